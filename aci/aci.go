@@ -41,6 +41,7 @@ import (
 	"github.com/docker/compose-cli/api/client"
 	"github.com/docker/compose-cli/api/containers"
 	"github.com/docker/compose-cli/api/context/store"
+	"github.com/docker/compose-cli/api/phase"
 )
 
 func createACIContainers(ctx context.Context, aciContext store.AciContext, groupDefinition containerinstance.ContainerGroup) error {
@@ -127,7 +128,9 @@ func createOrUpdateACIContainers(ctx context.Context, aciContext store.AciContex
 		}
 	}
 
+	end := phase.Start(ctx, phase.DeploymentPolling)
 	err = future.WaitForCompletionRef(ctx, containerGroupsClient.Client)
+	end(err)
 	if err != nil {
 		return err
 	}
@@ -141,6 +144,158 @@ func createOrUpdateACIContainers(ctx context.Context, aciContext store.AciContex
 	return err
 }
 
+const (
+	// statusTerminated is the ACI state of containers that have exited
+	statusTerminated = "Terminated"
+	// waitPollInterval is the delay between container group status checks
+	waitPollInterval = 2 * time.Second
+	// eventUnhealthy is the ACI event recorded when a liveness probe fails
+	eventUnhealthy = "Unhealthy"
+	// defaultProbePeriod is the ACI period of probes that don't set one
+	defaultProbePeriod = 10 * time.Second
+)
+
+// health states of running containers, a container without healthcheck having
+// none
+const (
+	healthNone      = ""
+	healthStarting  = "Waiting"
+	healthHealthy   = "Healthy"
+	healthUnhealthy = "Unhealthy"
+)
+
+// waitForRunningContainers polls the container group until all its containers
+// are running, and healthy when they define a healthcheck, or one of them has
+// terminated or is unhealthy
+func waitForRunningContainers(ctx context.Context, aciContext store.AciContext, containerGroupName string) error {
+	w := progress.ContextWriter(ctx)
+	for {
+		group, err := getACIContainerGroup(ctx, aciContext, containerGroupName)
+		if err != nil {
+			return err
+		}
+		running := true
+		if group.Containers != nil {
+			for _, c := range *group.Containers {
+				if c.Name == nil || *c.Name == convert.ComposeDNSSidecarName {
+					continue
+				}
+				switch status := convert.GetStatus(c, group); status {
+				case convert.StatusRunning:
+					switch health := containerHealth(c, time.Now()); health {
+					case healthNone:
+						w.Event(progress.NewEvent(*c.Name, progress.Done, "Running"))
+					case healthHealthy:
+						w.Event(progress.NewEvent(*c.Name, progress.Done, health))
+					case healthUnhealthy:
+						w.Event(progress.ErrorMessageEvent(*c.Name, health))
+						return fmt.Errorf("container %s of %s is unhealthy", *c.Name, containerGroupName)
+					default:
+						running = false
+						w.Event(progress.NewEvent(*c.Name, progress.Working, health))
+					}
+				case statusTerminated:
+					w.Event(progress.ErrorMessageEvent(*c.Name, status))
+					return fmt.Errorf("container %s of %s has terminated", *c.Name, containerGroupName)
+				default:
+					running = false
+					w.Event(progress.NewEvent(*c.Name, progress.Working, status))
+				}
+			}
+		}
+		if running {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(waitPollInterval):
+		}
+	}
+}
+
+// containerHealth returns the health state of a running container at the given
+// time. ACI doesn't report the health of containers, their healthcheck being
+// converted to a liveness probe which only records an event when it fails: a
+// container is healthy once a probe period has passed after the initial delay
+// without failure.
+func containerHealth(c containerinstance.Container, now time.Time) string {
+	probe := c.LivenessProbe
+	if probe == nil {
+		return healthNone
+	}
+	state := c.InstanceView.CurrentState
+	if state.StartTime == nil {
+		return healthStarting
+	}
+	started := state.StartTime.Time
+	if c.InstanceView.Events != nil {
+		for _, e := range *c.InstanceView.Events {
+			if e.Name != nil && *e.Name == eventUnhealthy && e.LastTimestamp != nil && !e.LastTimestamp.Before(started) {
+				return healthUnhealthy
+			}
+		}
+	}
+	firstProbe := started.Add(defaultProbePeriod)
+	if probe.PeriodSeconds != nil {
+		firstProbe = started.Add(time.Duration(*probe.PeriodSeconds) * time.Second)
+	}
+	if probe.InitialDelaySeconds != nil {
+		firstProbe = firstProbe.Add(time.Duration(*probe.InitialDelaySeconds) * time.Second)
+	}
+	if now.Before(firstProbe) {
+		return healthStarting
+	}
+	return healthHealthy
+}
+
+// waitForExitedContainer polls the container group until one of its
+// containers has terminated, or the one of the given service if set, and
+// returns its name and exit code
+func waitForExitedContainer(ctx context.Context, aciContext store.AciContext, containerGroupName string, service string) (string, int, error) {
+	w := progress.ContextWriter(ctx)
+	for {
+		group, err := getACIContainerGroup(ctx, aciContext, containerGroupName)
+		if err != nil {
+			return "", 0, err
+		}
+		if name, exitCode, ok := exitedContainer(group, service); ok {
+			w.Event(progress.NewEvent(name, progress.Done, fmt.Sprintf("Exited (%d)", exitCode)))
+			return name, exitCode, nil
+		}
+		select {
+		case <-ctx.Done():
+			return "", 0, ctx.Err()
+		case <-time.After(waitPollInterval):
+		}
+	}
+}
+
+// exitedContainer returns the name and exit code of a terminated container of
+// the group, of the given service if set
+func exitedContainer(group containerinstance.ContainerGroup, service string) (string, int, bool) {
+	if group.Containers == nil {
+		return "", 0, false
+	}
+	for _, c := range *group.Containers {
+		if c.Name == nil || *c.Name == convert.ComposeDNSSidecarName {
+			continue
+		}
+		if service != "" && *c.Name != service {
+			continue
+		}
+		if convert.GetStatus(c, group) != statusTerminated {
+			continue
+		}
+		var exitCode int
+		if c.InstanceView.CurrentState.ExitCode != nil {
+			exitCode = int(*c.InstanceView.CurrentState.ExitCode)
+		}
+		return *c.Name, exitCode, true
+	}
+	return "", 0, false
+}
+
 func getACIContainerGroup(ctx context.Context, aciContext store.AciContext, containerGroupName string) (containerinstance.ContainerGroup, error) {
 	containerGroupsClient, err := login.NewContainerGroupsClient(aciContext.SubscriptionID)
 	if err != nil {
@@ -203,6 +358,46 @@ func stopACIContainerGroup(ctx context.Context, aciContext store.AciContext, con
 	return err
 }
 
+func startACIContainerGroup(ctx context.Context, aciContext store.AciContext, containerGroupName string) error {
+	containerGroupsClient, err := login.NewContainerGroupsClient(aciContext.SubscriptionID)
+	if err != nil {
+		return fmt.Errorf("cannot get container group client: %v", err)
+	}
+
+	future, err := containerGroupsClient.Start(ctx, aciContext.ResourceGroup, containerGroupName)
+	if err != nil {
+		var aerr autorest.DetailedError
+		if ok := errors.As(err, &aerr); ok && aerr.StatusCode == http.StatusNotFound {
+			return api.ErrNotFound
+		}
+		return err
+	}
+	end := phase.Start(ctx, phase.DeploymentPolling)
+	err = future.WaitForCompletionRef(ctx, containerGroupsClient.Client)
+	end(err)
+	return err
+}
+
+func restartACIContainerGroup(ctx context.Context, aciContext store.AciContext, containerGroupName string) error {
+	containerGroupsClient, err := login.NewContainerGroupsClient(aciContext.SubscriptionID)
+	if err != nil {
+		return fmt.Errorf("cannot get container group client: %v", err)
+	}
+
+	future, err := containerGroupsClient.Restart(ctx, aciContext.ResourceGroup, containerGroupName)
+	if err != nil {
+		var aerr autorest.DetailedError
+		if ok := errors.As(err, &aerr); ok && aerr.StatusCode == http.StatusNotFound {
+			return api.ErrNotFound
+		}
+		return err
+	}
+	end := phase.Start(ctx, phase.DeploymentPolling)
+	err = future.WaitForCompletionRef(ctx, containerGroupsClient.Client)
+	end(err)
+	return err
+}
+
 func execACIContainer(ctx context.Context, aciContext store.AciContext, command, containerGroup string, containerName string) (c containerinstance.ContainerExecResponse, err error) {
 	containerClient, err := login.NewContainerClient(aciContext.SubscriptionID)
 	if err != nil {
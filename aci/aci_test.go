@@ -18,7 +18,11 @@ package aci
 
 import (
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2019-12-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/Azure/go-autorest/autorest/to"
 	"gotest.tools/v3/assert"
 )
 
@@ -26,3 +30,75 @@ func TestGetLinesWritten(t *testing.T) {
 	assert.Equal(t, 0, getBacktrackLines([]string{"Hello"}, 10))
 	assert.Equal(t, 3, getBacktrackLines([]string{"Hello", "world"}, 2))
 }
+
+func TestExitedContainer(t *testing.T) {
+	container := func(name, state string, exitCode int32) containerinstance.Container {
+		return containerinstance.Container{
+			Name: to.StringPtr(name),
+			ContainerProperties: &containerinstance.ContainerProperties{
+				InstanceView: &containerinstance.ContainerPropertiesInstanceView{
+					CurrentState: &containerinstance.ContainerState{
+						State:    to.StringPtr(state),
+						ExitCode: to.Int32Ptr(exitCode),
+					},
+				},
+			},
+		}
+	}
+	group := containerinstance.ContainerGroup{
+		ContainerGroupProperties: &containerinstance.ContainerGroupProperties{
+			Containers: &[]containerinstance.Container{
+				container("db", "Running", 0),
+				container("test", "Terminated", 3),
+			},
+		},
+	}
+
+	name, exitCode, ok := exitedContainer(group, "")
+	assert.Assert(t, ok)
+	assert.Equal(t, name, "test")
+	assert.Equal(t, exitCode, 3)
+
+	_, _, ok = exitedContainer(group, "db")
+	assert.Assert(t, !ok)
+}
+
+func TestContainerHealth(t *testing.T) {
+	started := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	probe := &containerinstance.ContainerProbe{InitialDelaySeconds: to.Int32Ptr(5), PeriodSeconds: to.Int32Ptr(10)}
+	unhealthy := func(at time.Time) *[]containerinstance.Event {
+		return &[]containerinstance.Event{{Name: to.StringPtr("Unhealthy"), LastTimestamp: &date.Time{Time: at}}}
+	}
+	testCases := []struct {
+		name   string
+		probe  *containerinstance.ContainerProbe
+		events *[]containerinstance.Event
+		now    time.Time
+		health string
+	}{
+		{name: "no healthcheck", now: started, health: healthNone},
+		{name: "first probe pending", probe: probe, now: started.Add(10 * time.Second), health: healthStarting},
+		{name: "first probe passed", probe: probe, now: started.Add(15 * time.Second), health: healthHealthy},
+		{name: "default period", probe: &containerinstance.ContainerProbe{}, now: started.Add(5 * time.Second), health: healthStarting},
+		{name: "probe failed", probe: probe, events: unhealthy(started.Add(5 * time.Second)), now: started.Add(6 * time.Second), health: healthUnhealthy},
+		{name: "probe failed before restart", probe: probe, events: unhealthy(started.Add(-time.Minute)), now: started.Add(15 * time.Second), health: healthHealthy},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := containerinstance.Container{
+				Name: to.StringPtr("web"),
+				ContainerProperties: &containerinstance.ContainerProperties{
+					LivenessProbe: tc.probe,
+					InstanceView: &containerinstance.ContainerPropertiesInstanceView{
+						CurrentState: &containerinstance.ContainerState{
+							State:     to.StringPtr("Running"),
+							StartTime: &date.Time{Time: started},
+						},
+						Events: tc.events,
+					},
+				},
+			}
+			assert.Equal(t, containerHealth(c, tc.now), tc.health)
+		})
+	}
+}
@@ -43,6 +43,7 @@ const (
 	backendType               = store.AciContextType
 	singleContainerTag        = "docker-single-container"
 	composeContainerTag       = "docker-compose-application"
+	composeConfigFilesTag     = "docker-compose-config-files"
 	dockerVolumeTag           = "docker-volume"
 	composeContainerSeparator = "_"
 )
@@ -155,6 +156,22 @@ func addTag(groupDefinition *containerinstance.ContainerGroup, tagName string) {
 	groupDefinition.Tags[tagName] = to.StringPtr(tagName)
 }
 
+// maxTagValueLength is the maximum length of Azure resource tag values
+const maxTagValueLength = 256
+
+// addConfigFilesTag tags the container group with the compose files it is
+// deployed from, unless they don't fit in a tag value
+func addConfigFilesTag(groupDefinition *containerinstance.ContainerGroup, configFiles []string) {
+	value := strings.Join(configFiles, ",")
+	if value == "" || len(value) > maxTagValueLength {
+		return
+	}
+	if groupDefinition.Tags == nil {
+		groupDefinition.Tags = make(map[string]*string, 1)
+	}
+	groupDefinition.Tags[composeConfigFilesTag] = to.StringPtr(value)
+}
+
 func getGroupAndContainerName(containerID string) (string, string) {
 	tokens := strings.Split(containerID, composeContainerSeparator)
 	groupName := tokens[0]
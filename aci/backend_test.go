@@ -18,8 +18,10 @@ package aci
 
 import (
 	"context"
+	"strings"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2019-12-01/containerinstance"
 	"github.com/stretchr/testify/mock"
 	"gotest.tools/v3/assert"
 
@@ -164,3 +166,13 @@ func (s *mockLoginService) GetValidToken() (oauth2.Token, string, error) {
 	args := s.Called()
 	return args.Get(0).(oauth2.Token), args.String(1), args.Error(2)
 }
+
+func TestAddConfigFilesTag(t *testing.T) {
+	var group containerinstance.ContainerGroup
+	addConfigFilesTag(&group, []string{"/src/compose.yaml", "/src/compose.prod.yaml"})
+	assert.Equal(t, *group.Tags[composeConfigFilesTag], "/src/compose.yaml,/src/compose.prod.yaml")
+
+	group = containerinstance.ContainerGroup{}
+	addConfigFilesTag(&group, []string{"/" + strings.Repeat("a", maxTagValueLength)})
+	assert.Assert(t, group.Tags == nil)
+}
@@ -20,8 +20,11 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2019-12-01/containerinstance"
 	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/cli/cli"
 	"github.com/docker/compose/v2/pkg/api"
 	"github.com/docker/compose/v2/pkg/progress"
 	"github.com/sirupsen/logrus"
@@ -30,6 +33,7 @@ import (
 
 	"github.com/docker/compose-cli/aci/convert"
 	"github.com/docker/compose-cli/aci/login"
+	"github.com/docker/compose-cli/api/containers"
 	"github.com/docker/compose-cli/api/context/store"
 	"github.com/docker/compose-cli/utils/formatter"
 )
@@ -59,27 +63,71 @@ func (cs *aciComposeService) Pull(ctx context.Context, project *types.Project, o
 }
 
 func (cs *aciComposeService) Create(ctx context.Context, project *types.Project, opts api.CreateOptions) error {
-	return api.ErrNotImplemented
+	return utils.UnsupportedCommand(ctx, "create", `ACI container groups are created running, the application can be deployed with "compose up"`)
 }
 
+// Start starts the containers of the stopped container group, ACI can't
+// start a single one
 func (cs *aciComposeService) Start(ctx context.Context, project *types.Project, options api.StartOptions) error {
-	return api.ErrNotImplemented
+	return progress.Run(ctx, func(ctx context.Context) error {
+		w := progress.ContextWriter(ctx)
+		w.Event(progress.StartingEvent(project.Name))
+		if err := startACIContainerGroup(ctx, cs.ctx, project.Name); err != nil {
+			w.Event(progress.ErrorEvent(project.Name))
+			return err
+		}
+		w.Event(progress.StartedEvent(project.Name))
+		return nil
+	})
 }
 
+// Restart restarts the containers of the container group, ACI can't restart
+// a single one
 func (cs *aciComposeService) Restart(ctx context.Context, project *types.Project, options api.RestartOptions) error {
-	return api.ErrNotImplemented
+	if len(options.Services) > 0 && len(options.Services) < len(project.Services)+len(project.DisabledServices) {
+		return fmt.Errorf("cannot restart services %s of compose application %q, ACI only restarts the entire application: %w",
+			strings.Join(options.Services, ", "), project.Name, api.ErrUnsupportedFlag)
+	}
+	return progress.Run(ctx, func(ctx context.Context) error {
+		w := progress.ContextWriter(ctx)
+		w.Event(progress.RestartingEvent(project.Name))
+		if err := restartACIContainerGroup(ctx, cs.ctx, project.Name); err != nil {
+			w.Event(progress.ErrorEvent(project.Name))
+			return err
+		}
+		w.Event(progress.StartedEvent(project.Name))
+		return nil
+	})
 }
 
+// Stop stops the containers of the container group, ACI can't stop a single
+// one nor set a timeout before killing them
 func (cs *aciComposeService) Stop(ctx context.Context, project *types.Project, options api.StopOptions) error {
-	return api.ErrNotImplemented
+	if len(options.Services) > 0 && len(options.Services) < len(project.Services)+len(project.DisabledServices) {
+		return fmt.Errorf("cannot stop services %s of compose application %q, ACI only stops the entire application: %w",
+			strings.Join(options.Services, ", "), project.Name, api.ErrUnsupportedFlag)
+	}
+	if options.Timeout != nil {
+		return fmt.Errorf("the ACI integration does not support setting a timeout to stop containers before killing them: %w", api.ErrUnsupportedFlag)
+	}
+	return progress.Run(ctx, func(ctx context.Context) error {
+		w := progress.ContextWriter(ctx)
+		w.Event(progress.StoppingEvent(project.Name))
+		if err := stopACIContainerGroup(ctx, cs.ctx, project.Name); err != nil {
+			w.Event(progress.ErrorEvent(project.Name))
+			return err
+		}
+		w.Event(progress.StoppedEvent(project.Name))
+		return nil
+	})
 }
 
 func (cs *aciComposeService) Pause(ctx context.Context, project string, options api.PauseOptions) error {
-	return api.ErrNotImplemented
+	return utils.UnsupportedCommand(ctx, "pause", `ACI containers can't be frozen, the application can be removed with "compose down"`)
 }
 
 func (cs *aciComposeService) UnPause(ctx context.Context, project string, options api.PauseOptions) error {
-	return api.ErrNotImplemented
+	return utils.UnsupportedCommand(ctx, "unpause", `ACI containers can't be frozen`)
 }
 
 func (cs *aciComposeService) Copy(ctx context.Context, project *types.Project, options api.CopyOptions) error {
@@ -91,7 +139,7 @@ func (cs *aciComposeService) Up(ctx context.Context, project *types.Project, opt
 		return err
 	}
 	return progress.Run(ctx, func(ctx context.Context) error {
-		return cs.up(ctx, project)
+		return cs.up(ctx, project, options.Start)
 	})
 }
 
@@ -101,10 +149,8 @@ func checkUnsupportedUpOptions(ctx context.Context, o api.UpOptions) error {
 		toCheck, expected interface{}
 		option            string
 	}{
-		{o.Start.CascadeStop, false, "abort-on-container-exit"},
 		{o.Create.RecreateDependencies, api.RecreateDiverged, "always-recreate-deps"},
 		{len(o.Start.AttachTo), 0, "attach-dependencies"},
-		{len(o.Start.ExitCodeFrom), 0, "exit-code-from"},
 		{o.Create.Recreate, api.RecreateDiverged, "force-recreate"},
 		{o.Create.QuietPull, false, "quiet-pull"},
 		{o.Create.RemoveOrphans, false, "remove-orphans"},
@@ -117,9 +163,17 @@ func checkUnsupportedUpOptions(ctx context.Context, o api.UpOptions) error {
 	return errs
 }
 
-func (cs *aciComposeService) up(ctx context.Context, project *types.Project) error {
+func (cs *aciComposeService) up(ctx context.Context, project *types.Project, options api.StartOptions) error {
 	logrus.Debugf("Up on project with name %q", project.Name)
 
+	if err := utils.CheckProfiles(project); err != nil {
+		return err
+	}
+	if deps := utils.DisabledDependencies(project); len(deps) > 0 {
+		// container groups are updated as a whole, restarting all containers
+		return fmt.Errorf(`option "up --no-deps" on context type ACI, as it would remove %s: %w`, strings.Join(deps, ", "), api.ErrUnsupportedFlag)
+	}
+
 	if err := autocreateFileshares(ctx, project); err != nil {
 		return err
 	}
@@ -130,7 +184,43 @@ func (cs *aciComposeService) up(ctx context.Context, project *types.Project) err
 	}
 
 	addTag(&groupDefinition, composeContainerTag)
-	return createOrUpdateACIContainers(ctx, cs.ctx, groupDefinition)
+	addConfigFilesTag(&groupDefinition, project.ComposeFiles)
+	if options.CascadeStop {
+		// exited containers must not be restarted to get their exit code
+		groupDefinition.RestartPolicy = containerinstance.Never
+	}
+	if err := createOrUpdateACIContainers(ctx, cs.ctx, groupDefinition); err != nil {
+		return err
+	}
+	if options.CascadeStop {
+		return cs.abortOnContainerExit(ctx, *groupDefinition.Name, options.ExitCodeFrom)
+	}
+	if !options.Wait {
+		return nil
+	}
+	return waitForRunningContainers(ctx, cs.ctx, *groupDefinition.Name)
+}
+
+// abortOnContainerExit stops the container group once a container has exited,
+// or the one of the exitCodeFrom service if set, and returns its exit code as
+// a status error.
+func (cs *aciComposeService) abortOnContainerExit(ctx context.Context, containerGroupName string, exitCodeFrom string) error {
+	container, exitCode, err := waitForExitedContainer(ctx, cs.ctx, containerGroupName, exitCodeFrom)
+	if err != nil {
+		return err
+	}
+	logrus.Debugf("container %s exited with code %d, stopping %s", container, exitCode, containerGroupName)
+	w := progress.ContextWriter(ctx)
+	w.Event(progress.StoppingEvent(containerGroupName))
+	if err := stopACIContainerGroup(ctx, cs.ctx, containerGroupName); err != nil {
+		w.Event(progress.ErrorEvent(containerGroupName))
+		return err
+	}
+	w.Event(progress.StoppedEvent(containerGroupName))
+	if exitCodeFrom != "" && exitCode != 0 {
+		return cli.StatusError{StatusCode: exitCode}
+	}
+	return nil
 }
 
 func (cs aciComposeService) warnKeepVolumeOnDown(ctx context.Context, projectName string) error {
@@ -235,10 +325,9 @@ func checkUnsupportedPsOptions(ctx context.Context, o api.PsOptions) error {
 	return utils.CheckUnsupported(ctx, nil, o.All, false, "ps", "all")
 }
 
+// List lists the compose applications with a running container, or all of
+// them with --all
 func (cs *aciComposeService) List(ctx context.Context, opts api.ListOptions) ([]api.Stack, error) {
-	if err := checkUnsupportedListOptions(ctx, opts); err != nil {
-		return nil, err
-	}
 	containerGroups, err := getACIContainerGroups(ctx, cs.ctx.SubscriptionID, cs.ctx.ResourceGroup)
 	if err != nil {
 		return nil, err
@@ -250,13 +339,18 @@ func (cs *aciComposeService) List(ctx context.Context, opts api.ListOptions) ([]
 			continue
 		}
 		state := api.RUNNING
+		running := false
 		for _, container := range *group.ContainerGroupProperties.Containers {
 			containerState := convert.GetStatus(container, group)
-			if containerState != api.RUNNING {
+			if containerState == api.RUNNING {
+				running = true
+			} else if state == api.RUNNING {
 				state = containerState
-				break
 			}
 		}
+		if !running && !opts.All {
+			continue
+		}
 		stacks = append(stacks, api.Stack{
 			ID:     *group.ID,
 			Name:   *group.Name,
@@ -266,20 +360,34 @@ func (cs *aciComposeService) List(ctx context.Context, opts api.ListOptions) ([]
 	return stacks, nil
 }
 
-func checkUnsupportedListOptions(ctx context.Context, o api.ListOptions) error {
-	return utils.CheckUnsupported(ctx, nil, o.All, false, "ls", "all")
+// ProjectConfigFiles returns the compose files of the compose applications,
+// as tagged on their container groups
+func (cs *aciComposeService) ProjectConfigFiles(ctx context.Context) (map[string][]string, error) {
+	containerGroups, err := getACIContainerGroups(ctx, cs.ctx.SubscriptionID, cs.ctx.ResourceGroup)
+	if err != nil {
+		return nil, err
+	}
+	configFiles := map[string][]string{}
+	for _, group := range containerGroups {
+		if files, ok := group.Tags[composeConfigFilesTag]; ok && files != nil && group.Name != nil {
+			configFiles[*group.Name] = strings.Split(*files, ",")
+		}
+	}
+	return configFiles, nil
 }
 
 func (cs *aciComposeService) Logs(ctx context.Context, projectName string, consumer api.LogConsumer, options api.LogOptions) error {
 	return api.ErrNotImplemented
 }
 
+// Convert prints the normalized compose model, as container groups can only
+// be converted with access to the storage accounts of the volumes
 func (cs *aciComposeService) Convert(ctx context.Context, project *types.Project, options api.ConvertOptions) ([]byte, error) {
-	return nil, api.ErrNotImplemented
+	return utils.MarshalProject(project, options.Format)
 }
 
 func (cs *aciComposeService) Kill(ctx context.Context, project *types.Project, options api.KillOptions) error {
-	return api.ErrNotImplemented
+	return utils.UnsupportedCommand(ctx, "kill", `ACI containers can't be sent signals, the application can be removed with "compose down"`)
 }
 
 func (cs *aciComposeService) RunOneOffContainer(ctx context.Context, project *types.Project, opts api.RunOptions) (int, error) {
@@ -290,19 +398,65 @@ func (cs *aciComposeService) Remove(ctx context.Context, project *types.Project,
 	return api.ErrNotImplemented
 }
 
+// Exec executes a binary in a service container, ACI doesn't accept arguments
 func (cs *aciComposeService) Exec(ctx context.Context, project string, opts api.RunOptions) (int, error) {
-	return 0, api.ErrNotImplemented
+	if err := checkUnsupportedExecOptions(ctx, opts); err != nil {
+		return 0, err
+	}
+	command := strings.Join(opts.Command, " ")
+	if err := verifyExecCommand(command); err != nil {
+		return 0, err
+	}
+	containerExecResponse, err := execACIContainer(ctx, cs.ctx, command, project, opts.Service)
+	if err != nil {
+		return 0, err
+	}
+	return 0, exec(context.Background(), *containerExecResponse.WebSocketURI, *containerExecResponse.Password, containers.ExecRequest{
+		Stdin:       opts.Stdin,
+		Stdout:      opts.Stdout,
+		Stderr:      opts.Stderr,
+		Command:     command,
+		Interactive: opts.Stdin != nil,
+		Tty:         opts.Tty,
+	})
+}
+
+func checkUnsupportedExecOptions(ctx context.Context, o api.RunOptions) error {
+	var errs error
+	errs = utils.CheckUnsupported(ctx, errs, len(o.Environment) > 0, false, "exec", "env")
+	errs = utils.CheckUnsupported(ctx, errs, o.WorkingDir, "", "exec", "workdir")
+	errs = utils.CheckUnsupported(ctx, errs, o.User, "", "exec", "user")
+	errs = utils.CheckUnsupported(ctx, errs, o.Privileged, false, "exec", "privileged")
+	errs = utils.CheckUnsupported(ctx, errs, o.Detach, false, "exec", "detach")
+	if o.Index > 1 {
+		errs = utils.CheckUnsupported(ctx, errs, o.Index, 1, "exec", "index")
+	}
+	return errs
 }
+
+// Top describes the containers of the services, ACI doesn't expose their processes
 func (cs *aciComposeService) Top(ctx context.Context, projectName string, services []string) ([]api.ContainerProcSummary, error) {
-	return nil, api.ErrNotImplemented
+	containers, err := cs.Ps(ctx, projectName, api.PsOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return utils.ContainersTop(containers, services), nil
 }
 
+// Events reports the changes of the containers, as ACI has no events API
 func (cs *aciComposeService) Events(ctx context.Context, project string, options api.EventsOptions) error {
-	return api.ErrNotImplemented
+	return utils.PollEvents(ctx, utils.EventsPollInterval, func(ctx context.Context) ([]api.ContainerSummary, error) {
+		return cs.Ps(ctx, project, api.PsOptions{})
+	}, options)
 }
 
+// Port returns the container group address, its FQDN if it has a DNS name
 func (cs *aciComposeService) Port(ctx context.Context, project string, service string, port int, options api.PortOptions) (string, int, error) {
-	return "", 0, api.ErrNotImplemented
+	containers, err := cs.Ps(ctx, project, api.PsOptions{})
+	if err != nil {
+		return "", 0, err
+	}
+	return utils.PublishedPort(containers, service, port, options)
 }
 
 func (cs *aciComposeService) Images(ctx context.Context, projectName string, options api.ImagesOptions) ([]api.ImageSummary, error) {
@@ -34,6 +34,7 @@ import (
 	"github.com/docker/compose-cli/aci/login"
 	"github.com/docker/compose-cli/api/containers"
 	"github.com/docker/compose-cli/api/context/store"
+	"github.com/docker/compose-cli/api/phase"
 )
 
 type aciContainerService struct {
@@ -113,7 +114,10 @@ func (cs *aciContainerService) Start(ctx context.Context, containerID string) er
 		return err
 	}
 
-	return future.WaitForCompletionRef(ctx, containerGroupsClient.Client)
+	end := phase.Start(ctx, phase.DeploymentPolling)
+	err = future.WaitForCompletionRef(ctx, containerGroupsClient.Client)
+	end(err)
+	return err
 }
 
 func (cs *aciContainerService) Stop(ctx context.Context, containerID string, timeout *uint32) error {
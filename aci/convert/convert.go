@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -28,12 +29,15 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2019-12-01/containerinstance"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/compose-spec/compose-go/types"
+	"github.com/containerd/containerd/platforms"
 	"github.com/docker/compose/v2/pkg/api"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 
 	"github.com/docker/compose-cli/aci/login"
 	"github.com/docker/compose-cli/api/containers"
 	"github.com/docker/compose-cli/api/context/store"
+	"github.com/docker/compose-cli/utils"
 	"github.com/docker/compose-cli/utils/formatter"
 )
 
@@ -44,6 +48,12 @@ const (
 	ComposeDNSSidecarName = "aci--dns--sidecar"
 
 	dnsSidecarImage = "docker/aci-hostnames-sidecar:1.0"
+	// aciPlatform is the platform of the containers run by ACI
+	aciPlatform = "linux/amd64"
+
+	// defaultGPUSku is the GPU SKU of services reserving GPUs, the one
+	// available in the most regions
+	defaultGPUSku = containerinstance.K80
 )
 
 // ToContainerGroup converts a compose project into a ACI container group
@@ -97,6 +107,23 @@ func ToContainerGroup(ctx context.Context, aciContext store.AciContext, p types.
 		if service.Labels != nil && len(service.Labels) > 0 {
 			return containerinstance.ContainerGroup{}, errors.New("ACI integration does not support labels in compose applications")
 		}
+		if service.Logging != nil {
+			logrus.Warnf("ACI integration does not support logging drivers, the logs of service %s are only available with docker logs", service.Name)
+		}
+		if len(service.Sysctls) > 0 {
+			var sysctls []string
+			for k := range service.Sysctls {
+				sysctls = append(sysctls, k)
+			}
+			sort.Strings(sysctls)
+			return containerinstance.ContainerGroup{}, fmt.Errorf("ACI integration does not support sysctls, set by service %s: %s", service.Name, strings.Join(sysctls, ", "))
+		}
+		if err := checkPlatform(service.Name, service.Platform); err != nil {
+			return containerinstance.ContainerGroup{}, err
+		}
+		if service.PullPolicy == types.PullPolicyNever {
+			return containerinstance.ContainerGroup{}, fmt.Errorf("ACI integration does not support pull_policy never on service %s, images are pulled when container groups start", service.Name)
+		}
 
 		containerPorts, serviceGroupPorts, serviceDomainName, err := convertPortsToAci(service)
 		if err != nil {
@@ -163,6 +190,22 @@ func getDNSSidecar(services types.Services) containerinstance.Container {
 
 type projectAciHelper types.Project
 
+// checkPlatform rejects the platforms of services other than the one of ACI
+// containers
+func checkPlatform(service string, platform string) error {
+	if platform == "" {
+		return nil
+	}
+	p, err := platforms.Parse(platform)
+	if err != nil {
+		return errors.Wrapf(err, "service %s: invalid platform %q", service, platform)
+	}
+	if platforms.Format(platforms.Normalize(p)) != aciPlatform {
+		return fmt.Errorf("ACI integration does not support platform %s of service %s, only %s containers can be run", platform, service, aciPlatform)
+	}
+	return nil
+}
+
 type serviceConfigAciHelper types.ServiceConfig
 
 func (s serviceConfigAciHelper) getAciContainer() (containerinstance.Container, error) {
@@ -252,6 +295,16 @@ func (s serviceConfigAciHelper) getResourceRequestsLimits() (*containerinstance.
 			CPU:        to.Float64Ptr(cpuLimit),
 		},
 	}
+	if device, ok := utils.GPUReservation(types.ServiceConfig(s)); ok {
+		count := device.Count
+		if count <= 0 {
+			count = 1
+		}
+		resources.Requests.Gpu = &containerinstance.GpuResource{
+			Count: to.Int32Ptr(int32(count)),
+			Sku:   defaultGPUSku,
+		}
+	}
 	return &resources, nil
 }
 
@@ -334,6 +334,55 @@ func TestLabelsErrorMessage(t *testing.T) {
 	assert.Error(t, err, "ACI integration does not support labels in compose applications")
 }
 
+func TestPullPolicyNeverErrorMessage(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:       "service1",
+				Image:      "image1",
+				PullPolicy: types.PullPolicyNever,
+			},
+		},
+	}
+
+	_, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	assert.Error(t, err, "ACI integration does not support pull_policy never on service service1, images are pulled when container groups start")
+}
+
+func TestSysctlsErrorMessage(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:    "service1",
+				Image:   "image1",
+				Sysctls: types.Mapping{"net.core.somaxconn": "1024", "kernel.shmmax": "1024"},
+			},
+		},
+	}
+
+	_, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	assert.Error(t, err, "ACI integration does not support sysctls, set by service service1: kernel.shmmax, net.core.somaxconn")
+}
+
+func TestPlatformErrorMessage(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:     "service1",
+				Image:    "image1",
+				Platform: "linux/arm64",
+			},
+		},
+	}
+
+	_, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	assert.Error(t, err, "ACI integration does not support platform linux/arm64 of service service1, only linux/amd64 containers can be run")
+
+	project.Services[0].Platform = "linux/x86_64"
+	_, err = ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	assert.NilError(t, err)
+}
+
 func TestComposeContainerGroupToContainerWithDomainName(t *testing.T) {
 	project := types.Project{
 		Services: []types.ServiceConfig{
@@ -446,6 +495,38 @@ func TestComposeContainerGroupToContainerResourceRequests(t *testing.T) {
 	assert.Equal(t, *limits.MemoryInGB, float64(0.1))
 }
 
+func TestComposeContainerGroupToContainerGPURequests(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:  "service1",
+				Image: "image1",
+				Deploy: &types.DeployConfig{
+					Resources: types.Resources{
+						Reservations: &types.Resource{
+							Devices: []types.DeviceRequest{{Capabilities: []string{"gpu"}, Count: 2}},
+						},
+					},
+				},
+			},
+			{
+				Name:    "service2",
+				Image:   "image2",
+				Runtime: "nvidia",
+			},
+		},
+	}
+
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	assert.NilError(t, err)
+
+	gpu := *((*group.Containers)[0]).Resources.Requests.Gpu
+	assert.Equal(t, *gpu.Count, int32(2))
+	assert.Equal(t, gpu.Sku, containerinstance.K80)
+	gpu = *((*group.Containers)[1]).Resources.Requests.Gpu
+	assert.Equal(t, *gpu.Count, int32(1))
+}
+
 func TestComposeContainerGroupToContainerResourceRequestsAndLimits(t *testing.T) {
 	project := types.Project{
 		Services: []types.ServiceConfig{
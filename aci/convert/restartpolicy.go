@@ -17,8 +17,12 @@
 package convert
 
 import (
+	"strings"
+
 	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2019-12-01/containerinstance"
+	"github.com/compose-spec/compose-go/types"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 
 	"github.com/docker/compose-cli/api/containers"
 )
@@ -29,22 +33,54 @@ func (p projectAciHelper) getRestartPolicy() (containerinstance.ContainerGroupRe
 		alreadySpecified := false
 		restartPolicyCondition = containerinstance.Always
 		for _, service := range p.Services {
-			if service.Deploy != nil &&
-				service.Deploy.RestartPolicy != nil {
-				if !alreadySpecified {
-					alreadySpecified = true
-					restartPolicyCondition = toAciRestartPolicy(service.Deploy.RestartPolicy.Condition)
-				}
-				if alreadySpecified && restartPolicyCondition != toAciRestartPolicy(service.Deploy.RestartPolicy.Condition) {
-					return "", errors.New("ACI integration does not support specifying different restart policies on services in the same compose application")
-				}
-
+			condition, ok := restartCondition(service)
+			if !ok {
+				continue
+			}
+			if !alreadySpecified {
+				alreadySpecified = true
+				restartPolicyCondition = toAciRestartPolicy(condition)
+			}
+			if alreadySpecified && restartPolicyCondition != toAciRestartPolicy(condition) {
+				return "", errors.New("ACI integration does not support specifying different restart policies on services in the same compose application")
 			}
 		}
 	}
 	return restartPolicyCondition, nil
 }
 
+// restartCondition returns the restart policy condition of the service, set
+// by its deploy restart policy or else by its restart attribute. Container
+// groups restart without delay nor limit on the number of attempts.
+func restartCondition(service types.ServiceConfig) (string, bool) {
+	if service.Deploy != nil && service.Deploy.RestartPolicy != nil {
+		policy := service.Deploy.RestartPolicy
+		if policy.Delay != nil || policy.MaxAttempts != nil || policy.Window != nil {
+			logrus.Warnf("ACI integration does not support the delay, max_attempts and window of restart policies, they are ignored on service %s", service.Name)
+		}
+		if policy.Condition == "" {
+			return containers.RestartPolicyAny, true
+		}
+		return policy.Condition, true
+	}
+	if service.Restart == "" {
+		return "", false
+	}
+	name := strings.SplitN(service.Restart, ":", 2)
+	switch name[0] {
+	case containers.RestartPolicyRunNo:
+		return containers.RestartPolicyNone, true
+	case containers.RestartPolicyRunAlways, "unless-stopped":
+		return containers.RestartPolicyAny, true
+	case containers.RestartPolicyOnFailure:
+		if len(name) > 1 {
+			logrus.Warnf("ACI integration does not limit the restart attempts, on-failure:%s is handled like on-failure on service %s", name[1], service.Name)
+		}
+		return containers.RestartPolicyOnFailure, true
+	}
+	return service.Restart, true
+}
+
 func toAciRestartPolicy(restartPolicy string) containerinstance.ContainerGroupRestartPolicy {
 	switch restartPolicy {
 	case containers.RestartPolicyNone:
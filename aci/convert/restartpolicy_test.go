@@ -111,6 +111,55 @@ func TestComposeInconsistentMultiContainerRestartPolicy(t *testing.T) {
 	assert.Error(t, err, "ACI integration does not support specifying different restart policies on services in the same compose application")
 }
 
+func TestComposeServiceRestart(t *testing.T) {
+	for restart, expected := range map[string]containerinstance.ContainerGroupRestartPolicy{
+		"no":             containerinstance.Never,
+		"always":         containerinstance.Always,
+		"unless-stopped": containerinstance.Always,
+		"on-failure":     containerinstance.OnFailure,
+		"on-failure:3":   containerinstance.OnFailure,
+	} {
+		project := types.Project{
+			Services: []types.ServiceConfig{
+				{
+					Name:    "service1",
+					Image:   "image1",
+					Restart: restart,
+				},
+				{
+					Name:  "service2",
+					Image: "image2",
+				},
+			},
+		}
+
+		group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+		assert.NilError(t, err)
+		assert.Equal(t, group.RestartPolicy, expected, restart)
+	}
+
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:    "service1",
+				Image:   "image1",
+				Restart: "no",
+			},
+			{
+				Name:  "service2",
+				Image: "image2",
+				Deploy: &types.DeployConfig{
+					RestartPolicy: &types.RestartPolicy{
+						Condition: "on-failure",
+					},
+				},
+			},
+		},
+	}
+	_, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	assert.Error(t, err, "ACI integration does not support specifying different restart policies on services in the same compose application")
+}
+
 func TestComposeSingleContainerGroupToContainerDefaultRestartPolicy(t *testing.T) {
 	project := types.Project{
 		Services: []types.ServiceConfig{
@@ -19,13 +19,15 @@ package convert
 import (
 	"encoding/base64"
 	"fmt"
-	"os"
 	"path"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2019-12-01/containerinstance"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
 	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/utils"
 )
 
 const (
@@ -43,7 +45,8 @@ func (p projectAciHelper) getAciSecretVolumes() ([]containerinstance.Volume, err
 	for _, svc := range p.Services {
 		squashedTargetVolumes := make(map[string]containerinstance.Volume)
 		for _, scr := range svc.Secrets {
-			data, err := os.ReadFile(p.Secrets[scr.Source].File)
+			project := types.Project(p)
+			data, err := utils.SecretContent(&project, scr.Source, p.Secrets[scr.Source])
 			if err != nil {
 				return secretVolumes, err
 			}
@@ -0,0 +1,112 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package build
+
+import (
+	"encoding/json"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// bakeConfig is the JSON definition of a `docker buildx bake` execution plan
+type bakeConfig struct {
+	Groups  map[string]bakeGroup  `json:"group"`
+	Targets map[string]bakeTarget `json:"target"`
+}
+
+type bakeGroup struct {
+	Targets []string `json:"targets"`
+}
+
+type bakeTarget struct {
+	Context    string            `json:"context"`
+	Dockerfile string            `json:"dockerfile,omitempty"`
+	Args       map[string]string `json:"args,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Tags       []string          `json:"tags"`
+	Target     string            `json:"target,omitempty"`
+	Platforms  []string          `json:"platforms,omitempty"`
+	CacheFrom  []string          `json:"cache-from,omitempty"`
+	CacheTo    []string          `json:"cache-to,omitempty"`
+	SSH        []string          `json:"ssh,omitempty"`
+	Secrets    []string          `json:"secret,omitempty"`
+	NoCache    bool              `json:"no-cache,omitempty"`
+	Pull       bool              `json:"pull,omitempty"`
+}
+
+// BakeFile returns the bake definition building the images of the given
+// services in a single execution plan, as a "default" group with one target
+// per service. The SSH and build cache flag values are forwarded to the
+// services like with compose build --ssh, --cache-from and --cache-to, and
+// the services are given the secret specs set for them.
+func BakeFile(project *types.Project, services types.Services, sshFlags []string, secrets map[string][]string, cacheFlags BuildCache, options api.BuildOptions) ([]byte, error) {
+	config := bakeConfig{
+		Groups:  map[string]bakeGroup{"default": {Targets: []string{}}},
+		Targets: map[string]bakeTarget{},
+	}
+	for _, service := range services {
+		if service.Build == nil {
+			continue
+		}
+		ssh, err := SSH(project, service, sshFlags)
+		if err != nil {
+			return nil, err
+		}
+		cache, err := Cache(service, cacheFlags)
+		if err != nil {
+			return nil, err
+		}
+		target := bakeTarget{
+			Args:      buildArgs(project, service, options),
+			Labels:    service.Build.Labels,
+			Tags:      []string{ImageName(project, service)},
+			Target:    service.Build.Target,
+			CacheFrom: cache.From,
+			CacheTo:   cache.To,
+			SSH:       ssh,
+			Secrets:   secrets[service.Name],
+			NoCache:   options.NoCache,
+			Pull:      options.Pull,
+		}
+		if service.Platform != "" {
+			target.Platforms = []string{service.Platform}
+		}
+		target.Context, target.Dockerfile = contextAndDockerfile(project, service)
+		group := config.Groups["default"]
+		group.Targets = append(group.Targets, service.Name)
+		config.Groups["default"] = group
+		config.Targets[service.Name] = target
+	}
+	return json.MarshalIndent(config, "", "  ")
+}
+
+// BakeArgs returns the arguments of the `docker buildx bake` command running
+// the execution plan of the given bake file, with the given builder unless
+// empty.
+func BakeArgs(file string, builder string, options api.BuildOptions) []string {
+	args := []string{"buildx", "bake", "--file", file, "--load"}
+	if builder != "" {
+		args = append(args, "--builder", builder)
+	}
+	if options.Quiet {
+		args = append(args, "--progress", "quiet")
+	} else if options.Progress != "" {
+		args = append(args, "--progress", options.Progress)
+	}
+	return args
+}
@@ -0,0 +1,86 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package build
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"gotest.tools/v3/assert"
+)
+
+func TestBakeFile(t *testing.T) {
+	project := &types.Project{Name: "demo", WorkingDir: "/src"}
+	services := types.Services{
+		{
+			Name:     "api",
+			Platform: "linux/arm64",
+			Build: &types.BuildConfig{
+				Context:   "api",
+				Args:      types.NewMappingWithEquals([]string{"MODE=prod"}),
+				CacheFrom: types.StringList{"demo/api:cache"},
+			},
+		},
+		{Name: "db", Image: "mysql"},
+		{
+			Name:  "web",
+			Image: "demo/web",
+			Build: &types.BuildConfig{Context: "web", Dockerfile: "Dockerfile.web"},
+		},
+	}
+
+	b, err := BakeFile(project, services, []string{"default"}, map[string][]string{"api": {"id=token,src=/run/token"}}, BuildCache{To: []string{"type=inline"}}, api.BuildOptions{NoCache: true})
+	assert.NilError(t, err)
+	var config bakeConfig
+	assert.NilError(t, json.Unmarshal(b, &config))
+	assert.DeepEqual(t, config, bakeConfig{
+		Groups: map[string]bakeGroup{"default": {Targets: []string{"api", "web"}}},
+		Targets: map[string]bakeTarget{
+			"api": {
+				Context:    "/src/api",
+				Dockerfile: "/src/api/Dockerfile",
+				Args:       map[string]string{"MODE": "prod"},
+				Tags:       []string{"demo_api"},
+				Platforms:  []string{"linux/arm64"},
+				CacheFrom:  []string{"demo/api:cache"},
+				CacheTo:    []string{"type=inline"},
+				SSH:        []string{"default"},
+				Secrets:    []string{"id=token,src=/run/token"},
+				NoCache:    true,
+			},
+			"web": {
+				Context:    "/src/web",
+				Dockerfile: "/src/web/Dockerfile.web",
+				Tags:       []string{"demo/web"},
+				CacheTo:    []string{"type=inline"},
+				SSH:        []string{"default"},
+				NoCache:    true,
+			},
+		},
+	})
+}
+
+func TestBakeArgs(t *testing.T) {
+	assert.DeepEqual(t, BakeArgs("bake.json", "", api.BuildOptions{Progress: "plain"}),
+		[]string{"buildx", "bake", "--file", "bake.json", "--load", "--progress", "plain"})
+	assert.DeepEqual(t, BakeArgs("bake.json", "", api.BuildOptions{Progress: "plain", Quiet: true}),
+		[]string{"buildx", "bake", "--file", "bake.json", "--load", "--progress", "quiet"})
+	assert.DeepEqual(t, BakeArgs("bake.json", ContainerizedBuilder, api.BuildOptions{}),
+		[]string{"buildx", "bake", "--file", "bake.json", "--load", "--builder", "compose-builder"})
+}
@@ -0,0 +1,127 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package build
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+// cacheTypes are the BuildKit cache backends
+var cacheTypes = map[string]bool{
+	"registry": true,
+	"local":    true,
+	"inline":   true,
+	"gha":      true,
+	"s3":       true,
+	"azblob":   true,
+}
+
+// BuildCache is the build cache of a service image: the cache sources it's
+// imported from and the exports it's written to, in the format of the
+// --cache-from and --cache-to flags of buildx, either an image reference or
+// comma-separated key=value attributes including the type of the cache.
+type BuildCache struct {
+	From []string
+	To   []string
+}
+
+// Cache returns the build cache of the service, from the cache_from and
+// cache_to attributes of its build section followed by the --cache-from and
+// --cache-to flag values.
+func Cache(service types.ServiceConfig, flags BuildCache) (BuildCache, error) {
+	if service.Build == nil {
+		return BuildCache{}, nil
+	}
+	cacheTo, err := utils.BuildCacheTo(service.Build)
+	if err != nil {
+		return BuildCache{}, fmt.Errorf("service %q: %w", service.Name, err)
+	}
+	cache := BuildCache{
+		From: append(append([]string{}, service.Build.CacheFrom...), flags.From...),
+		To:   append(cacheTo, flags.To...),
+	}
+	for _, entries := range [][]string{cache.From, cache.To} {
+		for _, entry := range entries {
+			if _, err := cacheType(entry); err != nil {
+				return BuildCache{}, fmt.Errorf("service %q: %w", service.Name, err)
+			}
+		}
+	}
+	return cache, nil
+}
+
+// RequiresBuildx reports whether the image has to be built with buildx, the
+// compose library neither exporting the build cache nor importing it from
+// cache sources given by their attributes rather than by an image reference.
+func (c BuildCache) RequiresBuildx() bool {
+	if len(c.To) > 0 {
+		return true
+	}
+	for _, entry := range c.From {
+		if strings.Contains(entry, "=") {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresContainerizedBuilder reports whether the build cache is exported
+// to a backend the default builder of the engine can't write to, the inline
+// cache only being embedded in the image.
+func (c BuildCache) RequiresContainerizedBuilder() bool {
+	for _, entry := range c.To {
+		if t, _ := cacheType(entry); t != "inline" {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheType returns the type of the cache entry, registry for image
+// references.
+func cacheType(entry string) (string, error) {
+	fields, err := csv.NewReader(strings.NewReader(entry)).Read()
+	if err != nil {
+		return "", fmt.Errorf("invalid build cache %q: %w", entry, err)
+	}
+	if !strings.Contains(entry, "=") {
+		return "registry", nil
+	}
+	var t string
+	for _, field := range fields {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return "", fmt.Errorf("invalid build cache %q, %q is not a key=value attribute", entry, field)
+		}
+		if strings.ToLower(kv[0]) == "type" {
+			t = kv[1]
+		}
+	}
+	if t == "" {
+		return "", fmt.Errorf("invalid build cache %q, the type is required", entry)
+	}
+	if !cacheTypes[t] {
+		return "", fmt.Errorf("invalid build cache %q, unsupported type %s", entry, t)
+	}
+	return t, nil
+}
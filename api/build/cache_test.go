@@ -0,0 +1,87 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package build
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+func TestCache(t *testing.T) {
+	service := types.ServiceConfig{
+		Name: "app",
+		Build: &types.BuildConfig{
+			Context:   ".",
+			CacheFrom: types.StringList{"demo/app:cache"},
+			Extensions: map[string]interface{}{
+				"extensions": map[string]interface{}{
+					utils.BuildCacheToExtension: []interface{}{"type=inline"},
+				},
+			},
+		},
+	}
+
+	cache, err := Cache(service, BuildCache{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cache, BuildCache{From: []string{"demo/app:cache"}, To: []string{"type=inline"}})
+	assert.Assert(t, cache.RequiresBuildx())
+	assert.Assert(t, !cache.RequiresContainerizedBuilder())
+
+	cache, err = Cache(service, BuildCache{From: []string{"type=local,src=/tmp/cache"}, To: []string{"type=local,dest=/tmp/cache,mode=max"}})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cache, BuildCache{
+		From: []string{"demo/app:cache", "type=local,src=/tmp/cache"},
+		To:   []string{"type=inline", "type=local,dest=/tmp/cache,mode=max"},
+	})
+	assert.Assert(t, cache.RequiresContainerizedBuilder())
+
+	cache = BuildCache{From: []string{"demo/app:cache"}}
+	assert.Assert(t, !cache.RequiresBuildx())
+	cache = BuildCache{From: []string{"type=registry,ref=demo/app:cache"}}
+	assert.Assert(t, cache.RequiresBuildx())
+
+	_, err = Cache(service, BuildCache{To: []string{"dest=/tmp/cache"}})
+	assert.Error(t, err, `service "app": invalid build cache "dest=/tmp/cache", the type is required`)
+	_, err = Cache(service, BuildCache{To: []string{"type=ftp,dest=/tmp/cache"}})
+	assert.Error(t, err, `service "app": invalid build cache "type=ftp,dest=/tmp/cache", unsupported type ftp`)
+	_, err = Cache(service, BuildCache{From: []string{"type=local,/tmp/cache"}})
+	assert.Error(t, err, `service "app": invalid build cache "type=local,/tmp/cache", "/tmp/cache" is not a key=value attribute`)
+
+	cache, err = Cache(types.ServiceConfig{Name: "db", Image: "mysql"}, BuildCache{To: []string{"type=inline"}})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cache, BuildCache{})
+}
+
+func TestCacheBuildxArgs(t *testing.T) {
+	project := &types.Project{Name: "demo", WorkingDir: "/src"}
+	service := types.ServiceConfig{Name: "app", Build: &types.BuildConfig{Context: "."}}
+	cache := BuildCache{From: []string{"type=local,src=/tmp/cache"}, To: []string{"type=local,dest=/tmp/cache"}}
+
+	args := BuildxArgs(project, service, nil, nil, cache, ContainerizedBuilder, api.BuildOptions{})
+	assert.DeepEqual(t, args, []string{
+		"buildx", "build", "--builder", "compose-builder", "--load", "--tag", "demo_app",
+		"--file", "/src/Dockerfile",
+		"--cache-from", "type=local,src=/tmp/cache",
+		"--cache-to", "type=local,dest=/tmp/cache",
+		"/src",
+	})
+}
@@ -0,0 +1,108 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package build
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/compose/v2/pkg/api"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+// ContainerizedBuilder is the containerized buildx builder images are built
+// with for several platforms or exporting their build cache, the default
+// builder of the engine only building images for one platform and only
+// embedding their cache
+const ContainerizedBuilder = "compose-builder"
+
+// Platforms returns the normalized platforms to build the service image for,
+// from the given --platforms flag values or else from its build section. The
+// platform of the service, if set, must be one of them.
+func Platforms(service types.ServiceConfig, flags []string) ([]string, error) {
+	if service.Build == nil {
+		return nil, nil
+	}
+	specs := flags
+	if len(specs) == 0 {
+		var err error
+		if specs, err = utils.BuildPlatforms(service.Build); err != nil {
+			return nil, fmt.Errorf("service %q: %w", service.Name, err)
+		}
+	}
+	var result []string
+	seen := map[string]bool{}
+	for _, spec := range specs {
+		p, err := platforms.Parse(spec)
+		if err != nil {
+			return nil, fmt.Errorf("service %q: invalid build platform %q: %w", service.Name, spec, err)
+		}
+		normalized := platforms.Format(platforms.Normalize(p))
+		if !seen[normalized] {
+			seen[normalized] = true
+			result = append(result, normalized)
+		}
+	}
+	if service.Platform != "" && len(result) > 0 {
+		p, err := platforms.Parse(service.Platform)
+		if err != nil {
+			return nil, fmt.Errorf("service %q: invalid platform %q: %w", service.Name, service.Platform, err)
+		}
+		if !seen[platforms.Format(platforms.Normalize(p))] {
+			return nil, fmt.Errorf("service %q: platform %s is not one of the build platforms %s", service.Name, service.Platform, strings.Join(result, ", "))
+		}
+	}
+	return result, nil
+}
+
+// MultiPlatformBuildxArgs returns the arguments of the `docker buildx build`
+// command building the service image for the given platforms with the
+// builder, pushing the image and its manifest list with push. Otherwise the
+// image is only kept in the build cache, the engine not storing images for
+// several platforms.
+func MultiPlatformBuildxArgs(project *types.Project, service types.ServiceConfig, ssh []string, secrets []string, cache BuildCache, platforms []string, builder string, push bool, options api.BuildOptions) []string {
+	output := []string{"--builder", builder}
+	if push {
+		output = append(output, "--push")
+	}
+	return buildxArgs(output, project, service, ssh, secrets, cache, strings.Join(platforms, ","), options)
+}
+
+// CreateBuilderArgs returns the arguments of the `docker buildx create`
+// command creating the containerized builder with the given name
+func CreateBuilderArgs(name string) []string {
+	return []string{"buildx", "create", "--name", name, "--driver", "docker-container"}
+}
+
+// HasBuilder reports whether the output of `docker buildx ls` lists the
+// builder, builders being listed unindented, the current one with a '*'
+// suffix, followed by their indented nodes.
+func HasBuilder(ls []byte, name string) bool {
+	for _, line := range strings.Split(string(ls), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(line, " ") {
+			continue
+		}
+		if strings.TrimSuffix(fields[0], "*") == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package build
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+func TestPlatforms(t *testing.T) {
+	service := types.ServiceConfig{
+		Name: "app",
+		Build: &types.BuildConfig{
+			Context: ".",
+			Extensions: map[string]interface{}{
+				"extensions": map[string]interface{}{
+					utils.BuildPlatformsExtension: []interface{}{"linux/amd64", "linux/arm64/v8", "linux/x86_64"},
+				},
+			},
+		},
+	}
+
+	platforms, err := Platforms(service, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, platforms, []string{"linux/amd64", "linux/arm64"})
+
+	platforms, err = Platforms(service, []string{"linux/arm/v7"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, platforms, []string{"linux/arm/v7"})
+
+	service.Platform = "linux/arm64"
+	_, err = Platforms(service, nil)
+	assert.NilError(t, err)
+	service.Platform = "linux/s390x"
+	_, err = Platforms(service, nil)
+	assert.Error(t, err, `service "app": platform linux/s390x is not one of the build platforms linux/amd64, linux/arm64`)
+
+	_, err = Platforms(service, []string{"linux/"})
+	assert.ErrorContains(t, err, `service "app": invalid build platform "linux/"`)
+
+	platforms, err = Platforms(types.ServiceConfig{Name: "db", Image: "mysql"}, []string{"linux/amd64"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(platforms), 0)
+}
+
+func TestMultiPlatformBuildxArgs(t *testing.T) {
+	project := &types.Project{Name: "demo", WorkingDir: "/src"}
+	service := types.ServiceConfig{
+		Name:  "app",
+		Image: "registry.example.com/app:1.0",
+		Build: &types.BuildConfig{Context: "."},
+	}
+
+	args := MultiPlatformBuildxArgs(project, service, nil, nil, BuildCache{}, []string{"linux/amd64", "linux/arm64"}, ContainerizedBuilder, true, api.BuildOptions{})
+	assert.DeepEqual(t, args, []string{
+		"buildx", "build", "--builder", "compose-builder", "--push", "--tag", "registry.example.com/app:1.0",
+		"--file", "/src/Dockerfile",
+		"--platform", "linux/amd64,linux/arm64",
+		"/src",
+	})
+
+	args = MultiPlatformBuildxArgs(project, service, nil, nil, BuildCache{}, []string{"linux/amd64", "linux/arm64"}, "custom", false, api.BuildOptions{Pull: true})
+	assert.DeepEqual(t, args, []string{
+		"buildx", "build", "--builder", "custom", "--tag", "registry.example.com/app:1.0",
+		"--file", "/src/Dockerfile",
+		"--platform", "linux/amd64,linux/arm64",
+		"--pull",
+		"/src",
+	})
+}
+
+func TestHasBuilder(t *testing.T) {
+	ls := []byte(`NAME/NODE          DRIVER/ENDPOINT             STATUS   PLATFORMS
+compose-builder    docker-container
+  compose-builder0 unix:///var/run/docker.sock inactive
+default *                 docker
+  default                 default                     running  linux/amd64, linux/386
+`)
+	assert.Assert(t, HasBuilder(ls, ContainerizedBuilder))
+	assert.Assert(t, HasBuilder(ls, "default"))
+	assert.Assert(t, !HasBuilder(ls, "compose-builder0"))
+	assert.Assert(t, !HasBuilder([]byte("NAME/NODE DRIVER/ENDPOINT STATUS PLATFORMS\n"), ContainerizedBuilder))
+}
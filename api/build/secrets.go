@@ -0,0 +1,78 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/compose-spec/compose-go/types"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+// SecretSpecs returns the specs of the secrets available to the build of the
+// service for `RUN --mount=type=secret` instructions, in the format of the
+// --secret flag of buildx, id=<id>,src=<file>. Secrets are identified by
+// their target, or else by their name. The secrets sourced from environment
+// variables are written to a temporary directory, removed by the returned
+// function.
+func SecretSpecs(project *types.Project, service types.ServiceConfig) ([]string, func(), error) {
+	var dir string
+	cleanup := func() {
+		if dir != "" {
+			_ = os.RemoveAll(dir)
+		}
+	}
+	secrets, err := utils.BuildSecrets(service.Build)
+	if err != nil {
+		return nil, cleanup, fmt.Errorf("service %q: %w", service.Name, err)
+	}
+	specs := make([]string, 0, len(secrets))
+	for _, s := range secrets {
+		secret, ok := project.Secrets[s.Source]
+		if !ok {
+			return nil, cleanup, fmt.Errorf("service %q: build secret %q is not defined", service.Name, s.Source)
+		}
+		if secret.External.External {
+			return nil, cleanup, fmt.Errorf("service %q: external secret %q can't be used by builds", service.Name, s.Source)
+		}
+		id := s.Target
+		if id == "" {
+			id = s.Source
+		}
+		file := resolvePath(project.WorkingDir, secret.File)
+		if _, ok := utils.SecretEnvironment(secret); ok {
+			content, err := utils.SecretContent(project, s.Source, secret)
+			if err != nil {
+				return nil, cleanup, err
+			}
+			if dir == "" {
+				if dir, err = os.MkdirTemp("", "compose-build-secrets-"); err != nil {
+					return nil, cleanup, err
+				}
+			}
+			file = filepath.Join(dir, s.Source)
+			if err := os.WriteFile(file, content, 0o600); err != nil {
+				return nil, cleanup, err
+			}
+		}
+		specs = append(specs, fmt.Sprintf("id=%s,src=%s", id, file))
+	}
+	return specs, cleanup, nil
+}
@@ -0,0 +1,82 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+func TestSecretSpecs(t *testing.T) {
+	project := &types.Project{
+		Name:        "demo",
+		WorkingDir:  "/src",
+		Environment: map[string]string{"TOKEN": "s3cr3t"},
+		Secrets: types.Secrets{
+			"key":      types.SecretConfig{File: "keys/api.pem"},
+			"token":    types.SecretConfig{Extensions: map[string]interface{}{utils.SecretEnvironmentExtension: "TOKEN"}},
+			"registry": types.SecretConfig{External: types.External{External: true}},
+		},
+	}
+	service := types.ServiceConfig{
+		Name: "app",
+		Build: &types.BuildConfig{
+			Context: ".",
+			Extensions: map[string]interface{}{
+				utils.BuildSecretsExtension: []interface{}{
+					map[string]interface{}{"source": "key", "target": "api_key"},
+					"token",
+				},
+			},
+		},
+	}
+
+	specs, cleanup, err := SecretSpecs(project, service)
+	assert.NilError(t, err)
+	assert.Equal(t, len(specs), 2)
+	assert.Equal(t, specs[0], "id=api_key,src=/src/keys/api.pem")
+	assert.Assert(t, strings.HasPrefix(specs[1], "id=token,src="))
+	file := strings.TrimPrefix(specs[1], "id=token,src=")
+	content, err := os.ReadFile(file)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "s3cr3t")
+	cleanup()
+	_, err = os.Stat(filepath.Dir(file))
+	assert.Assert(t, os.IsNotExist(err))
+
+	service.Build.Extensions[utils.BuildSecretsExtension] = []interface{}{"registry"}
+	_, cleanup, err = SecretSpecs(project, service)
+	cleanup()
+	assert.Error(t, err, `service "app": external secret "registry" can't be used by builds`)
+
+	service.Build.Extensions[utils.BuildSecretsExtension] = []interface{}{"missing"}
+	_, cleanup, err = SecretSpecs(project, service)
+	cleanup()
+	assert.Error(t, err, `service "app": build secret "missing" is not defined`)
+
+	specs, cleanup, err = SecretSpecs(project, types.ServiceConfig{Name: "db", Image: "mysql"})
+	cleanup()
+	assert.NilError(t, err)
+	assert.Equal(t, len(specs), 0)
+}
@@ -0,0 +1,279 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// SSHExtension is the build extension listing the SSH agent sockets or keys
+// BuildKit forwards to `RUN --mount=type=ssh` instructions, in the format of
+// the --ssh flag, e.g.
+//
+//	build:
+//	  context: .
+//	  x-ssh:
+//	    - default
+//	    - github=~/.ssh/id_github
+const SSHExtension = "x-ssh"
+
+// DefaultSSH forwards the SSH agent socket set by SSH_AUTH_SOCK
+const DefaultSSH = "default"
+
+// SSH returns the SSH specs of the service build, from its build section and
+// from the given --ssh flag values, the latter taking precedence for a same
+// ID. Paths are resolved relative to the project working directory.
+func SSH(project *types.Project, service types.ServiceConfig, flags []string) ([]string, error) {
+	if service.Build == nil {
+		return nil, nil
+	}
+	specs, err := sshExtension(service.Build.Extensions)
+	if err != nil {
+		return nil, fmt.Errorf("service %q: %w", service.Name, err)
+	}
+	var ids []string
+	byID := map[string]string{}
+	for _, spec := range append(specs, flags...) {
+		id, paths, err := parseSSHSpec(spec)
+		if err != nil {
+			return nil, fmt.Errorf("service %q: %w", service.Name, err)
+		}
+		for i, path := range paths {
+			paths[i] = resolvePath(project.WorkingDir, path)
+		}
+		if _, ok := byID[id]; !ok {
+			ids = append(ids, id)
+		}
+		byID[id] = id
+		if len(paths) > 0 {
+			byID[id] += "=" + strings.Join(paths, ",")
+		}
+	}
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, byID[id])
+	}
+	return result, nil
+}
+
+// BuildxArgs returns the arguments of the `docker buildx build` command
+// building the service image with the given SSH and secret specs and build
+// cache, as compose build would, with the given builder unless empty.
+func BuildxArgs(project *types.Project, service types.ServiceConfig, ssh []string, secrets []string, cache BuildCache, builder string, options api.BuildOptions) []string {
+	var output []string
+	if builder != "" {
+		output = append(output, "--builder", builder)
+	}
+	return buildxArgs(append(output, "--load"), project, service, ssh, secrets, cache, service.Platform, options)
+}
+
+// buildxArgs returns the arguments of the `docker buildx build` command with
+// the given output flags, building the service image for the platforms.
+func buildxArgs(output []string, project *types.Project, service types.ServiceConfig, ssh []string, secrets []string, cache BuildCache, platform string, options api.BuildOptions) []string {
+	build := service.Build
+	args := append([]string{"buildx", "build"}, output...)
+	args = append(args, "--tag", ImageName(project, service))
+	buildContext, dockerfile := contextAndDockerfile(project, service)
+	if dockerfile != "" {
+		args = append(args, "--file", dockerfile)
+	}
+	for _, spec := range ssh {
+		args = append(args, "--ssh", spec)
+	}
+	for _, spec := range secrets {
+		args = append(args, "--secret", spec)
+	}
+	args = append(args, sortedFlags("--build-arg", buildArgs(project, service, options))...)
+	args = append(args, sortedFlags("--label", build.Labels)...)
+	if build.Target != "" {
+		args = append(args, "--target", build.Target)
+	}
+	if build.Network != "" {
+		args = append(args, "--network", build.Network)
+	}
+	if platform != "" {
+		args = append(args, "--platform", platform)
+	}
+	for _, host := range build.ExtraHosts {
+		args = append(args, "--add-host", host)
+	}
+	for _, entry := range cache.From {
+		args = append(args, "--cache-from", entry)
+	}
+	for _, entry := range cache.To {
+		args = append(args, "--cache-to", entry)
+	}
+	if options.NoCache {
+		args = append(args, "--no-cache")
+	}
+	if options.Pull {
+		args = append(args, "--pull")
+	}
+	if options.Quiet {
+		args = append(args, "--quiet")
+	} else if options.Progress != "" {
+		args = append(args, "--progress", options.Progress)
+	}
+	return append(args, buildContext)
+}
+
+// contextAndDockerfile returns the build context of the service and the path
+// of its Dockerfile, resolved relative to the project working directory
+// unless the context is remote.
+func contextAndDockerfile(project *types.Project, service types.ServiceConfig) (string, string) {
+	buildContext := service.Build.Context
+	if isRemoteContext(buildContext) {
+		return buildContext, service.Build.Dockerfile
+	}
+	buildContext = resolvePath(project.WorkingDir, buildContext)
+	dockerfile := service.Build.Dockerfile
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+	return buildContext, resolvePath(buildContext, dockerfile)
+}
+
+// buildArgs returns the build args of the service, overridden by the ones of
+// the build options, resolved from the project environment.
+func buildArgs(project *types.Project, service types.ServiceConfig, options api.BuildOptions) map[string]string {
+	lookup := func(s string) (string, bool) {
+		v, ok := project.Environment[s]
+		return v, ok
+	}
+	args := map[string]string{}
+	for _, m := range []types.MappingWithEquals{service.Build.Args.Resolve(lookup), options.Args.Resolve(lookup)} {
+		for k, v := range m {
+			if v != nil {
+				args[k] = *v
+			}
+		}
+	}
+	return args
+}
+
+// ImageName returns the name of the image built for the service
+func ImageName(project *types.Project, service types.ServiceConfig) string {
+	if service.Image != "" {
+		return service.Image
+	}
+	return project.Name + "_" + service.Name
+}
+
+func sshExtension(extensions map[string]interface{}) ([]string, error) {
+	ext, ok := extensions[SSHExtension]
+	if !ok {
+		// compose-go keeps the build extensions in a nested map
+		if nested, isMap := extensions["extensions"].(map[string]interface{}); isMap {
+			ext, ok = nested[SSHExtension]
+		}
+	}
+	if !ok || ext == nil {
+		return nil, nil
+	}
+	switch v := ext.(type) {
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		specs := make([]string, 0, len(v))
+		for _, spec := range v {
+			s, isString := spec.(string)
+			if !isString {
+				return nil, fmt.Errorf("invalid %s entry %v, must be a string", SSHExtension, spec)
+			}
+			specs = append(specs, s)
+		}
+		return specs, nil
+	case map[string]interface{}:
+		ids := make([]string, 0, len(v))
+		for id := range v {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		specs := make([]string, 0, len(v))
+		for _, id := range ids {
+			if v[id] == nil {
+				specs = append(specs, id)
+				continue
+			}
+			specs = append(specs, fmt.Sprintf("%s=%v", id, v[id]))
+		}
+		return specs, nil
+	default:
+		return nil, fmt.Errorf("invalid %s, must be a list of SSH specs", SSHExtension)
+	}
+}
+
+// parseSSHSpec parses an SSH spec with the `default|<id>[=<socket>|<key>[,<key>]]`
+// format of the --ssh flag.
+func parseSSHSpec(spec string) (string, []string, error) {
+	id, value, hasPaths := strings.Cut(strings.TrimSpace(spec), "=")
+	if id == "" {
+		return "", nil, fmt.Errorf("invalid SSH spec %q, an ID is required", spec)
+	}
+	if !hasPaths {
+		if id != DefaultSSH {
+			return "", nil, fmt.Errorf("invalid SSH spec %q, a socket or key path is required for ID %q", spec, id)
+		}
+		return id, nil, nil
+	}
+	var paths []string
+	for _, path := range strings.Split(value, ",") {
+		if path == "" {
+			return "", nil, fmt.Errorf("invalid SSH spec %q, empty path", spec)
+		}
+		paths = append(paths, path)
+	}
+	return id, paths, nil
+}
+
+// isRemoteContext reports whether the build context is a git repository or
+// a tarball URL, which BuildKit fetches itself.
+func isRemoteContext(context string) bool {
+	return strings.Contains(context, "://") || strings.HasPrefix(context, "git@")
+}
+
+func resolvePath(dir string, path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	if filepath.IsAbs(path) || dir == "" {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+func sortedFlags(flag string, values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	args := make([]string, 0, 2*len(keys))
+	for _, k := range keys {
+		args = append(args, flag, k+"="+values[k])
+	}
+	return args
+}
@@ -0,0 +1,98 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package build
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"gotest.tools/v3/assert"
+)
+
+func TestSSH(t *testing.T) {
+	project := &types.Project{Name: "demo", WorkingDir: "/src"}
+	service := types.ServiceConfig{
+		Name: "app",
+		Build: &types.BuildConfig{
+			Context: ".",
+			Extensions: map[string]interface{}{
+				"extensions": map[string]interface{}{
+					SSHExtension: []interface{}{"default", "github=keys/github,keys/backup"},
+				},
+			},
+		},
+	}
+
+	ssh, err := SSH(project, service, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, ssh, []string{"default", "github=/src/keys/github,/src/keys/backup"})
+
+	ssh, err = SSH(project, service, []string{"github=/run/agent.sock", "gitlab=/keys/gitlab"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, ssh, []string{"default", "github=/run/agent.sock", "gitlab=/keys/gitlab"})
+
+	_, err = SSH(project, service, []string{"github"})
+	assert.ErrorContains(t, err, `service "app": invalid SSH spec "github", a socket or key path is required`)
+
+	ssh, err = SSH(project, types.ServiceConfig{Name: "db", Image: "mysql"}, []string{"default"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(ssh), 0)
+}
+
+func TestBuildxArgs(t *testing.T) {
+	project := &types.Project{
+		Name:        "demo",
+		WorkingDir:  "/src",
+		Environment: map[string]string{"TOKEN": "secret"},
+	}
+	token := "from-flag"
+	service := types.ServiceConfig{
+		Name: "app",
+		Build: &types.BuildConfig{
+			Context:    "app",
+			Dockerfile: "Dockerfile.dev",
+			Args:       types.NewMappingWithEquals([]string{"TOKEN", "MODE=dev"}),
+			Target:     "dev",
+		},
+	}
+
+	args := BuildxArgs(project, service, []string{"default"}, nil, BuildCache{}, "", api.BuildOptions{
+		Args:    types.MappingWithEquals{"MODE": &token},
+		NoCache: true,
+	})
+	assert.DeepEqual(t, args, []string{
+		"buildx", "build", "--load", "--tag", "demo_app",
+		"--file", "/src/app/Dockerfile.dev",
+		"--ssh", "default",
+		"--build-arg", "MODE=from-flag",
+		"--build-arg", "TOKEN=secret",
+		"--target", "dev",
+		"--no-cache",
+		"/src/app",
+	})
+
+	service.Image = "registry.example.com/app:1.0"
+	service.Build = &types.BuildConfig{Context: "git@github.com:example/app.git"}
+	args = BuildxArgs(project, service, []string{"default"}, nil, BuildCache{}, "", api.BuildOptions{Progress: "plain"})
+	assert.DeepEqual(t, args, []string{
+		"buildx", "build", "--load", "--tag", "registry.example.com/app:1.0",
+		"--ssh", "default",
+		"--progress", "plain",
+		"git@github.com:example/app.git",
+	})
+}
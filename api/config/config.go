@@ -44,6 +44,17 @@ func Dir() string {
 	return configDir
 }
 
+// DefaultDir returns the default config directory path, from the
+// DOCKER_CONFIG environment variable or in the user home directory
+func DefaultDir() string {
+	env := os.Getenv("DOCKER_CONFIG")
+	if env != "" {
+		return env
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ConfigFileDir)
+}
+
 // LoadFile loads the docker configuration
 func LoadFile(dir string) (*File, error) {
 	f := &File{}
@@ -72,6 +83,24 @@ func WriteCurrentContext(dir string, name string) error {
 	return writeFile(path, m)
 }
 
+// WriteMetricsEnabled writes the metrics reporting consent to the Docker
+// configuration file.
+func WriteMetricsEnabled(dir string, enabled bool) error {
+	m := map[string]interface{}{}
+	path := configFilePath(dir)
+	err := loadFile(path, &m)
+	if err != nil {
+		return err
+	}
+	metrics, ok := m[metricsKey].(map[string]interface{})
+	if !ok {
+		metrics = map[string]interface{}{}
+	}
+	metrics[metricsEnabledKey] = enabled
+	m[metricsKey] = metrics
+	return writeFile(path, m)
+}
+
 func writeFile(path string, content map[string]interface{}) error {
 	d, err := json.MarshalIndent(content, "", "\t")
 	if err != nil {
@@ -102,4 +131,13 @@ func configFilePath(dir string) string {
 type File struct {
 	CurrentContext string                       `json:"currentContext,omitempty"`
 	Plugins        map[string]map[string]string `json:"plugins,omitempty"`
+	Metrics        *MetricsConfig               `json:"metrics,omitempty"`
+}
+
+// MetricsConfig contains the metrics reporting settings
+type MetricsConfig struct {
+	// Endpoint is the base URL metric events are posted to
+	Endpoint string `json:"endpoint,omitempty"`
+	// Enabled records the user consent, reporting is enabled when unset
+	Enabled *bool `json:"enabled,omitempty"`
 }
@@ -80,3 +80,21 @@ func TestWriteDefaultContextToEmptyConfig(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Equal(t, string(c), "{}")
 }
+
+func TestWriteMetricsEnabled(t *testing.T) {
+	d := testConfigDir(t)
+	writeSampleConfig(t, d)
+
+	err := WriteMetricsEnabled(d, false)
+	assert.NilError(t, err)
+	f, err := LoadFile(d)
+	assert.NilError(t, err)
+	assert.Equal(t, f.CurrentContext, "local")
+	assert.Equal(t, *f.Metrics.Enabled, false)
+
+	err = WriteMetricsEnabled(d, true)
+	assert.NilError(t, err)
+	f, err = LoadFile(d)
+	assert.NilError(t, err)
+	assert.Equal(t, *f.Metrics.Enabled, true)
+}
@@ -29,4 +29,10 @@ const (
 	// currentContextKey is the key used in the Docker config file to set the
 	// default context
 	currentContextKey = "currentContext"
+	// metricsKey is the key used in the Docker config file for metrics
+	// reporting settings
+	metricsKey = "metrics"
+	// metricsEnabledKey is the key of the metrics settings recording the
+	// user consent
+	metricsEnabledKey = "enabled"
 )
@@ -0,0 +1,94 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package phase
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// ImagePull is the phase pulling service images
+	ImagePull = "image-pull"
+	// Build is the phase building service images
+	Build = "build"
+	// StackWait is the phase waiting for a CloudFormation stack to be deployed
+	StackWait = "stack-wait"
+	// DeploymentPolling is the phase polling for an ACI deployment to complete
+	DeploymentPolling = "deployment-polling"
+)
+
+// Phase is a long-running operation of a command
+type Phase struct {
+	Name     string
+	Start    time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// Recorder collects the phases of a command
+type Recorder struct {
+	mu     sync.Mutex
+	phases []Phase
+}
+
+// NewRecorder returns a new phase recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Phases returns the phases recorded so far, in completion order
+func (r *Recorder) Phases() []Phase {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Phase(nil), r.phases...)
+}
+
+func (r *Recorder) record(p Phase) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.phases = append(r.phases, p)
+}
+
+type recorderKey struct{}
+
+// WithRecorder returns a context recording phases to the given recorder
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// Start starts a phase, and returns the function to call with its result
+// once it ends. Nothing is recorded if the context has no recorder.
+func Start(ctx context.Context, name string) func(err error) {
+	r, ok := ctx.Value(recorderKey{}).(*Recorder)
+	if !ok || r == nil {
+		return func(error) {}
+	}
+	start := time.Now()
+	return func(err error) {
+		r.record(Phase{
+			Name:     name,
+			Start:    start,
+			Duration: time.Since(start),
+			Err:      err,
+		})
+	}
+}
@@ -0,0 +1,46 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package phase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	// no recorder, nothing happens
+	Start(context.Background(), Build)(nil)
+
+	r := NewRecorder()
+	ctx := WithRecorder(context.Background(), r)
+	endPull := Start(ctx, ImagePull)
+	endBuild := Start(ctx, Build)
+	endBuild(errors.New("failed"))
+	endPull(nil)
+
+	phases := r.Phases()
+	assert.Equal(t, len(phases), 2)
+	assert.Equal(t, phases[0].Name, Build)
+	assert.Error(t, phases[0].Err, "failed")
+	assert.Equal(t, phases[1].Name, ImagePull)
+	assert.NilError(t, phases[1].Err)
+	assert.Assert(t, phases[1].Duration >= phases[0].Duration)
+	assert.Assert(t, !phases[1].Start.After(phases[0].Start))
+}
@@ -0,0 +1,94 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package watch
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/mitchellh/mapstructure"
+)
+
+// DevelopExtension is the service extension configuring how the service is
+// updated on file changes, e.g.
+//
+//	x-develop:
+//	  watch:
+//	    - path: ./src
+//	      action: sync
+//	      target: /app/src
+//	    - path: ./package.json
+//	      action: rebuild
+const DevelopExtension = "x-develop"
+
+const (
+	// ActionSync copies changed files into the service containers
+	ActionSync = "sync"
+	// ActionRebuild rebuilds the service image and recreates its containers
+	ActionRebuild = "rebuild"
+)
+
+// Trigger is a path watched for a service, and the action run on changes
+type Trigger struct {
+	Path   string   `mapstructure:"path"`
+	Action string   `mapstructure:"action"`
+	Target string   `mapstructure:"target"`
+	Ignore []string `mapstructure:"ignore"`
+}
+
+type developConfig struct {
+	Watch []Trigger `mapstructure:"watch"`
+}
+
+// Triggers returns the watch triggers of the service, with paths resolved
+// relative to the project working directory. A service with a build section
+// and without triggers rebuilds on changes to its build context.
+func Triggers(project *types.Project, service types.ServiceConfig) ([]Trigger, error) {
+	var config developConfig
+	if ext, ok := service.Extensions[DevelopExtension]; ok {
+		if err := mapstructure.Decode(ext, &config); err != nil {
+			return nil, fmt.Errorf("service %q: invalid %s: %w", service.Name, DevelopExtension, err)
+		}
+	}
+	if len(config.Watch) == 0 && service.Build != nil {
+		config.Watch = []Trigger{{Path: service.Build.Context, Action: ActionRebuild}}
+	}
+	triggers := make([]Trigger, 0, len(config.Watch))
+	for _, t := range config.Watch {
+		if t.Path == "" {
+			return nil, fmt.Errorf("service %q: watch path is required", service.Name)
+		}
+		switch t.Action {
+		case ActionSync:
+			if t.Target == "" {
+				return nil, fmt.Errorf("service %q: watch target is required to sync %s", service.Name, t.Path)
+			}
+		case ActionRebuild:
+			if service.Build == nil {
+				return nil, fmt.Errorf("service %q: can't rebuild on changes to %s without a build section", service.Name, t.Path)
+			}
+		default:
+			return nil, fmt.Errorf("service %q: unsupported watch action %q", service.Name, t.Action)
+		}
+		if !filepath.IsAbs(t.Path) {
+			t.Path = filepath.Join(project.WorkingDir, t.Path)
+		}
+		triggers = append(triggers, t)
+	}
+	return triggers, nil
+}
@@ -0,0 +1,251 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// DefaultInterval is how often watched paths are checked for changes
+const DefaultInterval = 500 * time.Millisecond
+
+// Options configures a Watcher
+type Options struct {
+	// Services to watch, all services with triggers if empty
+	Services []string
+	// Interval between checks of the watched paths
+	Interval time.Duration
+	// Out receives progress messages
+	Out io.Writer
+}
+
+// Watcher updates the services of a running project when watched files change
+type Watcher struct {
+	backend  api.Service
+	project  *types.Project
+	interval time.Duration
+	out      io.Writer
+	triggers map[string][]Trigger
+	state    map[string][]snapshot
+}
+
+// NewWatcher creates a watcher for the given project services
+func NewWatcher(backend api.Service, project *types.Project, options Options) (*Watcher, error) {
+	services, err := project.GetServices(options.Services...)
+	if err != nil {
+		return nil, err
+	}
+	triggers := map[string][]Trigger{}
+	for _, s := range services {
+		t, err := Triggers(project, s)
+		if err != nil {
+			return nil, err
+		}
+		if len(t) > 0 {
+			triggers[s.Name] = t
+		}
+	}
+	if len(triggers) == 0 {
+		return nil, fmt.Errorf("none of the selected services has a build section or %s watch configuration", DevelopExtension)
+	}
+	w := &Watcher{
+		backend:  backend,
+		project:  project,
+		interval: options.Interval,
+		out:      options.Out,
+		triggers: triggers,
+	}
+	if w.interval <= 0 {
+		w.interval = DefaultInterval
+	}
+	if w.out == nil {
+		w.out = io.Discard
+	}
+	w.state = w.scan()
+	return w, nil
+}
+
+// Watch checks the watched paths until the context is done, syncing the
+// files changed since the watcher was created or rebuilding services
+// according to their triggers. Failures to update a service are reported and
+// don't stop watching.
+func (w *Watcher) Watch(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		next := w.scan()
+		for _, name := range w.Services() {
+			for i, t := range w.triggers[name] {
+				changes := diff(w.state[name][i], next[name][i])
+				if len(changes) == 0 {
+					continue
+				}
+				if err := w.apply(ctx, name, t, changes); err != nil {
+					fmt.Fprintf(w.out, "%s: %s\n", name, err)
+				}
+			}
+		}
+		w.state = next
+	}
+}
+
+// Services returns the names of the watched services
+func (w *Watcher) Services() []string {
+	names := make([]string, 0, len(w.triggers))
+	for name := range w.triggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// scan snapshots the watched paths, by service and trigger
+func (w *Watcher) scan() map[string][]snapshot {
+	state := map[string][]snapshot{}
+	for name, triggers := range w.triggers {
+		for _, t := range triggers {
+			state[name] = append(state[name], scan(t))
+		}
+	}
+	return state
+}
+
+func (w *Watcher) apply(ctx context.Context, service string, t Trigger, changes []string) error {
+	switch t.Action {
+	case ActionSync:
+		for _, file := range changes {
+			if _, err := os.Stat(file); os.IsNotExist(err) {
+				fmt.Fprintf(w.out, "%s: %s removed, not synced\n", service, file)
+				continue
+			}
+			dest := t.Target
+			if rel, err := filepath.Rel(t.Path, file); err == nil && rel != "." {
+				dest = path.Join(t.Target, filepath.ToSlash(rel))
+			}
+			err := w.backend.Copy(ctx, w.project, api.CopyOptions{
+				Source:      file,
+				Destination: service + ":" + dest,
+				All:         true,
+			})
+			if err != nil {
+				return fmt.Errorf("sync %s: %w", file, err)
+			}
+		}
+		fmt.Fprintf(w.out, "%s: synced %d file(s)\n", service, len(changes))
+		return nil
+	case ActionRebuild:
+		fmt.Fprintf(w.out, "%s: rebuilding after changes to %s\n", service, t.Path)
+		if err := w.backend.Build(ctx, w.project, api.BuildOptions{Services: []string{service}}); err != nil {
+			return fmt.Errorf("rebuild: %w", err)
+		}
+		err := w.backend.Up(ctx, w.project, api.UpOptions{
+			Create: api.CreateOptions{
+				Services:             []string{service},
+				Recreate:             api.RecreateForce,
+				RecreateDependencies: api.RecreateNever,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("restart: %w", err)
+		}
+		fmt.Fprintf(w.out, "%s: restarted\n", service)
+		return nil
+	}
+	return nil
+}
+
+// snapshot is the modification time and size of the watched files
+type snapshot map[string]fileState
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+func scan(t Trigger) snapshot {
+	s := snapshot{}
+	_ = filepath.Walk(t.Path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if ignored(t, p) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() {
+			s[p] = fileState{modTime: info.ModTime(), size: info.Size()}
+		}
+		return nil
+	})
+	return s
+}
+
+func ignored(t Trigger, p string) bool {
+	if filepath.Base(p) == ".git" {
+		return true
+	}
+	rel, err := filepath.Rel(t.Path, p)
+	if err != nil || rel == "." {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	for _, pattern := range t.Ignore {
+		pattern = strings.TrimSuffix(pattern, "/")
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// diff returns the files added, changed or removed between two snapshots
+func diff(before, after snapshot) []string {
+	var changes []string
+	for p, state := range after {
+		if prev, ok := before[p]; !ok || prev != state {
+			changes = append(changes, p)
+		}
+	}
+	for p := range before {
+		if _, ok := after[p]; !ok {
+			changes = append(changes, p)
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
@@ -0,0 +1,139 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"gotest.tools/v3/assert"
+)
+
+func TestTriggers(t *testing.T) {
+	project := &types.Project{WorkingDir: "/project"}
+	triggers, err := Triggers(project, types.ServiceConfig{
+		Name:  "web",
+		Build: &types.BuildConfig{Context: "/project/web"},
+		Extensions: map[string]interface{}{
+			DevelopExtension: map[string]interface{}{
+				"watch": []interface{}{
+					map[string]interface{}{"path": "./web/src", "action": "sync", "target": "/app/src"},
+					map[string]interface{}{"path": "./web/package.json", "action": "rebuild"},
+				},
+			},
+		},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, triggers, []Trigger{
+		{Path: "/project/web/src", Action: ActionSync, Target: "/app/src"},
+		{Path: "/project/web/package.json", Action: ActionRebuild},
+	})
+
+	triggers, err = Triggers(project, types.ServiceConfig{Name: "web", Build: &types.BuildConfig{Context: "/project/web"}})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, triggers, []Trigger{{Path: "/project/web", Action: ActionRebuild}})
+
+	triggers, err = Triggers(project, types.ServiceConfig{Name: "db"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(triggers), 0)
+
+	_, err = Triggers(project, types.ServiceConfig{
+		Name: "db",
+		Extensions: map[string]interface{}{
+			DevelopExtension: map[string]interface{}{
+				"watch": []interface{}{map[string]interface{}{"path": "./conf", "action": "rebuild"}},
+			},
+		},
+	})
+	assert.ErrorContains(t, err, `service "db": can't rebuild on changes to ./conf without a build section`)
+}
+
+func TestDiff(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "b.log"), []byte("b"), 0o600))
+	trigger := Trigger{Path: dir, Ignore: []string{"*.log"}}
+	before := scan(trigger)
+	assert.Equal(t, len(before), 1)
+
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("aa"), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "c.txt"), []byte("c"), 0o600))
+	assert.DeepEqual(t, diff(before, scan(trigger)), []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "c.txt")})
+}
+
+type fakeBackend struct {
+	api.Service
+	mu     sync.Mutex
+	copies []api.CopyOptions
+}
+
+func (f *fakeBackend) Copy(_ context.Context, _ *types.Project, opts api.CopyOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.copies = append(f.copies, opts)
+	return nil
+}
+
+func (f *fakeBackend) Copies() []api.CopyOptions {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]api.CopyOptions(nil), f.copies...)
+}
+
+func TestWatchSync(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.MkdirAll(filepath.Join(dir, "src"), 0o700))
+	project := &types.Project{
+		WorkingDir: dir,
+		Services: types.Services{{
+			Name: "web",
+			Extensions: map[string]interface{}{
+				DevelopExtension: map[string]interface{}{
+					"watch": []interface{}{map[string]interface{}{"path": "src", "action": "sync", "target": "/app"}},
+				},
+			},
+		}},
+	}
+	backend := &fakeBackend{}
+	w, err := NewWatcher(backend, project, Options{Interval: 10 * time.Millisecond})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, w.Services(), []string{"web"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- w.Watch(ctx)
+	}()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "src", "main.go"), []byte("package main"), 0o600))
+	deadline := time.Now().Add(5 * time.Second)
+	for len(backend.Copies()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	assert.NilError(t, <-done)
+	assert.DeepEqual(t, backend.Copies(), []api.CopyOptions{{
+		Source:      filepath.Join(dir, "src", "main.go"),
+		Destination: "web:/app/main.go",
+		All:         true,
+	}})
+}
@@ -0,0 +1,89 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/compose-spec/compose-go/cli"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/spf13/cobra"
+)
+
+// composeProject loads the project selected by the project flags of the
+// compose command the given command was added to, restricted to the given
+// services and the enabled profiles.
+func composeProject(cmd *cobra.Command, services []string, po ...cli.ProjectOptionsFn) (*types.Project, error) {
+	flags := cmd.Flags()
+	if cmd.HasParent() {
+		flags = cmd.Parent().Flags()
+	}
+	configPaths, _ := flags.GetStringArray("file")
+	profiles, _ := flags.GetStringArray("profile")
+	name, _ := flags.GetString("project-name")
+	projectDir, _ := flags.GetString("project-directory")
+	envFile, _ := flags.GetString("env-file")
+
+	options, err := cli.NewProjectOptions(configPaths,
+		append(po,
+			cli.WithResolvedPaths(true),
+			cli.WithWorkingDirectory(projectDir),
+			cli.WithEnvFile(envFile),
+			cli.WithDotEnv,
+			cli.WithOsEnv,
+			cli.WithConfigFileEnv,
+			cli.WithDefaultConfigPath,
+			cli.WithName(name))...)
+	if err != nil {
+		return nil, err
+	}
+	project, err := cli.ProjectFromOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	if len(services) > 0 {
+		s, err := project.GetServices(services...)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, s.GetProfiles()...)
+	}
+	if p, ok := options.Environment["COMPOSE_PROFILES"]; ok {
+		profiles = append(profiles, strings.Split(p, ",")...)
+	}
+	project.ApplyProfiles(profiles)
+	project.WithoutUnnecessaryResources()
+	return project, project.ForServices(services)
+}
+
+// composeProjectName returns the project name set by the --project-name flag
+// of the compose command, or the name of the project loaded from the compose
+// files otherwise.
+func composeProjectName(cmd *cobra.Command) (string, error) {
+	flags := cmd.Flags()
+	if cmd.HasParent() {
+		flags = cmd.Parent().Flags()
+	}
+	if name, _ := flags.GetString("project-name"); name != "" {
+		return name, nil
+	}
+	project, err := composeProject(cmd, nil)
+	if err != nil {
+		return "", err
+	}
+	return project.Name, nil
+}
@@ -0,0 +1,365 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/build"
+	"github.com/docker/compose-cli/api/context/store"
+	"github.com/docker/compose-cli/cli/mobycli"
+	"github.com/docker/compose-cli/utils"
+)
+
+// AddBuildx adds the build options the compose library has no support for,
+// building the images requiring them with buildx instead of the backend:
+//
+//   - --ssh and --bake to compose build, up and create
+//   - --cache-from and --cache-to to compose build
+//   - --platforms to compose build and push
+//
+// The services using SSH specs, build secrets or build cache options are
+// built by buildWithBuildx, the ones built for several platforms by
+// buildMultiPlatform, and all of them with --bake by buildWithBake. Images
+// are only built with buildx on context types building against a local
+// engine.
+func AddBuildx(command *cobra.Command, proxy *api.ServiceProxy, contextType string) {
+	var (
+		ssh       []string
+		bake      bool
+		platforms []string
+		cache     build.BuildCache
+	)
+	for _, c := range command.Commands() {
+		if c.Name() == "build" || c.Name() == "up" || c.Name() == "create" {
+			c.Flags().StringArrayVar(&ssh, "ssh", nil, "Set SSH authentications used when building service images. (use 'default' for using your default SSH Agent)")
+			c.Flags().BoolVar(&bake, "bake", false, "Build service images concurrently with a single buildx bake execution plan")
+		}
+		if c.Name() == "build" || c.Name() == "push" {
+			c.Flags().StringSliceVar(&platforms, "platforms", nil, "Build service images for these platforms, e.g. linux/amd64,linux/arm64")
+		}
+		if c.Name() == "build" {
+			c.Flags().StringArrayVar(&cache.From, "cache-from", nil, "Import the build cache of service images from an image or cache source (e.g. type=local,src=path)")
+			c.Flags().StringArrayVar(&cache.To, "cache-to", nil, "Export the build cache of service images to an image or cache destination (e.g. type=local,dest=path)")
+		}
+	}
+	local := contextType == store.LocalContextType || contextType == store.EcsLocalSimulationContextType
+	buildx := func(ctx context.Context, project *types.Project, services types.Services, options api.BuildOptions) error {
+		if bake {
+			return buildWithBake(ctx, project, services, ssh, cache, options)
+		}
+		return buildWithBuildx(ctx, project, services, ssh, cache, options)
+	}
+	checkUnsupported := func() error {
+		if len(ssh) > 0 {
+			return errors.Errorf("--ssh is not supported on context type %s", contextType)
+		}
+		if bake {
+			return errors.Errorf("--bake is not supported on context type %s", contextType)
+		}
+		if len(platforms) > 0 {
+			return errors.Errorf("--platforms is not supported on context type %s", contextType)
+		}
+		if len(cache.From) > 0 || len(cache.To) > 0 {
+			return errors.Errorf("--cache-from and --cache-to are not supported on context type %s", contextType)
+		}
+		return nil
+	}
+
+	// with --build, services are always rebuilt
+	rebuild := func(ctx context.Context, project *types.Project) error {
+		var services types.Services
+		for _, service := range project.Services {
+			if service.PullPolicy == types.PullPolicyBuild {
+				services = append(services, service)
+			}
+		}
+		return buildx(ctx, project, services, api.BuildOptions{})
+	}
+
+	buildFn, upFn, createFn, pushFn := proxy.BuildFn, proxy.UpFn, proxy.CreateFn, proxy.PushFn
+	proxy.BuildFn = func(ctx context.Context, project *types.Project, options api.BuildOptions) error {
+		if utils.IsDryRun(ctx) {
+			return buildFn(ctx, project, options)
+		}
+		if !local {
+			if err := checkUnsupported(); err != nil {
+				return err
+			}
+			return buildFn(ctx, project, options)
+		}
+		services, err := project.GetServices(options.Services...)
+		if err != nil {
+			return err
+		}
+		if services, err = buildMultiPlatform(ctx, project, services, ssh, cache, platforms, false, options); err != nil {
+			return err
+		}
+		if err := buildx(ctx, project, services, options); err != nil {
+			return err
+		}
+		return buildFn(ctx, project, options)
+	}
+	proxy.PushFn = func(ctx context.Context, project *types.Project, options api.PushOptions) error {
+		if utils.IsDryRun(ctx) {
+			return pushFn(ctx, project, options)
+		}
+		if !local {
+			if err := checkUnsupported(); err != nil {
+				return err
+			}
+			return pushFn(ctx, project, options)
+		}
+		others, err := buildMultiPlatform(ctx, project, project.Services, nil, cache, platforms, true, api.BuildOptions{})
+		if err != nil {
+			return err
+		}
+		if len(others) == len(project.Services) {
+			return pushFn(ctx, project, options)
+		}
+		pushed := *project
+		pushed.Services = others
+		return pushFn(ctx, &pushed, options)
+	}
+	proxy.UpFn = func(ctx context.Context, project *types.Project, options api.UpOptions) error {
+		if utils.IsDryRun(ctx) {
+			return upFn(ctx, project, options)
+		}
+		if !local {
+			if err := checkUnsupported(); err != nil {
+				return err
+			}
+			return upFn(ctx, project, options)
+		}
+		if err := rebuild(ctx, project); err != nil {
+			return err
+		}
+		return upFn(ctx, project, options)
+	}
+	proxy.CreateFn = func(ctx context.Context, project *types.Project, options api.CreateOptions) error {
+		if utils.IsDryRun(ctx) {
+			return createFn(ctx, project, options)
+		}
+		if !local {
+			if err := checkUnsupported(); err != nil {
+				return err
+			}
+			return createFn(ctx, project, options)
+		}
+		if err := rebuild(ctx, project); err != nil {
+			return err
+		}
+		return createFn(ctx, project, options)
+	}
+}
+
+// buildMultiPlatform builds the images of the given services for several
+// platforms, from the flags or from the platforms of their build section, and
+// returns the other services. Images are built by the containerized builder.
+// The engine not storing images for several platforms, they are kept in the
+// build cache by compose build and pushed with their manifest list with push.
+// The services built for a single platform are given it as their platform
+// when they don't set one.
+func buildMultiPlatform(ctx context.Context, project *types.Project, services types.Services, sshFlags []string, cacheFlags build.BuildCache, flags []string, push bool, options api.BuildOptions) (types.Services, error) {
+	var (
+		others  types.Services
+		builder string
+	)
+	for _, service := range services {
+		platforms, err := build.Platforms(service, flags)
+		if err != nil {
+			return nil, err
+		}
+		if len(platforms) <= 1 {
+			if len(platforms) == 1 && service.Platform == "" {
+				service.Platform = platforms[0]
+				setPlatform(project, service.Name, service.Platform)
+			}
+			others = append(others, service)
+			continue
+		}
+		if builder == "" {
+			if builder, err = containerizedBuilder(ctx); err != nil {
+				return nil, err
+			}
+		}
+		ssh, err := build.SSH(project, service, sshFlags)
+		if err != nil {
+			return nil, err
+		}
+		cache, err := build.Cache(service, cacheFlags)
+		if err != nil {
+			return nil, err
+		}
+		secrets, cleanup, err := build.SecretSpecs(project, service)
+		defer cleanup()
+		if err != nil {
+			return nil, err
+		}
+		if err := runDocker(build.MultiPlatformBuildxArgs(project, service, ssh, secrets, cache, platforms, builder, push, options)...); err != nil {
+			return nil, errors.Wrapf(err, "failed to build service %q", service.Name)
+		}
+		removeBuild(project, service.Name)
+	}
+	return others, nil
+}
+
+// containerizedBuilder returns the builder set by BUILDX_BUILDER, or else the
+// ContainerizedBuilder, created when missing.
+func containerizedBuilder(ctx context.Context) (string, error) {
+	if builder := os.Getenv("BUILDX_BUILDER"); builder != "" {
+		return builder, nil
+	}
+	ls, err := mobycli.ExecSilent(ctx, "buildx", "ls")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list buildx builders")
+	}
+	if !build.HasBuilder(ls, build.ContainerizedBuilder) {
+		if err := runDocker(build.CreateBuilderArgs(build.ContainerizedBuilder)...); err != nil {
+			return "", errors.Wrap(err, "failed to create the containerized builder")
+		}
+	}
+	return build.ContainerizedBuilder, nil
+}
+
+// buildWithBuildx builds the images of the given services requiring SSH
+// forwarding, secrets or build cache options the compose library doesn't
+// support, by the containerized builder unless only exporting the inline
+// cache.
+func buildWithBuildx(ctx context.Context, project *types.Project, services types.Services, sshFlags []string, cacheFlags build.BuildCache, options api.BuildOptions) error {
+	for _, service := range services {
+		ssh, err := build.SSH(project, service, sshFlags)
+		if err != nil {
+			return err
+		}
+		cache, err := build.Cache(service, cacheFlags)
+		if err != nil {
+			return err
+		}
+		secrets, cleanup, err := build.SecretSpecs(project, service)
+		defer cleanup()
+		if err != nil {
+			return err
+		}
+		// the compose library ignores the cache flags
+		if len(ssh) == 0 && len(secrets) == 0 && !cache.RequiresBuildx() && len(cacheFlags.From) == 0 {
+			continue
+		}
+		var builder string
+		if cache.RequiresContainerizedBuilder() {
+			if builder, err = containerizedBuilder(ctx); err != nil {
+				return err
+			}
+		}
+		if err := runDocker(build.BuildxArgs(project, service, ssh, secrets, cache, builder, options)...); err != nil {
+			return errors.Wrapf(err, "failed to build service %q", service.Name)
+		}
+		removeBuild(project, service.Name)
+	}
+	return nil
+}
+
+// buildWithBake builds the images of the given services with a bake file
+// written to a temporary directory.
+func buildWithBake(ctx context.Context, project *types.Project, services types.Services, sshFlags []string, cacheFlags build.BuildCache, options api.BuildOptions) error {
+	var (
+		toBuild types.Services
+		builder string
+	)
+	secrets := map[string][]string{}
+	for _, service := range services {
+		if service.Build == nil {
+			continue
+		}
+		toBuild = append(toBuild, service)
+		cache, err := build.Cache(service, cacheFlags)
+		if err != nil {
+			return err
+		}
+		specs, cleanup, err := build.SecretSpecs(project, service)
+		defer cleanup()
+		if err != nil {
+			return err
+		}
+		if len(specs) > 0 {
+			secrets[service.Name] = specs
+		}
+		if builder == "" && cache.RequiresContainerizedBuilder() {
+			if builder, err = containerizedBuilder(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	if len(toBuild) == 0 {
+		return nil
+	}
+	content, err := build.BakeFile(project, toBuild, sshFlags, secrets, cacheFlags, options)
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp("", "compose-bake-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name()) // nolint: errcheck
+	if _, err := file.Write(content); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := runDocker(build.BakeArgs(file.Name(), builder, options)...); err != nil {
+		return errors.Wrap(err, "failed to build services")
+	}
+	for _, service := range toBuild {
+		removeBuild(project, service.Name)
+	}
+	return nil
+}
+
+func runDocker(args ...string) error {
+	childExit := make(chan bool)
+	err := mobycli.RunDocker(childExit, args...)
+	childExit <- true
+	return err
+}
+
+func setPlatform(project *types.Project, name string, platform string) {
+	for i, s := range project.Services {
+		if s.Name == name {
+			project.Services[i].Platform = platform
+		}
+	}
+}
+
+// removeBuild removes the build section of a built service so that the
+// backend uses the built image as is.
+func removeBuild(project *types.Project, name string) {
+	for i, s := range project.Services {
+		if s.Name == name {
+			project.Services[i].Image = build.ImageName(project, s)
+			project.Services[i].Build = nil
+		}
+	}
+}
@@ -0,0 +1,74 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// AddBuildArgs adds the --build-arg flag of compose build to compose up and
+// create, setting the build args of the images they build.
+func AddBuildArgs(command *cobra.Command, proxy *api.ServiceProxy) {
+	var args []string
+	for _, c := range command.Commands() {
+		if c.Name() == "up" || c.Name() == "create" {
+			c.Flags().StringArrayVar(&args, "build-arg", []string{}, "Set build-time variables for services.")
+		}
+	}
+	up, create := proxy.UpFn, proxy.CreateFn
+	proxy.UpFn = func(ctx context.Context, project *types.Project, options api.UpOptions) error {
+		withBuildArgs(project, args)
+		return up(ctx, project, options)
+	}
+	proxy.CreateFn = func(ctx context.Context, project *types.Project, options api.CreateOptions) error {
+		withBuildArgs(project, args)
+		return create(ctx, project, options)
+	}
+}
+
+// withBuildArgs overrides the build args of the services with the key=value
+// flag values. Like the build args of compose files, args without a value are
+// resolved from the project environment and ignored when not set.
+func withBuildArgs(project *types.Project, flags []string) {
+	if len(flags) == 0 {
+		return
+	}
+	args := types.NewMappingWithEquals(flags).Resolve(func(name string) (string, bool) {
+		value, ok := project.Environment[name]
+		return value, ok
+	})
+	for i, service := range project.Services {
+		if service.Build == nil {
+			continue
+		}
+		build := *service.Build
+		build.Args = types.MappingWithEquals{}
+		for k, v := range service.Build.Args {
+			build.Args[k] = v
+		}
+		for k, v := range args {
+			if v != nil {
+				build.Args[k] = v
+			}
+		}
+		project.Services[i].Build = &build
+	}
+}
@@ -0,0 +1,47 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestWithBuildArgs(t *testing.T) {
+	version := "1.0"
+	build := &types.BuildConfig{Context: ".", Args: types.MappingWithEquals{"VERSION": &version}}
+	project := &types.Project{
+		Environment: map[string]string{"TOKEN": "s3cr3t"},
+		Services: types.Services{
+			{Name: "app", Build: build},
+			{Name: "db", Image: "mysql"},
+		},
+	}
+
+	withBuildArgs(project, []string{"VERSION=2.0", "TOKEN", "MISSING", "EMPTY="})
+	two, token, empty := "2.0", "s3cr3t", ""
+	assert.DeepEqual(t, project.Services[0].Build.Args, types.MappingWithEquals{
+		"VERSION": &two,
+		"TOKEN":   &token,
+		"EMPTY":   &empty,
+	})
+	assert.Assert(t, project.Services[1].Build == nil)
+	// the build section of the compose model isn't changed
+	assert.DeepEqual(t, build.Args, types.MappingWithEquals{"VERSION": &version})
+}
@@ -0,0 +1,56 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// AddConvertOutput validates the compose convert format and writes the
+// converted model to the --output file, which the compose library creates
+// but leaves empty as it doesn't flush its buffered writer. Nothing is written
+// with --quiet, which only validates the model.
+func AddConvertOutput(command *cobra.Command, proxy *api.ServiceProxy) {
+	var quiet bool
+	for _, c := range command.Commands() {
+		if c.Name() == "convert" {
+			run := c.RunE
+			c.RunE = func(cmd *cobra.Command, args []string) error {
+				quiet, _ = cmd.Flags().GetBool("quiet")
+				return run(cmd, args)
+			}
+		}
+	}
+	convert := proxy.ConvertFn
+	proxy.ConvertFn = func(ctx context.Context, project *types.Project, options api.ConvertOptions) ([]byte, error) {
+		if options.Format != "yaml" && options.Format != "json" {
+			return nil, errors.Errorf(`invalid --format value %q, must be "yaml" or "json"`, options.Format)
+		}
+		b, err := convert(ctx, project, options)
+		if err != nil || quiet || options.Output == "" || len(b) == 0 {
+			return b, err
+		}
+		// returning no content, the compose library doesn't write the file
+		return nil, os.WriteFile(options.Output, b, 0o666)
+	}
+}
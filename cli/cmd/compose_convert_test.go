@@ -0,0 +1,70 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+)
+
+func TestConvertOutput(t *testing.T) {
+	proxy := api.NewServiceProxy()
+	proxy.ConvertFn = func(ctx context.Context, project *types.Project, options api.ConvertOptions) ([]byte, error) {
+		return []byte("services: {}\n"), nil
+	}
+	project := &types.Project{Name: "demo"}
+	output := filepath.Join(t.TempDir(), "compose.yaml")
+	command := &cobra.Command{Use: "compose"}
+	convertCmd := &cobra.Command{
+		Use: "convert",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, err := proxy.Convert(cmd.Context(), project, api.ConvertOptions{Format: "yaml", Output: output})
+			return err
+		},
+	}
+	convertCmd.Flags().Bool("quiet", false, "")
+	command.AddCommand(convertCmd)
+	AddConvertOutput(command, proxy)
+
+	b, err := proxy.Convert(context.TODO(), project, api.ConvertOptions{Format: "yaml"})
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), "services: {}\n")
+
+	b, err = proxy.Convert(context.TODO(), project, api.ConvertOptions{Format: "yaml", Output: output})
+	assert.NilError(t, err)
+	assert.Equal(t, len(b), 0)
+	written, err := os.ReadFile(output)
+	assert.NilError(t, err)
+	assert.Equal(t, string(written), "services: {}\n")
+
+	_, err = proxy.Convert(context.TODO(), project, api.ConvertOptions{Format: "toml"})
+	assert.ErrorContains(t, err, `invalid --format value "toml"`)
+
+	// --quiet only validates the model
+	output = filepath.Join(t.TempDir(), "compose.yaml")
+	command.SetArgs([]string{"convert", "--quiet"})
+	assert.NilError(t, command.Execute())
+	_, err = os.Stat(output)
+	assert.Assert(t, os.IsNotExist(err))
+}
@@ -0,0 +1,91 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/build"
+)
+
+// createOptions are the compose create flags applied to the project, as the
+// compose library ignores --build and --no-build.
+type createOptions struct {
+	build   bool
+	noBuild bool
+	pull    string
+}
+
+// AddCreateOptions makes compose create honor --build and --no-build, and
+// adds the --pull flag setting the pull policy of all services. With
+// --no-build, services run the image they were built as, compose up only
+// removing their build section.
+func AddCreateOptions(command *cobra.Command, proxy *api.ServiceProxy) {
+	var opts createOptions
+	for _, c := range command.Commands() {
+		if c.Name() != "create" {
+			continue
+		}
+		c.Flags().StringVar(&opts.pull, "pull", "", `Pull images before creating containers ("always"|"missing"|"never")`)
+		run := c.RunE
+		c.RunE = func(cmd *cobra.Command, args []string) error {
+			switch opts.pull {
+			case "", types.PullPolicyAlways, types.PullPolicyMissing, types.PullPolicyNever:
+			default:
+				return errors.Errorf(`invalid --pull value %q, must be "always", "missing" or "never"`, opts.pull)
+			}
+			opts.build, _ = cmd.Flags().GetBool("build")
+			opts.noBuild, _ = cmd.Flags().GetBool("no-build")
+			return run(cmd, args)
+		}
+	}
+	create, up := proxy.CreateFn, proxy.UpFn
+	proxy.CreateFn = func(ctx context.Context, project *types.Project, options api.CreateOptions) error {
+		opts.apply(project)
+		return create(ctx, project, options)
+	}
+	proxy.UpFn = func(ctx context.Context, project *types.Project, options api.UpOptions) error {
+		for i, service := range project.Services {
+			// only left by --no-build, the compose loader rejecting it
+			if service.Image == "" && service.Build == nil {
+				project.Services[i].Image = build.ImageName(project, service)
+			}
+		}
+		return up(ctx, project, options)
+	}
+}
+
+func (opts createOptions) apply(project *types.Project) {
+	for i, service := range project.Services {
+		if opts.pull != "" {
+			service.PullPolicy = opts.pull
+		}
+		if opts.build && service.Build != nil {
+			service.PullPolicy = types.PullPolicyBuild
+		}
+		if opts.noBuild {
+			service.Image = build.ImageName(project, service)
+			service.Build = nil
+		}
+		project.Services[i] = service
+	}
+}
@@ -0,0 +1,77 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+)
+
+func TestCreateOptionsApply(t *testing.T) {
+	newProject := func() *types.Project {
+		return &types.Project{Services: types.Services{
+			{Name: "app", Image: "demo/app", Build: &types.BuildConfig{Context: "."}},
+			{Name: "db", Image: "mysql"},
+		}}
+	}
+
+	project := newProject()
+	createOptions{build: true, pull: types.PullPolicyAlways}.apply(project)
+	assert.Equal(t, project.Services[0].PullPolicy, types.PullPolicyBuild)
+	assert.Equal(t, project.Services[1].PullPolicy, types.PullPolicyAlways)
+
+	project = newProject()
+	createOptions{noBuild: true, pull: types.PullPolicyNever}.apply(project)
+	assert.Assert(t, project.Services[0].Build == nil)
+	assert.Equal(t, project.Services[0].Image, "demo/app")
+	assert.Equal(t, project.Services[0].PullPolicy, types.PullPolicyNever)
+
+	project = &types.Project{Name: "demo", Services: types.Services{
+		{Name: "worker", Build: &types.BuildConfig{Context: "."}},
+	}}
+	createOptions{noBuild: true}.apply(project)
+	assert.Equal(t, project.Services[0].Image, "demo_worker")
+
+	project = newProject()
+	createOptions{}.apply(project)
+	assert.DeepEqual(t, project, newProject())
+}
+
+func TestUpNoBuild(t *testing.T) {
+	var images []string
+	proxy := api.NewServiceProxy()
+	proxy.UpFn = func(ctx context.Context, project *types.Project, options api.UpOptions) error {
+		for _, service := range project.Services {
+			images = append(images, service.Image)
+		}
+		return nil
+	}
+	AddCreateOptions(&cobra.Command{Use: "compose"}, proxy)
+
+	// services as left by compose up --no-build
+	project := &types.Project{Name: "demo", Services: types.Services{
+		{Name: "app", Image: "demo/app"},
+		{Name: "worker"},
+	}}
+	assert.NilError(t, proxy.Up(context.Background(), project, api.UpOptions{}))
+	assert.DeepEqual(t, images, []string{"demo/app", "demo_worker"})
+}
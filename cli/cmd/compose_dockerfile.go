@@ -0,0 +1,108 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+// AddDockerfileInline builds the images of the services embedding their
+// Dockerfile in the compose file, with the dockerfile_inline build attribute,
+// from the Dockerfile written to a temporary directory for the time of the
+// command.
+func AddDockerfileInline(proxy *api.ServiceProxy) {
+	buildFn, pushFn, upFn, createFn, runFn := proxy.BuildFn, proxy.PushFn, proxy.UpFn, proxy.CreateFn, proxy.RunOneOffContainerFn
+	proxy.BuildFn = func(ctx context.Context, project *types.Project, options api.BuildOptions) error {
+		cleanup, err := writeInlineDockerfiles(project)
+		defer cleanup()
+		if err != nil {
+			return err
+		}
+		return buildFn(ctx, project, options)
+	}
+	// multi-platform images are built again when pushed
+	proxy.PushFn = func(ctx context.Context, project *types.Project, options api.PushOptions) error {
+		cleanup, err := writeInlineDockerfiles(project)
+		defer cleanup()
+		if err != nil {
+			return err
+		}
+		return pushFn(ctx, project, options)
+	}
+	proxy.UpFn = func(ctx context.Context, project *types.Project, options api.UpOptions) error {
+		cleanup, err := writeInlineDockerfiles(project)
+		defer cleanup()
+		if err != nil {
+			return err
+		}
+		return upFn(ctx, project, options)
+	}
+	proxy.CreateFn = func(ctx context.Context, project *types.Project, options api.CreateOptions) error {
+		cleanup, err := writeInlineDockerfiles(project)
+		defer cleanup()
+		if err != nil {
+			return err
+		}
+		return createFn(ctx, project, options)
+	}
+	proxy.RunOneOffContainerFn = func(ctx context.Context, project *types.Project, options api.RunOptions) (int, error) {
+		cleanup, err := writeInlineDockerfiles(project)
+		defer cleanup()
+		if err != nil {
+			return 0, err
+		}
+		return runFn(ctx, project, options)
+	}
+}
+
+// writeInlineDockerfiles writes the inline Dockerfiles of the services to
+// temporary directories, set as the Dockerfile of their build, and returns the
+// function removing them.
+func writeInlineDockerfiles(project *types.Project) (func(), error) {
+	var dirs []string
+	cleanup := func() {
+		for _, dir := range dirs {
+			_ = os.RemoveAll(dir)
+		}
+	}
+	for i, service := range project.Services {
+		content, ok := utils.DockerfileInline(service.Build)
+		if !ok {
+			continue
+		}
+		dir, err := os.MkdirTemp("", "compose-dockerfile-")
+		if err != nil {
+			return cleanup, err
+		}
+		dirs = append(dirs, dir)
+		dockerfile := filepath.Join(dir, "Dockerfile")
+		if err := os.WriteFile(dockerfile, []byte(content), 0o600); err != nil {
+			return cleanup, err
+		}
+		build := *service.Build
+		build.Dockerfile = dockerfile
+		project.Services[i].Build = &build
+	}
+	return cleanup, nil
+}
@@ -0,0 +1,55 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+func TestWriteInlineDockerfiles(t *testing.T) {
+	inline := &types.BuildConfig{
+		Context:    ".",
+		Dockerfile: "Dockerfile",
+		Extensions: map[string]interface{}{utils.DockerfileInlineExtension: "FROM alpine\n"},
+	}
+	project := &types.Project{Services: types.Services{
+		{Name: "app", Build: inline},
+		{Name: "web", Build: &types.BuildConfig{Context: ".", Dockerfile: "Dockerfile.web"}},
+		{Name: "db", Image: "mysql"},
+	}}
+
+	cleanup, err := writeInlineDockerfiles(project)
+	assert.NilError(t, err)
+	dockerfile := project.Services[0].Build.Dockerfile
+	assert.Assert(t, filepath.IsAbs(dockerfile))
+	content, err := os.ReadFile(dockerfile)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "FROM alpine\n")
+	assert.Equal(t, inline.Dockerfile, "Dockerfile")
+	assert.Equal(t, project.Services[1].Build.Dockerfile, "Dockerfile.web")
+
+	cleanup()
+	_, err = os.Stat(filepath.Dir(dockerfile))
+	assert.Assert(t, os.IsNotExist(err))
+}
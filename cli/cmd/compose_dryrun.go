@@ -0,0 +1,88 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/context/store"
+	"github.com/docker/compose-cli/utils"
+)
+
+// dryRunCommands are the commands printing their operations with --dry-run
+var dryRunCommands = []string{"up", "down", "build", "pull"}
+
+// AddDryRun adds the --dry-run flag to compose up, down, build and pull,
+// telling the backend through the context to print the operations they would
+// perform instead of performing them. It must wrap the proxy functions last,
+// so that no other wrapper runs operations before the backend is called.
+func AddDryRun(command *cobra.Command, proxy *api.ServiceProxy, contextType string) {
+	var dryRun bool
+	for _, c := range command.Commands() {
+		for _, name := range dryRunCommands {
+			if c.Name() == name {
+				c.Flags().BoolVar(&dryRun, "dry-run", false, "Print the operations the command would perform, without performing them")
+			}
+		}
+	}
+	withDryRun := func(ctx context.Context) (context.Context, error) {
+		if !dryRun {
+			return ctx, nil
+		}
+		switch contextType {
+		case store.LocalContextType, store.EcsContextType:
+			return utils.WithDryRun(ctx), nil
+		default:
+			return nil, errors.Errorf("--dry-run is not supported on context type %s", contextType)
+		}
+	}
+
+	up, down, build, pull := proxy.UpFn, proxy.DownFn, proxy.BuildFn, proxy.PullFn
+	proxy.UpFn = func(ctx context.Context, project *types.Project, options api.UpOptions) error {
+		ctx, err := withDryRun(ctx)
+		if err != nil {
+			return err
+		}
+		return up(ctx, project, options)
+	}
+	proxy.DownFn = func(ctx context.Context, projectName string, options api.DownOptions) error {
+		ctx, err := withDryRun(ctx)
+		if err != nil {
+			return err
+		}
+		return down(ctx, projectName, options)
+	}
+	proxy.BuildFn = func(ctx context.Context, project *types.Project, options api.BuildOptions) error {
+		ctx, err := withDryRun(ctx)
+		if err != nil {
+			return err
+		}
+		return build(ctx, project, options)
+	}
+	proxy.PullFn = func(ctx context.Context, project *types.Project, options api.PullOptions) error {
+		ctx, err := withDryRun(ctx)
+		if err != nil {
+			return err
+		}
+		return pull(ctx, project, options)
+	}
+}
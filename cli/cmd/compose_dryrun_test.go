@@ -0,0 +1,67 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/context/store"
+	"github.com/docker/compose-cli/utils"
+)
+
+func TestDryRun(t *testing.T) {
+	newCommand := func() *cobra.Command {
+		command := &cobra.Command{Use: "compose"}
+		command.AddCommand(&cobra.Command{Use: "up"}, &cobra.Command{Use: "restart"})
+		return command
+	}
+	var dryRun bool
+	newProxy := func() *api.ServiceProxy {
+		proxy := api.NewServiceProxy()
+		proxy.UpFn = func(ctx context.Context, project *types.Project, options api.UpOptions) error {
+			dryRun = utils.IsDryRun(ctx)
+			return nil
+		}
+		return proxy
+	}
+
+	command, proxy := newCommand(), newProxy()
+	AddDryRun(command, proxy, store.LocalContextType)
+	up, _, err := command.Find([]string{"up"})
+	assert.NilError(t, err)
+	assert.NilError(t, up.Flags().Set("dry-run", "true"))
+	assert.NilError(t, proxy.Up(context.TODO(), &types.Project{}, api.UpOptions{}))
+	assert.Assert(t, dryRun)
+
+	restart, _, err := command.Find([]string{"restart"})
+	assert.NilError(t, err)
+	assert.Assert(t, restart.Flags().Lookup("dry-run") == nil)
+
+	command, proxy = newCommand(), newProxy()
+	AddDryRun(command, proxy, store.AciContextType)
+	up, _, err = command.Find([]string{"up"})
+	assert.NilError(t, err)
+	assert.NilError(t, up.Flags().Set("dry-run", "true"))
+	err = proxy.Up(context.TODO(), &types.Project{}, api.UpOptions{})
+	assert.Error(t, err, "--dry-run is not supported on context type aci")
+}
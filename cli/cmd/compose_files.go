@@ -0,0 +1,220 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/compose-spec/compose-go/cli"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+// AddComposeFileResolution adds support for the compose file features the
+// compose library loader rejects, like the include top-level element, see
+// utils.ResolveComposeFile, and adds the --strict-interpolation flag, failing
+// on the substitution of variables which are not set. Compose files are also
+// looked up in the directory set by --project-directory.
+//
+// Before running a compose command, the compose files using these features
+// are replaced by resolved temporary files, removed by the returned function
+// once the command has run, whether it succeeded or not. The projects keep
+// the original compose files, which the resources are labelled with.
+func AddComposeFileResolution(command *cobra.Command, proxy *api.ServiceProxy) func() {
+	var (
+		generated map[string]string
+		strict    bool
+	)
+	command.PersistentFlags().BoolVar(&strict, "strict-interpolation", false, "Fail when compose files substitute variables which are not set")
+	preRun := command.PersistentPreRunE
+	command.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if preRun != nil {
+			if err := preRun(cmd, args); err != nil {
+				return err
+			}
+		}
+		files, err := resolveComposeFiles(command.PersistentFlags(), strict)
+		generated = files
+		return err
+	}
+	proxy.WithInterceptor(func(ctx context.Context, project *types.Project) {
+		files := make([]string, 0, len(project.ComposeFiles))
+		for _, f := range project.ComposeFiles {
+			if original, ok := generated[f]; ok {
+				f = original
+			}
+			files = append(files, f)
+		}
+		project.ComposeFiles = files
+	})
+	return func() {
+		for f := range generated {
+			_ = os.Remove(f)
+		}
+	}
+}
+
+// resolveComposeFiles replaces the compose files set by the flags, or found by
+// default, which have to be resolved and returns the generated files with the
+// compose file each of them replaces. The
+// project directory is kept as the directory of the first compose file.
+//
+// When the --file flag isn't set, the compose files set by COMPOSE_FILE are set
+// to the flag. When neither compose files nor COMPOSE_FILE are set, they are
+// looked up in the project directory set by --project-directory, like with
+// docker-compose v1, rather than in the working directory.
+func resolveComposeFiles(flags *pflag.FlagSet, strict bool) (map[string]string, error) {
+	configPaths, _ := flags.GetStringArray("file")
+	projectDir, _ := flags.GetString("project-directory")
+	if projectDir == "" {
+		// deprecated alias of --project-directory
+		projectDir, _ = flags.GetString("workdir")
+	}
+	envFile, _ := flags.GetString("env-file")
+	options, err := cli.NewProjectOptions(configPaths,
+		cli.WithWorkingDirectory(projectDir),
+		cli.WithEnvFile(envFile),
+		cli.WithDotEnv,
+		cli.WithOsEnv)
+	if err != nil {
+		// let the compose command report invalid options
+		return nil, nil
+	}
+	// compose files set by COMPOSE_FILE or looked up in the project directory
+	// are set to the --file flag, merged in order like repeated -f flags
+	lookedUp := len(options.ConfigPaths) == 0
+	if lookedUp {
+		if options.ConfigPaths, err = composeFileEnv(options.Environment); err != nil {
+			return nil, err
+		}
+	}
+	if len(options.ConfigPaths) == 0 && projectDir != "" {
+		options.ConfigPaths = defaultComposeFiles(projectDir)
+	}
+	lookedUp = lookedUp && len(options.ConfigPaths) > 0
+	if err := cli.WithDefaultConfigPath(options); err != nil {
+		// let the compose command report missing compose files
+		return nil, nil
+	}
+
+	var paths []string
+	generated := map[string]string{}
+	contents, err := utils.ResolveComposeFiles(options.ConfigPaths, options.Environment, strict)
+	if err != nil {
+		return nil, err
+	}
+	for i, path := range options.ConfigPaths {
+		content := contents[i]
+		if content == nil {
+			paths = append(paths, path)
+			continue
+		}
+		f, err := writeResolvedFile(path, content)
+		if err != nil {
+			return generated, err
+		}
+		generated[f] = path
+		paths = append(paths, f)
+	}
+	if len(generated) == 0 && !lookedUp {
+		return nil, nil
+	}
+
+	if projectDir == "" {
+		workingDir, err := options.GetWorkingDir()
+		if err != nil {
+			return generated, err
+		}
+		if err := flags.Set("project-directory", workingDir); err != nil {
+			return generated, err
+		}
+	}
+	file, ok := flags.Lookup("file").Value.(pflag.SliceValue)
+	if !ok {
+		return generated, errors.New("unexpected type of the --file flag")
+	}
+	return generated, file.Replace(paths)
+}
+
+// composeFileEnv returns the compose files set by COMPOSE_FILE, separated by
+// COMPOSE_PATH_SEPARATOR or the platform path list separator, ':' or ';' on
+// Windows. Empty entries, as left by a trailing separator, are ignored.
+func composeFileEnv(environment map[string]string) ([]string, error) {
+	sep := environment[cli.ComposePathSeparator]
+	if sep == "" {
+		sep = string(os.PathListSeparator)
+	}
+	var paths []string
+	for _, path := range strings.Split(environment[cli.ComposeFilePath], sep) {
+		if strings.TrimSpace(path) == "" {
+			continue
+		}
+		if path != "-" {
+			abs, err := filepath.Abs(path)
+			if err != nil {
+				return nil, err
+			}
+			path = abs
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// defaultComposeFiles returns the compose file found in the directory under
+// the default names, followed by the override file if any
+func defaultComposeFiles(dir string) []string {
+	file := findFile(dir, cli.DefaultFileNames)
+	if file == "" {
+		return nil
+	}
+	if override := findFile(dir, cli.DefaultOverrideFileNames); override != "" {
+		return []string{file, override}
+	}
+	return []string{file}
+}
+
+func findFile(dir string, names []string) string {
+	for _, name := range names {
+		f := filepath.Join(dir, name)
+		if _, err := os.Stat(f); err == nil {
+			return f
+		}
+	}
+	return ""
+}
+
+func writeResolvedFile(path string, content []byte) (string, error) {
+	f, err := os.CreateTemp("", "compose-*-"+filepath.Base(path))
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(content); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), f.Close()
+}
@@ -0,0 +1,161 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+)
+
+func TestComposeFileResolution(t *testing.T) {
+	dir := t.TempDir()
+	compose := filepath.Join(dir, "compose.yaml")
+	assert.NilError(t, os.WriteFile(compose, []byte("include:\n  - db.yaml\nservices:\n  web:\n    image: nginx\n"), 0o644))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "db.yaml"), []byte("services:\n  db:\n    image: mysql\n"), 0o644))
+
+	var (
+		files      []string
+		projectDir string
+		content    []byte
+		labelled   []string
+	)
+	proxy := api.NewServiceProxy()
+	proxy.UpFn = func(ctx context.Context, project *types.Project, options api.UpOptions) error {
+		labelled = project.ComposeFiles
+		return errors.New("up failed")
+	}
+	command := &cobra.Command{Use: "compose"}
+	command.PersistentFlags().StringArrayVarP(&files, "file", "f", []string{}, "")
+	command.PersistentFlags().StringVar(&projectDir, "project-directory", "", "")
+	command.PersistentFlags().String("env-file", "", "")
+	command.AddCommand(&cobra.Command{
+		Use: "up",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if content, err = os.ReadFile(files[0]); err != nil {
+				return err
+			}
+			return proxy.Up(cmd.Context(), &types.Project{ComposeFiles: files}, api.UpOptions{})
+		},
+	})
+	cleanup := AddComposeFileResolution(command, proxy)
+
+	command.SetArgs([]string{"--file", compose, "up"})
+	assert.Error(t, command.Execute(), "up failed")
+	assert.Equal(t, projectDir, dir)
+	assert.Equal(t, len(files), 1)
+	assert.Assert(t, files[0] != compose)
+	assert.Assert(t, !strings.Contains(string(content), "include"))
+	assert.Assert(t, strings.Contains(string(content), "db:"))
+	assert.DeepEqual(t, labelled, []string{compose})
+
+	cleanup()
+	_, err := os.Stat(files[0])
+	assert.Assert(t, os.IsNotExist(err), "generated compose file not removed")
+}
+
+func TestStrictInterpolation(t *testing.T) {
+	dir := t.TempDir()
+	compose := filepath.Join(dir, "compose.yaml")
+	assert.NilError(t, os.WriteFile(compose, []byte("services:\n  web:\n    image: nginx:${COMPOSE_TEST_UNSET_TAG}\n"), 0o644))
+
+	var ran bool
+	command := &cobra.Command{Use: "compose"}
+	command.PersistentFlags().StringArrayP("file", "f", []string{}, "")
+	command.PersistentFlags().String("project-directory", "", "")
+	command.PersistentFlags().String("env-file", "", "")
+	command.AddCommand(&cobra.Command{
+		Use: "up",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ran = true
+			return nil
+		},
+	})
+	AddComposeFileResolution(command, api.NewServiceProxy())
+
+	command.SetArgs([]string{"--file", compose, "up"})
+	assert.NilError(t, command.Execute())
+	assert.Assert(t, ran)
+
+	ran = false
+	command.SetArgs([]string{"--file", compose, "--strict-interpolation", "up"})
+	assert.Error(t, command.Execute(), compose+": variables not set: COMPOSE_TEST_UNSET_TAG")
+	assert.Assert(t, !ran)
+}
+
+func TestProjectDirectoryComposeFiles(t *testing.T) {
+	dir := t.TempDir()
+	compose, override := filepath.Join(dir, "compose.yaml"), filepath.Join(dir, "compose.override.yaml")
+	assert.NilError(t, os.WriteFile(compose, []byte("services:\n  web:\n    image: nginx\n"), 0o644))
+	assert.NilError(t, os.WriteFile(override, []byte("services:\n  web:\n    build: .\n"), 0o644))
+
+	var files []string
+	command := &cobra.Command{Use: "compose"}
+	command.PersistentFlags().StringArrayVarP(&files, "file", "f", []string{}, "")
+	command.PersistentFlags().String("project-directory", "", "")
+	command.PersistentFlags().String("env-file", "", "")
+	command.AddCommand(&cobra.Command{
+		Use:  "up",
+		RunE: func(cmd *cobra.Command, args []string) error { return nil },
+	})
+	AddComposeFileResolution(command, api.NewServiceProxy())
+
+	command.SetArgs([]string{"--project-directory", dir, "up"})
+	assert.NilError(t, command.Execute())
+	assert.DeepEqual(t, files, []string{compose, override})
+}
+
+func TestComposeFileEnv(t *testing.T) {
+	dir := t.TempDir()
+	compose, override := filepath.Join(dir, "compose.yaml"), filepath.Join(dir, "override.yaml")
+	assert.NilError(t, os.WriteFile(compose, []byte("services:\n  web:\n    image: nginx\n"), 0o644))
+	assert.NilError(t, os.WriteFile(override, []byte("services:\n  web:\n    build: .\n"), 0o644))
+
+	paths, err := composeFileEnv(map[string]string{"COMPOSE_FILE": override + string(os.PathListSeparator) + compose + string(os.PathListSeparator)})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, paths, []string{override, compose})
+
+	paths, err = composeFileEnv(map[string]string{"COMPOSE_FILE": compose + "," + override, "COMPOSE_PATH_SEPARATOR": ","})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, paths, []string{compose, override})
+
+	t.Setenv("COMPOSE_FILE", compose+",,"+override)
+	t.Setenv("COMPOSE_PATH_SEPARATOR", ",")
+	var files []string
+	command := &cobra.Command{Use: "compose"}
+	command.PersistentFlags().StringArrayVarP(&files, "file", "f", []string{}, "")
+	command.PersistentFlags().String("project-directory", "", "")
+	command.PersistentFlags().String("env-file", "", "")
+	command.AddCommand(&cobra.Command{
+		Use:  "up",
+		RunE: func(cmd *cobra.Command, args []string) error { return nil },
+	})
+	AddComposeFileResolution(command, api.NewServiceProxy())
+
+	command.SetArgs([]string{"up"})
+	assert.NilError(t, command.Execute())
+	assert.DeepEqual(t, files, []string{compose, override})
+}
@@ -0,0 +1,137 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type healthcheckOptions struct {
+	disable     bool
+	cmd         string
+	interval    time.Duration
+	timeout     time.Duration
+	startPeriod time.Duration
+	retries     int
+}
+
+func (o healthcheckOptions) overridden() bool {
+	return o.cmd != "" || o.interval != 0 || o.timeout != 0 || o.startPeriod != 0 || o.retries != 0
+}
+
+// AddHealthcheckOverrides adds the --no-healthcheck and --health-* flags to
+// compose up and run, overriding the healthchecks of the services for this
+// invocation. Services without healthcheck get one from the flags, the image
+// one being used when --health-cmd isn't set.
+func AddHealthcheckOverrides(command *cobra.Command, proxy *api.ServiceProxy) {
+	var opts healthcheckOptions
+	for _, c := range command.Commands() {
+		if c.Name() == "up" || c.Name() == "run" {
+			flags := c.Flags()
+			flags.BoolVar(&opts.disable, "no-healthcheck", false, "Disable the healthchecks of the services")
+			flags.StringVar(&opts.cmd, "health-cmd", "", "Command to run to check health")
+			flags.DurationVar(&opts.interval, "health-interval", 0, "Time between running the check (ms|s|m|h)")
+			flags.DurationVar(&opts.timeout, "health-timeout", 0, "Maximum time to allow one check to run (ms|s|m|h)")
+			flags.DurationVar(&opts.startPeriod, "health-start-period", 0, "Start period for the containers to initialize before starting health-retries countdown (ms|s|m|h)")
+			flags.IntVar(&opts.retries, "health-retries", 0, "Consecutive failures needed to report unhealthy")
+		}
+	}
+
+	up, create, run := proxy.UpFn, proxy.CreateFn, proxy.RunOneOffContainerFn
+	proxy.UpFn = func(ctx context.Context, project *types.Project, options api.UpOptions) error {
+		if err := overrideHealthchecks(project, opts); err != nil {
+			return err
+		}
+		return up(ctx, project, options)
+	}
+	// compose run creates the dependencies of the service before running it
+	proxy.CreateFn = func(ctx context.Context, project *types.Project, options api.CreateOptions) error {
+		if err := overrideHealthchecks(project, opts); err != nil {
+			return err
+		}
+		return create(ctx, project, options)
+	}
+	proxy.RunOneOffContainerFn = func(ctx context.Context, project *types.Project, options api.RunOptions) (int, error) {
+		if err := overrideHealthchecks(project, opts); err != nil {
+			return 0, err
+		}
+		return run(ctx, project, options)
+	}
+}
+
+// overrideHealthchecks sets the healthchecks of the project services from the
+// options. With disabled healthchecks, services depending on others to be
+// healthy only wait for them to be started.
+func overrideHealthchecks(project *types.Project, opts healthcheckOptions) error {
+	if opts.disable && opts.overridden() {
+		return errors.New("--no-healthcheck conflicts with --health-* options")
+	}
+	if opts.retries < 0 {
+		return errors.New("--health-retries cannot be negative")
+	}
+	for i, service := range project.Services {
+		switch {
+		case opts.disable:
+			service.HealthCheck = &types.HealthCheckConfig{Disable: true}
+			for name, dependency := range service.DependsOn {
+				if dependency.Condition == types.ServiceConditionHealthy {
+					dependency.Condition = types.ServiceConditionStarted
+					service.DependsOn[name] = dependency
+				}
+			}
+		case opts.overridden():
+			service.HealthCheck = overrideHealthcheck(service.HealthCheck, opts)
+		default:
+			continue
+		}
+		project.Services[i] = service
+	}
+	return nil
+}
+
+func overrideHealthcheck(healthcheck *types.HealthCheckConfig, opts healthcheckOptions) *types.HealthCheckConfig {
+	var check types.HealthCheckConfig
+	if healthcheck != nil && !healthcheck.Disable {
+		check = *healthcheck
+	}
+	if opts.cmd != "" {
+		check.Test = types.HealthCheckTest{"CMD-SHELL", opts.cmd}
+	}
+	if opts.interval != 0 {
+		interval := types.Duration(opts.interval)
+		check.Interval = &interval
+	}
+	if opts.timeout != 0 {
+		timeout := types.Duration(opts.timeout)
+		check.Timeout = &timeout
+	}
+	if opts.startPeriod != 0 {
+		startPeriod := types.Duration(opts.startPeriod)
+		check.StartPeriod = &startPeriod
+	}
+	if opts.retries != 0 {
+		retries := uint64(opts.retries)
+		check.Retries = &retries
+	}
+	return &check
+}
@@ -0,0 +1,73 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func healthcheckProject() *types.Project {
+	interval := types.Duration(30 * time.Second)
+	return &types.Project{Services: types.Services{
+		{
+			Name: "db",
+			HealthCheck: &types.HealthCheckConfig{
+				Test:     types.HealthCheckTest{"CMD", "pg_isready"},
+				Interval: &interval,
+			},
+		},
+		{
+			Name:      "web",
+			DependsOn: types.DependsOnConfig{"db": {Condition: types.ServiceConditionHealthy}},
+		},
+	}}
+}
+
+func TestOverrideHealthchecks(t *testing.T) {
+	project := healthcheckProject()
+	assert.NilError(t, overrideHealthchecks(project, healthcheckOptions{interval: time.Second, retries: 3}))
+	interval, retries := types.Duration(time.Second), uint64(3)
+	assert.DeepEqual(t, project.Services[0].HealthCheck, &types.HealthCheckConfig{
+		Test:     types.HealthCheckTest{"CMD", "pg_isready"},
+		Interval: &interval,
+		Retries:  &retries,
+	})
+	assert.DeepEqual(t, project.Services[1].HealthCheck, &types.HealthCheckConfig{
+		Interval: &interval,
+		Retries:  &retries,
+	})
+
+	project = healthcheckProject()
+	assert.NilError(t, overrideHealthchecks(project, healthcheckOptions{cmd: "curl -f http://localhost"}))
+	assert.DeepEqual(t, project.Services[1].HealthCheck.Test, types.HealthCheckTest{"CMD-SHELL", "curl -f http://localhost"})
+
+	project = healthcheckProject()
+	assert.NilError(t, overrideHealthchecks(project, healthcheckOptions{disable: true}))
+	assert.Assert(t, project.Services[0].HealthCheck.Disable)
+	assert.Equal(t, project.Services[1].DependsOn["db"].Condition, types.ServiceConditionStarted)
+
+	project = healthcheckProject()
+	assert.NilError(t, overrideHealthchecks(project, healthcheckOptions{}))
+	assert.DeepEqual(t, project, healthcheckProject())
+
+	err := overrideHealthchecks(project, healthcheckOptions{disable: true, interval: time.Second})
+	assert.Error(t, err, "--no-healthcheck conflicts with --health-* options")
+}
@@ -0,0 +1,47 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// AddKillServices makes compose kill signal the services given as arguments
+// only, not their dependencies.
+func AddKillServices(command *cobra.Command, proxy *api.ServiceProxy) {
+	var services []string
+	for _, c := range command.Commands() {
+		if c.Name() == "kill" {
+			run := c.RunE
+			c.RunE = func(cmd *cobra.Command, args []string) error {
+				services = args
+				return run(cmd, args)
+			}
+		}
+	}
+	kill := proxy.KillFn
+	proxy.KillFn = func(ctx context.Context, project *types.Project, options api.KillOptions) error {
+		if len(options.Services) == 0 {
+			options.Services = services
+		}
+		return kill(ctx, project, options)
+	}
+}
@@ -0,0 +1,53 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+)
+
+func TestKillServices(t *testing.T) {
+	var killed []string
+	proxy := api.NewServiceProxy()
+	proxy.KillFn = func(ctx context.Context, project *types.Project, options api.KillOptions) error {
+		killed = options.Services
+		return nil
+	}
+	project := &types.Project{Name: "demo"}
+	command := &cobra.Command{Use: "compose"}
+	kill := &cobra.Command{
+		Use: "kill",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return proxy.Kill(cmd.Context(), project, api.KillOptions{})
+		},
+	}
+	command.AddCommand(kill)
+	AddKillServices(command, proxy)
+
+	command.SetArgs([]string{"kill", "web"})
+	assert.NilError(t, command.Execute())
+	assert.DeepEqual(t, killed, []string{"web"})
+
+	assert.NilError(t, proxy.Kill(context.Background(), project, api.KillOptions{Services: []string{"db"}}))
+	assert.DeepEqual(t, killed, []string{"db"})
+}
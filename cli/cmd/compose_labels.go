@@ -0,0 +1,123 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// reservedLabelPrefix is the prefix of the labels compose identifies the
+// resources of projects with
+const reservedLabelPrefix = "com.docker.compose."
+
+// AddProjectLabels adds the --label flag to compose up and create, setting
+// labels on all the resources created for the project: the containers of its
+// services and its networks and volumes. The labels compose identifies
+// project resources with can't be set, ls and down relying on them.
+func AddProjectLabels(command *cobra.Command, proxy *api.ServiceProxy) {
+	var labels []string
+	for _, c := range command.Commands() {
+		if c.Name() == "up" || c.Name() == "create" {
+			c.Flags().StringArrayVar(&labels, "label", []string{}, "Set a label on the project containers, networks and volumes")
+		}
+	}
+	up, create := proxy.UpFn, proxy.CreateFn
+	proxy.UpFn = func(ctx context.Context, project *types.Project, options api.UpOptions) error {
+		if err := setProjectLabels(project, labels); err != nil {
+			return err
+		}
+		return up(ctx, project, options)
+	}
+	proxy.CreateFn = func(ctx context.Context, project *types.Project, options api.CreateOptions) error {
+		if err := setProjectLabels(project, labels); err != nil {
+			return err
+		}
+		return create(ctx, project, options)
+	}
+}
+
+// setProjectLabels sets the key=value labels on the services and on the
+// networks and volumes created for the project, overriding the labels they
+// define with the same keys.
+func setProjectLabels(project *types.Project, labels []string) error {
+	parsed, err := parseProjectLabels(labels)
+	if err != nil || len(parsed) == 0 {
+		return err
+	}
+	for i, service := range project.Services {
+		project.Services[i].Labels = withLabels(service.Labels, parsed)
+	}
+	for name, network := range project.Networks {
+		if network.External.External {
+			continue
+		}
+		network.Labels = withLabels(network.Labels, parsed)
+		project.Networks[name] = network
+	}
+	for name, volume := range project.Volumes {
+		if volume.External.External {
+			continue
+		}
+		volume.Labels = withLabels(volume.Labels, parsed)
+		project.Volumes[name] = volume
+	}
+	return nil
+}
+
+func parseProjectLabels(labels []string) (types.Labels, error) {
+	parsed := types.Labels{}
+	var reserved []string
+	for _, label := range labels {
+		kv := strings.SplitN(label, "=", 2)
+		key := strings.TrimSpace(kv[0])
+		if key == "" {
+			return nil, errors.Errorf("invalid label %q, a key is required", label)
+		}
+		if strings.HasPrefix(key, reservedLabelPrefix) {
+			reserved = append(reserved, key)
+			continue
+		}
+		if len(kv) == 1 {
+			parsed[key] = ""
+		} else {
+			parsed[key] = kv[1]
+		}
+	}
+	if len(reserved) > 0 {
+		sort.Strings(reserved)
+		return nil, errors.Errorf("labels %s are reserved by compose to identify project resources", strings.Join(reserved, ", "))
+	}
+	return parsed, nil
+}
+
+func withLabels(labels types.Labels, added types.Labels) types.Labels {
+	merged := types.Labels{}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	for k, v := range added {
+		merged[k] = v
+	}
+	return merged
+}
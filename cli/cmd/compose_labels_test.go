@@ -0,0 +1,52 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestSetProjectLabels(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			{Name: "app", Labels: types.Labels{"team": "web", "tier": "front"}},
+			{Name: "db"},
+		},
+		Networks: types.Networks{
+			"default":  types.NetworkConfig{},
+			"external": types.NetworkConfig{External: types.External{External: true}},
+		},
+		Volumes: types.Volumes{
+			"data": types.VolumeConfig{Labels: types.Labels{"tier": "back"}},
+		},
+	}
+	err := setProjectLabels(project, []string{"team=ops", "env=prod", "release"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, project.Services[0].Labels, types.Labels{"team": "ops", "tier": "front", "env": "prod", "release": ""})
+	assert.DeepEqual(t, project.Services[1].Labels, types.Labels{"team": "ops", "env": "prod", "release": ""})
+	assert.DeepEqual(t, project.Networks["default"].Labels, types.Labels{"team": "ops", "env": "prod", "release": ""})
+	assert.Assert(t, project.Networks["external"].Labels == nil)
+	assert.DeepEqual(t, project.Volumes["data"].Labels, types.Labels{"team": "ops", "tier": "back", "env": "prod", "release": ""})
+
+	err = setProjectLabels(project, []string{"com.docker.compose.project=other", "com.docker.compose.service=app"})
+	assert.Error(t, err, "labels com.docker.compose.project, com.docker.compose.service are reserved by compose to identify project resources")
+	err = setProjectLabels(project, []string{"=value"})
+	assert.Error(t, err, `invalid label "=value", a key is required`)
+}
@@ -0,0 +1,82 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+// AddLogColors makes the logs printed by compose up and logs have a stable
+// color by service, which the compose library assigns in the order containers
+// are attached. Prefixes are printed without colors with --no-color, when
+// NO_COLOR is set or when the output isn't a terminal, unless --ansi=always.
+func AddLogColors(command *cobra.Command, proxy *api.ServiceProxy) {
+	var upFlags, logsFlags *pflag.FlagSet
+	for _, c := range command.Commands() {
+		switch c.Name() {
+		case "up":
+			upFlags = c.Flags()
+		case "logs":
+			logsFlags = c.Flags()
+		}
+	}
+	consumer := func(flags *pflag.FlagSet) api.LogConsumer {
+		noColor, _ := flags.GetBool("no-color")
+		noPrefix, _ := flags.GetBool("no-log-prefix")
+		ansi, _ := command.Flags().GetString("ansi")
+		if noAnsi, _ := command.Flags().GetBool("no-ansi"); noAnsi {
+			ansi = "never"
+		}
+		color := !noColor && useColors(ansi, os.Getenv("NO_COLOR"), isatty.IsTerminal(os.Stdout.Fd()))
+		return utils.NewLogConsumer(os.Stdout, color, !noPrefix)
+	}
+
+	up, logs := proxy.UpFn, proxy.LogsFn
+	proxy.UpFn = func(ctx context.Context, project *types.Project, options api.UpOptions) error {
+		if options.Start.Attach != nil && upFlags != nil {
+			options.Start.Attach = consumer(upFlags)
+		}
+		return up(ctx, project, options)
+	}
+	proxy.LogsFn = func(ctx context.Context, projectName string, c api.LogConsumer, options api.LogOptions) error {
+		if logsFlags != nil {
+			c = consumer(logsFlags)
+		}
+		return logs(ctx, projectName, c, options)
+	}
+}
+
+// useColors reports whether logs are colored with the --ansi mode, the
+// NO_COLOR variable value and whether the output is a terminal
+func useColors(ansi string, noColor string, terminal bool) bool {
+	switch ansi {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	return noColor == "" && terminal
+}
@@ -0,0 +1,31 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestUseColors(t *testing.T) {
+	assert.Assert(t, useColors("auto", "", true))
+	assert.Assert(t, !useColors("auto", "", false))
+	assert.Assert(t, !useColors("auto", "1", true))
+	assert.Assert(t, useColors("always", "1", false))
+	assert.Assert(t, !useColors("never", "", true))
+}
@@ -0,0 +1,112 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/cli/opts"
+	"github.com/docker/compose/v2/cmd/formatter"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// ConfigFilesLister is implemented by the backends recording the compose files
+// projects are deployed from
+type ConfigFilesLister interface {
+	// ProjectConfigFiles returns the compose files of the projects by name
+	ProjectConfigFiles(ctx context.Context) (map[string][]string, error)
+}
+
+// composeStackView is a compose project printed by `compose ls`
+type composeStackView struct {
+	Name        string
+	Status      string
+	ConfigFiles string
+}
+
+// AddLsConfigFiles makes compose ls print the compose files of the projects,
+// when the backend records them.
+func AddLsConfigFiles(command *cobra.Command, backend api.Service, service api.Service) {
+	lister, _ := service.(ConfigFilesLister)
+	for _, c := range command.Commands() {
+		if c.Name() != "ls" {
+			continue
+		}
+		c.RunE = func(cmd *cobra.Command, args []string) error {
+			return runLs(cmd.Context(), cmd, backend, lister)
+		}
+	}
+}
+
+func runLs(ctx context.Context, cmd *cobra.Command, backend api.Service, lister ConfigFilesLister) error {
+	flags := cmd.Flags()
+	format, _ := flags.GetString("format")
+	quiet, _ := flags.GetBool("quiet")
+	all, _ := flags.GetBool("all")
+	filters := flags.Lookup("filter").Value.(*opts.FilterOpt).Value()
+	if err := filters.Validate(map[string]bool{"name": true}); err != nil {
+		return err
+	}
+
+	stacks, err := backend.List(ctx, api.ListOptions{All: all})
+	if err != nil {
+		return err
+	}
+	var filtered []api.Stack
+	for _, s := range stacks {
+		if filters.Contains("name") && !filters.Match("name", s.Name) {
+			continue
+		}
+		filtered = append(filtered, s)
+	}
+	out := cmd.OutOrStdout()
+	if quiet {
+		for _, s := range filtered {
+			fmt.Fprintln(out, s.Name)
+		}
+		return nil
+	}
+
+	configFiles := map[string][]string{}
+	if lister != nil {
+		if configFiles, err = lister.ProjectConfigFiles(ctx); err != nil {
+			return err
+		}
+	}
+	view := stackViews(filtered, configFiles)
+	return formatter.Print(view, format, out, func(w io.Writer) {
+		for _, s := range view {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", s.Name, s.Status, s.ConfigFiles)
+		}
+	}, "NAME", "STATUS", "CONFIG FILES")
+}
+
+func stackViews(stacks []api.Stack, configFiles map[string][]string) []composeStackView {
+	views := make([]composeStackView, len(stacks))
+	for i, s := range stacks {
+		views[i] = composeStackView{
+			Name:        s.Name,
+			Status:      strings.TrimSpace(fmt.Sprintf("%s %s", s.Status, s.Reason)),
+			ConfigFiles: strings.Join(configFiles[s.Name], ","),
+		}
+	}
+	return views
+}
@@ -0,0 +1,37 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"gotest.tools/v3/assert"
+)
+
+func TestStackViews(t *testing.T) {
+	views := stackViews([]api.Stack{
+		{Name: "demo", Status: api.RUNNING},
+		{Name: "other", Status: api.FAILED, Reason: "task stopped"},
+	}, map[string][]string{
+		"demo": {"/src/demo/compose.yaml", "/src/demo/compose.override.yaml"},
+	})
+	assert.DeepEqual(t, views, []composeStackView{
+		{Name: "demo", Status: api.RUNNING, ConfigFiles: "/src/demo/compose.yaml,/src/demo/compose.override.yaml"},
+		{Name: "other", Status: "Failed task stopped"},
+	})
+}
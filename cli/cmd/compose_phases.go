@@ -0,0 +1,44 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+
+	"github.com/docker/compose-cli/api/phase"
+)
+
+// AddPhaseRecording records the image pull and build phases of compose
+// commands, see phase.Start.
+func AddPhaseRecording(proxy *api.ServiceProxy) {
+	pull, build := proxy.PullFn, proxy.BuildFn
+	proxy.PullFn = func(ctx context.Context, project *types.Project, options api.PullOptions) error {
+		end := phase.Start(ctx, phase.ImagePull)
+		err := pull(ctx, project, options)
+		end(err)
+		return err
+	}
+	proxy.BuildFn = func(ctx context.Context, project *types.Project, options api.BuildOptions) error {
+		end := phase.Start(ctx, phase.Build)
+		err := build(ctx, project, options)
+		end(err)
+		return err
+	}
+}
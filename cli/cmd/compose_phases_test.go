@@ -0,0 +1,53 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/phase"
+)
+
+func TestPhaseRecording(t *testing.T) {
+	proxy := api.NewServiceProxy()
+	proxy.PullFn = func(ctx context.Context, project *types.Project, options api.PullOptions) error {
+		return nil
+	}
+	proxy.BuildFn = func(ctx context.Context, project *types.Project, options api.BuildOptions) error {
+		return errors.New("build failed")
+	}
+	AddPhaseRecording(proxy)
+	recorder := phase.NewRecorder()
+	ctx := phase.WithRecorder(context.Background(), recorder)
+	project := &types.Project{Name: "demo"}
+
+	assert.NilError(t, proxy.Pull(ctx, project, api.PullOptions{}))
+	assert.Error(t, proxy.Build(ctx, project, api.BuildOptions{}), "build failed")
+
+	phases := recorder.Phases()
+	assert.Equal(t, len(phases), 2)
+	assert.Equal(t, phases[0].Name, phase.ImagePull)
+	assert.NilError(t, phases[0].Err)
+	assert.Equal(t, phases[1].Name, phase.Build)
+	assert.Error(t, phases[1].Err, "build failed")
+}
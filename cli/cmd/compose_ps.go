@@ -0,0 +1,219 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/templates"
+	compose "github.com/docker/compose/v2/cmd/compose"
+	"github.com/docker/compose/v2/cmd/formatter"
+	"github.com/docker/compose/v2/pkg/api"
+	utils2 "github.com/docker/compose/v2/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/docker/compose-cli/api/context/store"
+	"github.com/docker/compose-cli/utils"
+)
+
+// composeContainerView is the stable description of a compose container
+// printed by `compose ps --format`, as JSON or through a Go template.
+type composeContainerView struct {
+	ID         string
+	Name       string
+	Command    string
+	Project    string
+	Service    string
+	State      string
+	Health     string
+	ExitCode   int
+	Ports      string
+	Publishers []api.PortPublisher
+}
+
+// AddPsFormat makes the --format flag of compose ps accept a Go template,
+// executed for each container, and prints JSON as a list of
+// composeContainerView.
+func AddPsFormat(command *cobra.Command, backend api.Service) {
+	for _, c := range command.Commands() {
+		if c.Name() != "ps" {
+			continue
+		}
+		ps := c
+		run := ps.RunE
+		ps.Flags().Lookup("format").Usage = "Format the output. Values: [pretty | json | TEMPLATE]"
+		ps.RunE = func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+			format, _ := flags.GetString("format")
+			quiet, _ := flags.GetBool("quiet")
+			services, _ := flags.GetBool("services")
+			if quiet || services || format == "" || strings.EqualFold(format, formatter.PRETTY) {
+				return run(cmd, args)
+			}
+			return runPsFormat(cmd.Context(), cmd, backend, args, format)
+		}
+	}
+}
+
+// AddPsFilters makes the --filter flag of compose ps repeatable and accept
+// label and name filters besides the status one. The filters and the --status
+// values are passed to the backend through the context, for the engine to only
+// list the matching containers. On the other context types, only status
+// filters are supported, applied to the listed containers.
+func AddPsFilters(command *cobra.Command, proxy *api.ServiceProxy, contextType string) {
+	var (
+		values   []string
+		statuses []string
+	)
+	for _, c := range command.Commands() {
+		if c.Name() != "ps" {
+			continue
+		}
+		// the compose library flag only accepts a single value
+		repeatable := pflag.NewFlagSet("ps", pflag.ContinueOnError)
+		repeatable.StringArrayVar(&values, "filter", nil, "")
+		flag := c.Flags().Lookup("filter")
+		flag.Value = repeatable.Lookup("filter").Value
+		flag.DefValue = "[]"
+		flag.Usage = "Filter containers by a property (label=KEY[=VALUE], name=NAME, status=STATUS)"
+		flag.Hidden = false
+		flags := c.Flags()
+		run := c.RunE
+		c.RunE = func(cmd *cobra.Command, args []string) error {
+			statuses, _ = flags.GetStringArray("status")
+			return run(cmd, args)
+		}
+	}
+
+	ps := proxy.PsFn
+	proxy.PsFn = func(ctx context.Context, projectName string, options api.PsOptions) ([]api.ContainerSummary, error) {
+		f, err := utils.ParsePsFilters(values)
+		if err != nil {
+			return nil, err
+		}
+		for _, status := range statuses {
+			f.Add("status", status)
+		}
+		if f.Len() == 0 {
+			return ps(ctx, projectName, options)
+		}
+		if contextType == store.LocalContextType {
+			return ps(utils.WithPsFilters(ctx, f), projectName, options)
+		}
+		for _, key := range []string{"label", "name"} {
+			if f.Contains(key) {
+				return nil, errors.Errorf("--filter %s is not supported on context type %s", key, contextType)
+			}
+		}
+		containers, err := ps(ctx, projectName, options)
+		if err != nil {
+			return nil, err
+		}
+		var filtered []api.ContainerSummary
+		for _, c := range containers {
+			if f.ExactMatch("status", c.State) {
+				filtered = append(filtered, c)
+			}
+		}
+		return filtered, nil
+	}
+}
+
+func runPsFormat(ctx context.Context, cmd *cobra.Command, backend api.Service, services []string, format string) error {
+	flags := cmd.Flags()
+	all, _ := flags.GetBool("all")
+	statuses, _ := flags.GetStringArray("status")
+
+	projectName, err := composeProjectName(cmd)
+	if err != nil {
+		return err
+	}
+	containers, err := backend.Ps(ctx, projectName, api.PsOptions{
+		All:      all,
+		Services: services,
+	})
+	if err != nil {
+		return err
+	}
+	views, err := containerViews(containers, services, statuses)
+	if err != nil {
+		return err
+	}
+	return printContainerViews(cmd.OutOrStdout(), views, format)
+}
+
+func containerViews(containers []api.ContainerSummary, services []string, statuses []string) ([]composeContainerView, error) {
+SERVICES:
+	for _, s := range services {
+		for _, c := range containers {
+			if c.Service == s {
+				continue SERVICES
+			}
+		}
+		return nil, fmt.Errorf("no such service: %s", s)
+	}
+
+	views := []composeContainerView{}
+	for _, c := range containers {
+		if len(statuses) > 0 && !utils2.StringContains(statuses, c.State) {
+			continue
+		}
+		publishers := c.Publishers
+		if publishers == nil {
+			publishers = api.PortPublishers{}
+		}
+		views = append(views, composeContainerView{
+			ID:         c.ID,
+			Name:       c.Name,
+			Command:    c.Command,
+			Project:    c.Project,
+			Service:    c.Service,
+			State:      c.State,
+			Health:     c.Health,
+			ExitCode:   c.ExitCode,
+			Ports:      compose.DisplayablePorts(c),
+			Publishers: publishers,
+		})
+	}
+	sort.Slice(views, func(i, j int) bool {
+		return views[i].Name < views[j].Name
+	})
+	return views, nil
+}
+
+func printContainerViews(out io.Writer, views []composeContainerView, format string) error {
+	if strings.EqualFold(format, formatter.JSON) {
+		return formatter.Print(views, formatter.JSON, out, nil)
+	}
+	tmpl, err := templates.Parse(format)
+	if err != nil {
+		return errors.Wrapf(api.ErrParsingFailed, "format value %q could not be parsed: %s", format, err)
+	}
+	for _, view := range views {
+		if err := tmpl.Execute(out, view); err != nil {
+			return err
+		}
+		fmt.Fprintln(out)
+	}
+	return nil
+}
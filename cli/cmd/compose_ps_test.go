@@ -0,0 +1,139 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/context/store"
+	"github.com/docker/compose-cli/utils"
+)
+
+var testContainers = []api.ContainerSummary{
+	{
+		ID:      "456",
+		Name:    "demo-web-1",
+		Project: "demo",
+		Service: "web",
+		State:   "running",
+		Health:  "healthy",
+		Publishers: api.PortPublishers{
+			{URL: "0.0.0.0", TargetPort: 80, PublishedPort: 8080, Protocol: "tcp"},
+		},
+	},
+	{
+		ID:       "123",
+		Name:     "demo-db-1",
+		Project:  "demo",
+		Service:  "db",
+		State:    "exited",
+		ExitCode: 1,
+	},
+}
+
+func TestContainerViews(t *testing.T) {
+	views, err := containerViews(testContainers, nil, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(views), 2)
+	assert.Equal(t, views[0].Name, "demo-db-1")
+	assert.Equal(t, views[1].Ports, "0.0.0.0:8080->80/tcp")
+
+	views, err = containerViews(testContainers, nil, []string{"running"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(views), 1)
+	assert.Equal(t, views[0].Service, "web")
+
+	_, err = containerViews(testContainers, []string{"cache"}, nil)
+	assert.Error(t, err, "no such service: cache")
+}
+
+func TestPrintContainerViewsTemplate(t *testing.T) {
+	views, err := containerViews(testContainers, nil, nil)
+	assert.NilError(t, err)
+
+	var out bytes.Buffer
+	assert.NilError(t, printContainerViews(&out, views, "{{.ID}} {{.Service}} {{.Health}} {{range .Publishers}}{{.PublishedPort}}{{end}}"))
+	assert.Equal(t, out.String(), "123 db  \n456 web healthy 8080\n")
+
+	out.Reset()
+	assert.NilError(t, printContainerViews(&out, views, "{{json .Name}}"))
+	assert.Equal(t, out.String(), "\"demo-db-1\"\n\"demo-web-1\"\n")
+
+	err = printContainerViews(&out, views, "{{.ID")
+	assert.ErrorContains(t, err, "could not be parsed")
+}
+
+func TestPrintContainerViewsJSON(t *testing.T) {
+	views, err := containerViews(testContainers[1:], nil, nil)
+	assert.NilError(t, err)
+
+	var out bytes.Buffer
+	assert.NilError(t, printContainerViews(&out, views, "json"))
+	assert.Equal(t, out.String(), `[{"ID":"123","Name":"demo-db-1","Command":"","Project":"demo","Service":"db","State":"exited",`+
+		`"Health":"","ExitCode":1,"Ports":"","Publishers":[]}]`+"\n")
+}
+
+func TestPsFilters(t *testing.T) {
+	psCommand := func(contextType string, fn func(ctx context.Context, projectName string, options api.PsOptions) ([]api.ContainerSummary, error)) (*cobra.Command, *[]api.ContainerSummary) {
+		var listed []api.ContainerSummary
+		proxy := api.NewServiceProxy()
+		proxy.PsFn = fn
+		compose := &cobra.Command{Use: "compose"}
+		ps := &cobra.Command{Use: "ps", RunE: func(cmd *cobra.Command, args []string) error {
+			containers, err := proxy.Ps(cmd.Context(), "demo", api.PsOptions{})
+			listed = containers
+			return err
+		}}
+		ps.Flags().String("filter", "", "")
+		ps.Flags().StringArray("status", []string{}, "")
+		compose.AddCommand(ps)
+		AddPsFilters(compose, proxy, contextType)
+		return compose, &listed
+	}
+
+	var f filters.Args
+	compose, _ := psCommand(store.LocalContextType, func(ctx context.Context, projectName string, options api.PsOptions) ([]api.ContainerSummary, error) {
+		f = utils.PsFilters(ctx)
+		return testContainers, nil
+	})
+	compose.SetArgs([]string{"ps", "--filter", "label=tier=front", "--filter", "name=web", "--status", "running"})
+	assert.NilError(t, compose.Execute())
+	assert.DeepEqual(t, f.Get("label"), []string{"tier=front"})
+	assert.DeepEqual(t, f.Get("name"), []string{"web"})
+	assert.DeepEqual(t, f.Get("status"), []string{"running"})
+
+	list := func(ctx context.Context, projectName string, options api.PsOptions) ([]api.ContainerSummary, error) {
+		assert.Equal(t, utils.PsFilters(ctx).Len(), 0)
+		return testContainers, nil
+	}
+	compose, _ = psCommand(store.AciContextType, list)
+	compose.SetArgs([]string{"ps", "--filter", "label=tier=front"})
+	assert.Error(t, compose.Execute(), "--filter label is not supported on context type aci")
+
+	compose, listed := psCommand(store.AciContextType, list)
+	compose.SetArgs([]string{"ps", "--filter", "status=running"})
+	assert.NilError(t, compose.Execute())
+	assert.Equal(t, len(*listed), 1)
+	assert.Equal(t, (*listed)[0].Name, "demo-web-1")
+}
@@ -0,0 +1,97 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// ScaleCommand sets the number of replicas of services of a running project.
+// The other services are left as is, existing containers aren't recreated.
+func ScaleCommand(backend api.Service) *cobra.Command {
+	return &cobra.Command{
+		Use:   "scale SERVICE=REPLICAS...",
+		Short: "Scale services",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scales, err := parseScales(args)
+			if err != nil {
+				return err
+			}
+			project, err := composeProject(cmd, nil)
+			if err != nil {
+				return err
+			}
+			services := make([]string, 0, len(scales))
+			for name, replicas := range scales {
+				if err := setServiceReplicas(project, name, replicas); err != nil {
+					return err
+				}
+				services = append(services, name)
+			}
+			return backend.Up(cmd.Context(), project, api.UpOptions{
+				Create: api.CreateOptions{
+					Services:             services,
+					Recreate:             api.RecreateNever,
+					RecreateDependencies: api.RecreateNever,
+					Inherit:              true,
+				},
+			})
+		},
+	}
+}
+
+// parseScales parses SERVICE=REPLICAS arguments
+func parseScales(args []string) (map[string]uint64, error) {
+	scales := map[string]uint64{}
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid scale %q, should be SERVICE=REPLICAS", arg)
+		}
+		replicas, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number of replicas %q for service %s", value, name)
+		}
+		scales[name] = replicas
+	}
+	return scales, nil
+}
+
+func setServiceReplicas(project *types.Project, name string, replicas uint64) error {
+	for i, service := range project.Services {
+		if service.Name != name {
+			continue
+		}
+		if service.Deploy == nil {
+			service.Deploy = &types.DeployConfig{}
+		}
+		if service.Deploy.Mode == "global" {
+			return fmt.Errorf("service %q is global and can't be scaled", name)
+		}
+		service.Deploy.Replicas = &replicas
+		project.Services[i] = service
+		return nil
+	}
+	return fmt.Errorf("no such service: %s", name)
+}
@@ -0,0 +1,47 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestParseScales(t *testing.T) {
+	scales, err := parseScales([]string{"web=3", "worker=0"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, scales, map[string]uint64{"web": 3, "worker": 0})
+
+	_, err = parseScales([]string{"web"})
+	assert.Error(t, err, `invalid scale "web", should be SERVICE=REPLICAS`)
+	_, err = parseScales([]string{"web=-1"})
+	assert.Error(t, err, `invalid number of replicas "-1" for service web`)
+}
+
+func TestSetServiceReplicas(t *testing.T) {
+	project := &types.Project{Services: types.Services{
+		{Name: "web"},
+		{Name: "agent", Deploy: &types.DeployConfig{Mode: "global"}},
+	}}
+	assert.NilError(t, setServiceReplicas(project, "web", 3))
+	assert.Equal(t, *project.Services[0].Deploy.Replicas, uint64(3))
+
+	assert.Error(t, setServiceReplicas(project, "agent", 2), `service "agent" is global and can't be scaled`)
+	assert.Error(t, setServiceReplicas(project, "db", 2), "no such service: db")
+}
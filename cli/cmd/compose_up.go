@@ -0,0 +1,105 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// AddNoAttach adds the --no-attach flag to compose up, excluding services
+// from the ones selected by --attach and --attach-dependencies, or from all
+// the project services, so that their logs are not streamed.
+func AddNoAttach(command *cobra.Command, proxy *api.ServiceProxy) {
+	var noAttach []string
+	for _, c := range command.Commands() {
+		if c.Name() == "up" {
+			c.Flags().StringArrayVar(&noAttach, "no-attach", []string{}, "Don't attach to service output.")
+		}
+	}
+	up := proxy.UpFn
+	proxy.UpFn = func(ctx context.Context, project *types.Project, options api.UpOptions) error {
+		if len(noAttach) == 0 {
+			return up(ctx, project, options)
+		}
+		if options.Start.Attach == nil {
+			return errors.New("--detach cannot be combined with --no-attach")
+		}
+		attachTo, err := attachedServices(project, options.Start.AttachTo, noAttach)
+		if err != nil {
+			return err
+		}
+		options.Start.AttachTo = attachTo
+		return up(ctx, project, options)
+	}
+}
+
+// attachedServices returns the services to attach to, all the project
+// services when none is selected, except the ones excluded by --no-attach.
+func attachedServices(project *types.Project, attachTo, noAttach []string) ([]string, error) {
+	for _, name := range noAttach {
+		if _, err := project.GetService(name); err != nil {
+			return nil, err
+		}
+	}
+	if len(attachTo) == 0 {
+		attachTo = project.ServiceNames()
+	}
+	var services []string
+	for _, name := range attachTo {
+		if !utils.StringContains(noAttach, name) {
+			services = append(services, name)
+		}
+	}
+	if len(services) == 0 {
+		return nil, errors.New("--no-attach excludes all the attached services, use --detach instead")
+	}
+	return services, nil
+}
+
+// AddUpWaitTimeout adds the --wait-timeout flag to compose up, bounding how
+// long --wait blocks for services to be running or healthy.
+func AddUpWaitTimeout(command *cobra.Command, proxy *api.ServiceProxy) {
+	var timeout time.Duration
+	for _, c := range command.Commands() {
+		if c.Name() == "up" {
+			c.Flags().DurationVar(&timeout, "wait-timeout", 0, "Maximum duration to wait for services to be running|healthy when using --wait")
+		}
+	}
+	up := proxy.UpFn
+	proxy.UpFn = func(ctx context.Context, project *types.Project, options api.UpOptions) error {
+		if timeout == 0 {
+			return up(ctx, project, options)
+		}
+		if !options.Start.Wait {
+			return errors.New("--wait-timeout can only be used with --wait")
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		err := up(ctx, project, options)
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return errors.Errorf("services did not become running or healthy within %s", timeout)
+		}
+		return err
+	}
+}
@@ -0,0 +1,74 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+)
+
+func TestAttachedServices(t *testing.T) {
+	project := &types.Project{Services: types.Services{
+		{Name: "db"},
+		{Name: "cache"},
+		{Name: "web"},
+	}}
+
+	services, err := attachedServices(project, nil, []string{"db"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, services, []string{"cache", "web"})
+
+	services, err = attachedServices(project, []string{"db", "web"}, []string{"db"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, services, []string{"web"})
+
+	_, err = attachedServices(project, []string{"db"}, []string{"db"})
+	assert.ErrorContains(t, err, "--no-attach excludes all the attached services")
+
+	_, err = attachedServices(project, nil, []string{"queue"})
+	assert.ErrorContains(t, err, "queue")
+}
+
+func TestUpWaitTimeout(t *testing.T) {
+	proxy := api.NewServiceProxy()
+	proxy.UpFn = func(ctx context.Context, project *types.Project, options api.UpOptions) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	command := &cobra.Command{Use: "compose"}
+	up := &cobra.Command{Use: "up"}
+	command.AddCommand(up)
+	AddUpWaitTimeout(command, proxy)
+	assert.NilError(t, up.Flags().Set("wait-timeout", "10ms"))
+	project := &types.Project{Name: "demo"}
+
+	err := proxy.Up(context.Background(), project, api.UpOptions{})
+	assert.Error(t, err, "--wait-timeout can only be used with --wait")
+
+	err = proxy.Up(context.Background(), project, api.UpOptions{Start: api.StartOptions{Wait: true}})
+	assert.Error(t, err, "services did not become running or healthy within 10ms")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = proxy.Up(ctx, project, api.UpOptions{Start: api.StartOptions{Wait: true}})
+	assert.Equal(t, err, context.Canceled)
+}
@@ -0,0 +1,165 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"sort"
+	"strings"
+
+	"github.com/docker/compose/v2/cmd/formatter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/cli/mobycli"
+	"github.com/docker/compose-cli/internal"
+)
+
+const (
+	composeModule    = "github.com/docker/compose/v2"
+	composeGoModule  = "github.com/compose-spec/compose-go"
+	unknownVersion   = "unknown"
+	buildxPluginName = "buildx"
+)
+
+// composeVersion is the version information printed by compose version, for
+// tooling to check the features available
+type composeVersion struct {
+	Version          string `json:"version"`
+	CloudIntegration string `json:"cloudIntegration"`
+	// ComposeSpec is the version of compose-go, embedding the compose
+	// specification schema projects are validated against
+	ComposeSpec string            `json:"composeSpec"`
+	Backend     string            `json:"backend"`
+	CLI         string            `json:"cli,omitempty"`
+	Plugins     map[string]string `json:"plugins,omitempty"`
+}
+
+// AddComposeVersion replaces the compose library's version command, which
+// only prints its own version, by one also printing the compose
+// specification, backend and plugin versions.
+func AddComposeVersion(command *cobra.Command, contextType string) {
+	for _, c := range command.Commands() {
+		if c.Name() == "version" {
+			command.RemoveCommand(c)
+		}
+	}
+	command.AddCommand(composeVersionCommand(contextType))
+}
+
+func composeVersionCommand(contextType string) *cobra.Command {
+	var (
+		format string
+		short  bool
+	)
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Show the Docker Compose version information",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			version := getComposeVersion(cmd.Context(), contextType)
+			if short {
+				_, err := fmt.Fprintln(cmd.OutOrStdout(), version.Version)
+				return err
+			}
+			return printComposeVersion(cmd.OutOrStdout(), version, format)
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVarP(&format, "format", "f", formatter.PRETTY, "Format the output. Values: [pretty | json]")
+	flags.BoolVar(&short, "short", false, "Shows only Compose's version number.")
+	return cmd
+}
+
+func getComposeVersion(ctx context.Context, contextType string) composeVersion {
+	version := composeVersion{
+		Version:          moduleVersion(composeModule),
+		CloudIntegration: internal.Version,
+		ComposeSpec:      moduleVersion(composeGoModule),
+		Backend:          contextType,
+		CLI:              mobycli.CliVersion(),
+	}
+	if out, err := mobycli.ExecSilent(ctx, buildxPluginName, "version"); err == nil {
+		if v := parseBuildxVersion(string(out)); v != "" {
+			version.Plugins = map[string]string{buildxPluginName: v}
+		}
+	}
+	return version
+}
+
+func printComposeVersion(out io.Writer, version composeVersion, format string) error {
+	switch format {
+	case formatter.JSON:
+		b, err := json.Marshal(version)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(b))
+		return err
+	case formatter.PRETTY, "":
+		fmt.Fprintf(out, "Docker Compose version %s\n", version.Version)
+		fmt.Fprintf(out, " Cloud integration: %s\n", version.CloudIntegration)
+		fmt.Fprintf(out, " Compose specification: %s\n", version.ComposeSpec)
+		fmt.Fprintf(out, " Backend: %s\n", version.Backend)
+		if version.CLI != "" {
+			fmt.Fprintf(out, " Docker CLI: %s\n", version.CLI)
+		}
+		var plugins []string
+		for name := range version.Plugins {
+			plugins = append(plugins, name)
+		}
+		sort.Strings(plugins)
+		for _, name := range plugins {
+			fmt.Fprintf(out, " Plugin %s: %s\n", name, version.Plugins[name])
+		}
+		return nil
+	default:
+		return errors.Errorf("invalid format %q, must be %q or %q", format, formatter.PRETTY, formatter.JSON)
+	}
+}
+
+// moduleVersion returns the version of a module this binary is built with
+func moduleVersion(path string) string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return unknownVersion
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != path {
+			continue
+		}
+		if dep.Replace != nil {
+			return dep.Replace.Version
+		}
+		return dep.Version
+	}
+	return unknownVersion
+}
+
+// parseBuildxVersion returns the version from the `docker buildx version`
+// output, e.g. "github.com/docker/buildx v0.8.2 6224def4dd2c"
+func parseBuildxVersion(out string) string {
+	fields := strings.Fields(out)
+	if len(fields) < 2 {
+		return ""
+	}
+	return fields[1]
+}
@@ -0,0 +1,54 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestPrintComposeVersion(t *testing.T) {
+	version := composeVersion{
+		Version:          "v2.2.0",
+		CloudIntegration: "v1.0.24",
+		ComposeSpec:      "v1.0.8",
+		Backend:          "ecs",
+		Plugins:          map[string]string{"buildx": "v0.8.2"},
+	}
+
+	var out bytes.Buffer
+	assert.NilError(t, printComposeVersion(&out, version, "json"))
+	assert.Equal(t, out.String(), `{"version":"v2.2.0","cloudIntegration":"v1.0.24","composeSpec":"v1.0.8","backend":"ecs","plugins":{"buildx":"v0.8.2"}}`+"\n")
+
+	out.Reset()
+	assert.NilError(t, printComposeVersion(&out, version, "pretty"))
+	assert.Equal(t, out.String(), `Docker Compose version v2.2.0
+ Cloud integration: v1.0.24
+ Compose specification: v1.0.8
+ Backend: ecs
+ Plugin buildx: v0.8.2
+`)
+
+	assert.ErrorContains(t, printComposeVersion(&out, version, "yaml"), `invalid format "yaml"`)
+}
+
+func TestParseBuildxVersion(t *testing.T) {
+	assert.Equal(t, parseBuildxVersion("github.com/docker/buildx v0.8.2 6224def4dd2c3d347eee19db595348c50d7cb491\n"), "v0.8.2")
+	assert.Equal(t, parseBuildxVersion(""), "")
+}
@@ -0,0 +1,83 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+// AddConfigModel makes compose config, the alias of compose convert, print
+// the resolved compose model as YAML or JSON whatever the context type, where
+// compose convert prints the context type's format, e.g. CloudFormation. It
+// also adds the --environment flag, printing the variables the compose files
+// are interpolated with.
+func AddConfigModel(command *cobra.Command, proxy *api.ServiceProxy) {
+	var (
+		config      bool
+		environment bool
+	)
+	for _, c := range command.Commands() {
+		if c.Name() != "convert" {
+			continue
+		}
+		c.Flags().BoolVar(&environment, "environment", false, "Print the environment variables used for interpolation, one per line.")
+		run := c.RunE
+		c.RunE = func(cmd *cobra.Command, args []string) error {
+			config = cmd.CalledAs() == "config"
+			if !environment {
+				return run(cmd, args)
+			}
+			project, err := composeProject(cmd, args)
+			if err != nil {
+				return err
+			}
+			printEnvironment(cmd.OutOrStdout(), project.Environment)
+			return nil
+		}
+	}
+	convert := proxy.ConvertFn
+	proxy.ConvertFn = func(ctx context.Context, project *types.Project, options api.ConvertOptions) ([]byte, error) {
+		if !config {
+			return convert(ctx, project, options)
+		}
+		return utils.MarshalProject(project, options.Format)
+	}
+}
+
+// printEnvironment prints the variables compose files are interpolated with,
+// sorted by name. Variables set in the process environment take precedence
+// over the ones of the --env-file file, read instead of the .env file of the
+// project directory when set.
+func printEnvironment(out io.Writer, environment map[string]string) {
+	var names []string
+	for name := range environment {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "%s=%s\n", name, environment[name])
+	}
+}
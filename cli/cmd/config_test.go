@@ -0,0 +1,122 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	compose "github.com/docker/compose/v2/cmd/compose"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+)
+
+func newTestConfigCommand(converted string) *cobra.Command {
+	proxy := api.NewServiceProxy()
+	proxy.ConvertFn = func(ctx context.Context, project *types.Project, options api.ConvertOptions) ([]byte, error) {
+		return []byte(converted), nil
+	}
+	command := compose.RootCommand(proxy)
+	AddConfigModel(command, proxy)
+	AddConvertOutput(command, proxy)
+	// the compose command runs the pre-run hook of its root
+	root := &cobra.Command{Use: "docker", TraverseChildren: true}
+	root.AddCommand(command)
+	return root
+}
+
+func TestConfigJSON(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "compose.yaml")
+	assert.NilError(t, os.WriteFile(file, []byte(`services:
+  web:
+    image: nginx
+    command: echo $$HOME
+`), 0o600))
+
+	output := filepath.Join(dir, "config.json")
+	command := newTestConfigCommand("converted")
+	command.SetArgs([]string{"compose", "-f", file, "-p", "demo", "config", "--format", "json", "--output", output})
+	assert.NilError(t, command.Execute())
+	b, err := os.ReadFile(output)
+	assert.NilError(t, err)
+	var project struct {
+		Services map[string]struct {
+			Image   string   `json:"image"`
+			Command []string `json:"command"`
+		} `json:"services"`
+	}
+	assert.NilError(t, json.Unmarshal(b, &project))
+	assert.Equal(t, project.Services["web"].Image, "nginx")
+	assert.DeepEqual(t, project.Services["web"].Command, []string{"echo", "$$HOME"})
+
+	// convert prints the context type's format
+	command = newTestConfigCommand("converted")
+	command.SetArgs([]string{"compose", "-f", file, "-p", "demo", "convert", "--output", output})
+	assert.NilError(t, command.Execute())
+	b, err = os.ReadFile(output)
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), "converted")
+
+	// the compose library flags are kept
+	config, _, err := command.Find([]string{"compose", "config"})
+	assert.NilError(t, err)
+	for _, flag := range []string{"images", "hash", "resolve-image-digests", "no-normalize", "services", "volumes", "profiles", "environment"} {
+		assert.Assert(t, config.Flags().Lookup(flag) != nil, flag)
+	}
+}
+
+func TestConfigEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "compose.yaml")
+	assert.NilError(t, os.WriteFile(file, []byte("services:\n  web:\n    image: nginx:${TAG}\n"), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("TAG=latest\nSTAGE=dev\n"), 0o600))
+	staging := filepath.Join(dir, "staging.env")
+	assert.NilError(t, os.WriteFile(staging, []byte("TAG=1.21\nSTAGE=staging\nREGION=eu\n"), 0o600))
+	t.Setenv("REGION", "us")
+
+	environment := func(args ...string) map[string]string {
+		command := newTestConfigCommand("")
+		var out bytes.Buffer
+		command.SetOut(&out)
+		command.SetArgs(append(append([]string{"compose", "-f", file}, args...), "config", "--environment"))
+		assert.NilError(t, command.Execute())
+		env := map[string]string{}
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			kv := strings.SplitN(line, "=", 2)
+			env[kv[0]] = kv[1]
+		}
+		return env
+	}
+
+	env := environment()
+	assert.Equal(t, env["TAG"], "latest")
+	assert.Equal(t, env["STAGE"], "dev")
+	assert.Equal(t, env["REGION"], "us")
+
+	env = environment("--env-file", staging)
+	assert.Equal(t, env["TAG"], "1.21")
+	assert.Equal(t, env["STAGE"], "staging")
+	assert.Equal(t, env["REGION"], "us")
+}
@@ -0,0 +1,78 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/config"
+	"github.com/docker/compose-cli/cli/metrics"
+)
+
+// MetricsCommand inspects the usage metrics reported by the CLI
+func MetricsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "metrics",
+		Short:  "Inspect usage metrics",
+		Hidden: true,
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "dump [COMMAND...]",
+		Short: "Print the payloads reported by the last command, or that a command would report",
+		Long: `Print the payloads reported by the last command, or that the given command would report, as JSON lines.
+Payloads are shown as they are posted, after redaction.`,
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return printPreviewPayloads(cmd, args)
+			}
+			b, err := metrics.LastPayloads(metrics.Dir())
+			if os.IsNotExist(err) {
+				return fmt.Errorf("no metrics reported yet")
+			}
+			if err != nil {
+				return err
+			}
+			_, err = cmd.OutOrStdout().Write(b)
+			return err
+		},
+	})
+	return cmd
+}
+
+func printPreviewPayloads(cmd *cobra.Command, args []string) error {
+	ctype, _ := cmd.Context().Value(config.ContextTypeKey).(string)
+	payloads := metrics.PreviewPayloads(metrics.CmdResult{
+		ContextType: ctype,
+		Args:        args,
+		Status:      metrics.SuccessStatus,
+	})
+	if len(payloads) == 0 {
+		return fmt.Errorf("no metrics would be reported for %q", args)
+	}
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	for _, payload := range payloads {
+		if err := enc.Encode(payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/config"
+)
+
+func TestMetricsDumpWithConfigFlag(t *testing.T) {
+	defaultDir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", defaultDir)
+	previous := config.Dir()
+	config.WithDir(t.TempDir())
+	defer config.WithDir(previous)
+
+	payload := `{"path":"/usage","body":{"command":"ps"}}` + "\n"
+	assert.NilError(t, os.WriteFile(filepath.Join(defaultDir, "metrics-last.jsonl"), []byte(payload), 0o600))
+
+	cmd := MetricsCommand()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"dump"})
+	assert.NilError(t, cmd.Execute())
+	assert.Equal(t, out.String(), payload)
+}
@@ -18,11 +18,13 @@ package cmd
 
 import (
 	"context"
+	"os"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/docker/compose-cli/cli/metrics"
 	"github.com/docker/compose-cli/cli/server"
 	containersv1 "github.com/docker/compose-cli/cli/server/protos/containers/v1"
 	contextsv1 "github.com/docker/compose-cli/cli/server/protos/contexts/v1"
@@ -32,7 +34,8 @@ import (
 )
 
 type serveOpts struct {
-	address string
+	address        string
+	metricsAddress string
 }
 
 // ServeCommand returns the command to serve the API
@@ -48,12 +51,30 @@ func ServeCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&opts.address, "address", "", "The address to listen to")
+	cmd.Flags().StringVar(&opts.metricsAddress, "metrics-address", os.Getenv(metrics.EnvVarPrometheusAddress), "Localhost address to expose Prometheus metrics on, e.g. 127.0.0.1:9464")
 
 	return cmd
 }
 
 func runServe(ctx context.Context, opts serveOpts) error {
-	s := server.New(ctx)
+	reporter := metrics.NewDefaultReporter()
+	if opts.metricsAddress != "" {
+		metricsListener, err := metrics.ListenPrometheus(opts.metricsAddress)
+		if err != nil {
+			return errors.Wrap(err, "metrics address "+opts.metricsAddress)
+		}
+		prometheusReporter := metrics.NewPrometheusReporter()
+		reporter = metrics.NewMuxReporter(reporter, prometheusReporter)
+		go func() {
+			if err := prometheusReporter.Serve(ctx, metricsListener); err != nil {
+				logrus.WithError(err).Error("unable to serve Prometheus metrics")
+			}
+		}()
+		logrus.WithField("address", opts.metricsAddress).Info("serving Prometheus metrics")
+	}
+	metricsClient := metrics.NewClient(reporter)
+	defer metricsClient.Close()
+	s := server.New(ctx, metricsClient)
 
 	listener, err := server.CreateListener(opts.address)
 	if err != nil {
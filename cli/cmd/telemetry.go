@@ -0,0 +1,72 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/cli/metrics"
+)
+
+// TelemetryCommand manages the usage metrics reporting consent, kept in the
+// default configuration directory whatever the --config flag, see metrics.Dir.
+func TelemetryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "telemetry",
+		Short: "Manage usage telemetry reporting",
+	}
+	cmd.AddCommand(
+		telemetrySetCommand("on", "Enable usage telemetry reporting", true),
+		telemetrySetCommand("off", "Disable usage telemetry reporting", false),
+		&cobra.Command{
+			Use:   "status",
+			Short: "Show whether usage telemetry is reported",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				printTelemetryStatus(cmd)
+				return nil
+			},
+		},
+	)
+	return cmd
+}
+
+func telemetrySetCommand(use string, short string, enabled bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := metrics.SetConsent(metrics.Dir(), enabled); err != nil {
+				return err
+			}
+			printTelemetryStatus(cmd)
+			return nil
+		},
+	}
+}
+
+func printTelemetryStatus(cmd *cobra.Command) {
+	consent := metrics.GetConsent(metrics.Dir())
+	status := "disabled"
+	if consent.Enabled {
+		status = "enabled"
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Telemetry is %s (%s)\n", status, consent.Reason)
+}
@@ -0,0 +1,48 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/config"
+	"github.com/docker/compose-cli/cli/metrics"
+)
+
+func TestTelemetryWithConfigFlag(t *testing.T) {
+	defaultDir, configFlagDir := t.TempDir(), t.TempDir()
+	t.Setenv("DOCKER_CONFIG", defaultDir)
+	t.Setenv(metrics.EnvVarDoNotTrack, "")
+	// as set by docker --config
+	previous := config.Dir()
+	config.WithDir(configFlagDir)
+	defer config.WithDir(previous)
+
+	cmd := TelemetryCommand()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"off"})
+	assert.NilError(t, cmd.Execute())
+	assert.Equal(t, out.String(), "Telemetry is disabled (disabled in the configuration file)\n")
+
+	// the consent is read from where the reporter checks it
+	assert.Assert(t, !metrics.GetConsent(defaultDir).Enabled)
+	assert.Equal(t, metrics.GetConsent(configFlagDir).Reason, "default")
+}
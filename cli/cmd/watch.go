@@ -0,0 +1,56 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/watch"
+)
+
+// WatchCommand updates the services of a running project as their files change
+func WatchCommand(backend api.Service) *cobra.Command {
+	opts := watch.Options{}
+	cmd := &cobra.Command{
+		Use:   "watch [SERVICE...]",
+		Short: "Sync or rebuild services as their files change",
+		Long: `Watch the files of running services, syncing changes into their containers or rebuilding them.
+
+Paths are configured per service with the ` + watch.DevelopExtension + ` extension, services with a build
+section and no configuration are rebuilt on changes to their build context.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			project, err := composeProject(cmd, args)
+			if err != nil {
+				return err
+			}
+			opts.Services = args
+			opts.Out = cmd.OutOrStdout()
+			w, err := watch.NewWatcher(backend, project, opts)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Watching %s, press Ctrl+C to stop\n", strings.Join(w.Services(), ", "))
+			return w.Watch(cmd.Context())
+		},
+	}
+	cmd.Flags().DurationVar(&opts.Interval, "poll-interval", watch.DefaultInterval, "Interval between checks for file changes")
+	return cmd
+}
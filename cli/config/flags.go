@@ -19,7 +19,6 @@ package config
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
@@ -39,12 +38,7 @@ func (c *ConfigFlags) AddConfigFlags(flags *pflag.FlagSet) {
 }
 
 func confDir() string {
-	env := os.Getenv("DOCKER_CONFIG")
-	if env != "" {
-		return env
-	}
-	home, _ := os.UserHomeDir()
-	return filepath.Join(home, config.ConfigFileDir)
+	return config.DefaultDir()
 }
 
 // GetCurrentContext get current context based on opts, env vars
@@ -41,6 +41,7 @@ import (
 	"github.com/docker/compose-cli/api/config"
 	apicontext "github.com/docker/compose-cli/api/context"
 	"github.com/docker/compose-cli/api/context/store"
+	"github.com/docker/compose-cli/api/phase"
 	"github.com/docker/compose-cli/cli/cmd"
 	contextcmd "github.com/docker/compose-cli/cli/cmd/context"
 	"github.com/docker/compose-cli/cli/cmd/login"
@@ -51,6 +52,7 @@ import (
 	"github.com/docker/compose-cli/cli/metrics"
 	"github.com/docker/compose-cli/cli/mobycli"
 	cliopts "github.com/docker/compose-cli/cli/options"
+	"github.com/docker/compose-cli/internal"
 	"github.com/docker/compose-cli/local"
 
 	// Backend registrations
@@ -62,6 +64,7 @@ import (
 
 var (
 	metricsClient           metrics.Client
+	phaseRecorder           = phase.NewRecorder()
 	contextAgnosticCommands = map[string]struct{}{
 		"context":          {},
 		"login":            {},
@@ -73,6 +76,11 @@ var (
 		"__complete":       {},
 		"__completeNoDesc": {},
 	}
+	// compose subcommands handled by this CLI whatever the context type
+	contextAgnosticComposeCommands = map[string]struct{}{
+		"telemetry": {},
+		"metrics":   {},
+	}
 	unknownCommandRegexp = regexp.MustCompile(`unknown docker command: "([^"]*)"`)
 )
 
@@ -111,6 +119,9 @@ func isContextAgnosticCommand(cmd *cobra.Command) bool {
 	if _, ok := contextAgnosticCommands[cmd.Name()]; ok && isFirstLevelCommand(cmd) {
 		return true
 	}
+	if _, ok := contextAgnosticComposeCommands[cmd.Name()]; ok && cmd.HasParent() && cmd.Parent().Name() == "compose" && isFirstLevelCommand(cmd.Parent()) {
+		return true
+	}
 	return isContextAgnosticCommand(cmd.Parent())
 }
 
@@ -200,6 +211,9 @@ func main() {
 	ctx, cancel := newSigContext()
 	defer cancel()
 
+	metrics.ExportSessionID()
+	ctx = phase.WithRecorder(ctx, phaseRecorder)
+
 	// --version should immediately be forwarded to the original cli
 	if opts.Version {
 		mobycli.Exec(root)
@@ -250,23 +264,48 @@ func main() {
 	if ctype == store.AciContextType {
 		customizeCliForACI(command, proxy)
 	}
+	cmd.AddBuildx(command, proxy, ctype)
+	cmd.AddCreateOptions(command, proxy)
+	cmd.AddBuildArgs(command, proxy)
+	cmd.AddDockerfileInline(proxy)
+	cmd.AddConfigModel(command, proxy)
+	cmd.AddConvertOutput(command, proxy)
+	cmd.AddPhaseRecording(proxy)
+	cmd.AddUpWaitTimeout(command, proxy)
+	cmd.AddNoAttach(command, proxy)
+	cmd.AddLogColors(command, proxy)
+	cmd.AddHealthcheckOverrides(command, proxy)
+	cmd.AddProjectLabels(command, proxy)
+	cmd.AddKillServices(command, proxy)
+	cmd.AddPsFormat(command, proxy)
+	cmd.AddPsFilters(command, proxy, ctype)
+	cmd.AddComposeVersion(command, ctype)
+	cmd.AddLsConfigFiles(command, proxy, service.ComposeService())
+	cleanupComposeFiles := cmd.AddComposeFileResolution(command, proxy)
+	cmd.AddDryRun(command, proxy, ctype)
+	command.AddCommand(cmd.TelemetryCommand(), cmd.MetricsCommand(), cmd.WatchCommand(proxy), cmd.ScaleCommand(proxy))
 
 	root.AddCommand(command)
 
 	start := time.Now().UTC()
 	err = root.ExecuteContext(ctx)
 	duration := time.Since(start)
+	// before handleError exits
+	cleanupComposeFiles()
 	if err != nil {
 		handleError(ctx, err, ctype, currentContext, cc, root, start, duration)
 	}
 	metricsClient.Track(
 		metrics.CmdResult{
-			ContextType: ctype,
-			Args:        os.Args[1:],
-			Status:      metrics.SuccessStatus,
-			Start:       start,
-			Duration:    duration,
+			ContextType:    ctype,
+			BackendVersion: internal.Version,
+			Args:           os.Args[1:],
+			Status:         metrics.SuccessStatus,
+			Start:          start,
+			Duration:       duration,
+			Phases:         phaseRecorder.Phases(),
 		})
+	metricsClient.Close()
 }
 
 func customizeCliForACI(command *cobra.Command, proxy *api.ServiceProxy) {
@@ -299,13 +338,18 @@ func handleError(
 	if api.IsErrCanceled(err) || errors.Is(ctx.Err(), context.Canceled) {
 		metricsClient.Track(
 			metrics.CmdResult{
-				ContextType: ctype,
-				Args:        os.Args[1:],
-				Status:      metrics.CanceledStatus,
-				Start:       start,
-				Duration:    duration,
+				ContextType:    ctype,
+				BackendVersion: internal.Version,
+				Args:           os.Args[1:],
+				Status:         metrics.CanceledStatus,
+				ExitCode:       130,
+				Start:          start,
+				Duration:       duration,
+				ErrorCategory:  metrics.CanceledCategory,
+				Phases:         phaseRecorder.Phases(),
 			},
 		)
+		metricsClient.Close()
 		os.Exit(130)
 	}
 	if ctype == store.AwsContextType {
@@ -336,13 +380,18 @@ func exit(ctx string, err error, ctype string, start time.Time, duration time.Du
 		// TODO(milas): shouldn't this use the exit code to determine status?
 		metricsClient.Track(
 			metrics.CmdResult{
-				ContextType: ctype,
-				Args:        os.Args[1:],
-				Status:      metrics.SuccessStatus,
-				Start:       start,
-				Duration:    duration,
+				ContextType:    ctype,
+				BackendVersion: internal.Version,
+				Args:           os.Args[1:],
+				Status:         metrics.SuccessStatus,
+				ExitCode:       exit.StatusCode,
+				Start:          start,
+				Duration:       duration,
+				ErrorCategory:  metrics.ErrorCategoryFromExitCode(exit.StatusCode),
+				Phases:         phaseRecorder.Phases(),
 			},
 		)
+		metricsClient.Close()
 		os.Exit(exit.StatusCode)
 	}
 
@@ -357,19 +406,27 @@ func exit(ctx string, err error, ctype string, start time.Time, duration time.Du
 		metricsStatus = metrics.CommandSyntaxFailure.MetricsStatus
 		exitCode = metrics.CommandSyntaxFailure.ExitCode
 	}
+	if errors.Is(err, api.ErrLoginRequired) {
+		exitCode = api.ExitCodeLoginRequired
+	}
 	metricsClient.Track(
 		metrics.CmdResult{
-			ContextType: ctype,
-			Args:        os.Args[1:],
-			Status:      metricsStatus,
-			Start:       start,
-			Duration:    duration,
+			ContextType:    ctype,
+			BackendVersion: internal.Version,
+			Args:           os.Args[1:],
+			Status:         metricsStatus,
+			ExitCode:       exitCode,
+			Start:          start,
+			Duration:       duration,
+			ErrorCategory:  metrics.ClassifyError(err),
+			Phases:         phaseRecorder.Phases(),
 		},
 	)
+	metricsClient.Close()
 
 	if errors.Is(err, api.ErrLoginRequired) {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(api.ExitCodeLoginRequired)
+		os.Exit(exitCode)
 	}
 
 	if compose2.Warning != "" {
@@ -401,10 +458,14 @@ func checkIfUnknownCommandExistInDefaultContext(err error, currentContext string
 		if mobycli.IsDefaultContextCommand(dockerCommand) {
 			fmt.Fprintf(os.Stderr, "Command %q not available in current context (%s), you can use the \"default\" context to run this command\n", dockerCommand, currentContext)
 			metricsClient.Track(metrics.CmdResult{
-				ContextType: contextType,
-				Args:        os.Args[1:],
-				Status:      metrics.FailureStatus,
+				ContextType:    contextType,
+				BackendVersion: internal.Version,
+				Args:           os.Args[1:],
+				Status:         metrics.FailureStatus,
+				ExitCode:       1,
+				ErrorCategory:  metrics.UserErrorCategory,
 			})
+			metricsClient.Close()
 			os.Exit(1)
 		}
 	}
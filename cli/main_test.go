@@ -20,6 +20,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/spf13/cobra"
 	"gotest.tools/v3/assert"
 
 	"github.com/docker/compose-cli/cli/cmd"
@@ -38,6 +39,20 @@ func TestCheckOwnCommand(t *testing.T) {
 	assert.Assert(t, !isContextAgnosticCommand(cmd.PsCommand()))
 }
 
+func TestCheckComposeTelemetryCommand(t *testing.T) {
+	root := &cobra.Command{Use: "docker"}
+	compose := &cobra.Command{Use: "compose"}
+	telemetry := cmd.TelemetryCommand()
+	up := &cobra.Command{Use: "up"}
+	compose.AddCommand(telemetry, up)
+	root.AddCommand(compose)
+
+	assert.Assert(t, isContextAgnosticCommand(telemetry))
+	assert.Assert(t, isContextAgnosticCommand(telemetry.Commands()[0]))
+	assert.Assert(t, !isContextAgnosticCommand(up))
+	assert.Assert(t, !isContextAgnosticCommand(compose))
+}
+
 func TestAppendPaths(t *testing.T) {
 	assert.Equal(t, appendPaths("", "/bin/path"), "/bin/path")
 	assert.Equal(t, appendPaths("path1", "binaryPath"), "path1"+string(os.PathListSeparator)+"binaryPath")
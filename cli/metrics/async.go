@@ -0,0 +1,202 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultAsyncQueueSize is the number of pending metric events kept in memory
+// by the default asynchronous reporter.
+const DefaultAsyncQueueSize = 32
+
+// AsyncReporter reports metric events to the wrapped reporter from a
+// background goroutine, so callers never wait on slow or unreachable
+// endpoints.
+//
+// Pending events are kept in a bounded queue; when the queue is full the
+// oldest event is dropped. Flush must be called before the process exits to
+// deliver pending events, Close stops the background goroutine.
+type AsyncReporter struct {
+	reporter Reporter
+	capacity int
+
+	mu      sync.Mutex
+	queue   []func(Reporter)
+	busy    bool
+	dropped int
+	waiters []chan struct{}
+	closed  bool
+	wake    chan struct{}
+	stop    chan struct{}
+}
+
+// NewAsyncReporter creates a reporter that queues up to size metric events
+// and reports them to the provided reporter in the background.
+func NewAsyncReporter(reporter Reporter, size int) *AsyncReporter {
+	if size < 1 {
+		size = 1
+	}
+	a := &AsyncReporter{
+		reporter: reporter,
+		capacity: size,
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// Heartbeat reports a metric for aggregation.
+func (a *AsyncReporter) Heartbeat(ctx context.Context, cmd CommandUsage) {
+	ctx = detach(ctx)
+	a.enqueue(func(r Reporter) {
+		r.Heartbeat(ctx, cmd)
+	})
+}
+
+// Event reports an analytics action.
+func (a *AsyncReporter) Event(ctx context.Context, cmd DockerCLIEvent) {
+	ctx = detach(ctx)
+	a.enqueue(func(r Reporter) {
+		r.Event(ctx, cmd)
+	})
+}
+
+// Flush blocks until all pending events have been reported, or the context
+// is done.
+func (a *AsyncReporter) Flush(ctx context.Context) error {
+	a.mu.Lock()
+	if len(a.queue) == 0 && !a.busy {
+		a.mu.Unlock()
+		return flush(ctx, a.reporter)
+	}
+	done := make(chan struct{})
+	a.waiters = append(a.waiters, done)
+	a.mu.Unlock()
+
+	select {
+	case <-done:
+		return flush(ctx, a.reporter)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close discards pending events, stops the background goroutine and closes
+// the wrapped reporter. Events reported afterwards are dropped.
+func (a *AsyncReporter) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	a.dropped += len(a.queue)
+	a.queue = nil
+	for _, w := range a.waiters {
+		close(w)
+	}
+	a.waiters = nil
+	a.mu.Unlock()
+	close(a.stop)
+	return closeReporter(a.reporter)
+}
+
+// Dropped returns the number of events discarded because the queue was full.
+func (a *AsyncReporter) Dropped() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.dropped
+}
+
+func (a *AsyncReporter) enqueue(f func(Reporter)) {
+	a.mu.Lock()
+	if a.closed {
+		a.dropped++
+		a.mu.Unlock()
+		return
+	}
+	if len(a.queue) >= a.capacity {
+		a.queue = a.queue[1:]
+		a.dropped++
+	}
+	a.queue = append(a.queue, f)
+	a.mu.Unlock()
+
+	select {
+	case a.wake <- struct{}{}:
+	default: // worker has already been notified
+	}
+}
+
+func (a *AsyncReporter) run() {
+	for {
+		select {
+		case <-a.wake:
+		case <-a.stop:
+			return
+		}
+		for {
+			a.mu.Lock()
+			if len(a.queue) == 0 {
+				a.busy = false
+				for _, w := range a.waiters {
+					close(w)
+				}
+				a.waiters = nil
+				a.mu.Unlock()
+				break
+			}
+			next := a.queue[0]
+			a.queue = a.queue[1:]
+			a.busy = true
+			a.mu.Unlock()
+
+			next(a.reporter)
+		}
+	}
+}
+
+// detach returns a context with the values of ctx, such as the trace context,
+// but without its deadline and cancellation: queued events are reported
+// after the call returns, how long to wait for them is decided by Flush.
+func detach(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
+
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (d detachedContext) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
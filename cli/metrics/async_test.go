@@ -0,0 +1,140 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+type blockingReporter struct {
+	mu       sync.Mutex
+	release  chan struct{}
+	commands []string
+}
+
+func (b *blockingReporter) Heartbeat(ctx context.Context, cmd CommandUsage) {
+	<-b.release
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.commands = append(b.commands, cmd.Command)
+}
+
+func (b *blockingReporter) Event(context.Context, DockerCLIEvent) {}
+
+func TestAsyncReporterDoesNotBlock(t *testing.T) {
+	r := &blockingReporter{release: make(chan struct{})}
+	async := NewAsyncReporter(r, 2)
+
+	start := time.Now()
+	async.Heartbeat(context.Background(), CommandUsage{Command: "ps"})
+	waitForWorker(async)
+	for _, c := range []string{"up", "down", "logs"} {
+		async.Heartbeat(context.Background(), CommandUsage{Command: c})
+	}
+	assert.Assert(t, time.Since(start) < Timeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, async.Flush(ctx), context.DeadlineExceeded)
+
+	close(r.release)
+	assert.NilError(t, async.Flush(context.Background()))
+
+	// "ps" was picked up by the worker, then "up" was dropped as the queue
+	// only holds 2 events
+	assert.DeepEqual(t, r.commands, []string{"ps", "down", "logs"})
+	assert.Equal(t, async.Dropped(), 1)
+}
+
+// waitForWorker waits for the worker to pick up the queued events.
+func waitForWorker(a *AsyncReporter) {
+	for {
+		a.mu.Lock()
+		picked := a.busy && len(a.queue) == 0
+		a.mu.Unlock()
+		if picked {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMuxReporterFlush(t *testing.T) {
+	r := &blockingReporter{release: make(chan struct{})}
+	close(r.release)
+	mux := NewMuxReporter(&countingReporter{}, NewAsyncReporter(r, 8))
+
+	mux.Heartbeat(context.Background(), CommandUsage{Command: "up"})
+	assert.NilError(t, mux.Flush(context.Background()))
+	assert.DeepEqual(t, r.commands, []string{"up"})
+}
+
+type panickingReporter struct{}
+
+func (panickingReporter) Heartbeat(context.Context, CommandUsage) {
+	panic("boom")
+}
+
+func (panickingReporter) Event(context.Context, DockerCLIEvent) {}
+
+func TestMuxReporterIsolation(t *testing.T) {
+	hung := &blockingReporter{release: make(chan struct{})}
+	defer close(hung.release)
+	counting := &countingReporter{}
+
+	for _, parallel := range []bool{false, true} {
+		opts := []MuxReporterOption{WithReporterTimeout(10 * time.Millisecond)}
+		if parallel {
+			opts = append(opts, WithParallel())
+		}
+		mux := NewMuxReporter(hung, panickingReporter{}, counting).With(opts...)
+		mux.Heartbeat(context.Background(), CommandUsage{Command: "up"})
+		assert.DeepEqual(t, mux.Failures(), []uint64{1, 1, 0})
+	}
+	assert.Equal(t, counting.heartbeats, 2)
+}
+
+type closingReporter struct {
+	*RecordingReporter
+	closed bool
+}
+
+func (c *closingReporter) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestClientClose(t *testing.T) {
+	inner := &closingReporter{RecordingReporter: NewRecordingReporter()}
+	async := NewAsyncReporter(inner, 8)
+	c := NewClient(NewRedactingReporter(NewMuxReporter(async)))
+
+	async.Heartbeat(context.Background(), CommandUsage{Command: "ps"})
+	c.Close()
+	assert.DeepEqual(t, inner.Commands(), []string{"ps"})
+	assert.Assert(t, inner.closed)
+
+	async.Heartbeat(context.Background(), CommandUsage{Command: "up"})
+	assert.NilError(t, async.Flush(context.Background()))
+	assert.Equal(t, async.Dropped(), 1)
+	assert.DeepEqual(t, inner.Commands(), []string{"ps"})
+}
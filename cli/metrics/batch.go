@@ -0,0 +1,153 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+)
+
+const (
+	// EnvVarMetricsBatch is an optional environment variable enabling, when
+	// set to a true value, the batching of heartbeats posted to a configured
+	// metrics endpoint, see WithBatching.
+	EnvVarMetricsBatch = "DOCKER_METRICS_BATCH"
+
+	// DefaultBatchMaxEntries is the number of heartbeats after which a batch
+	// is posted without waiting to be flushed.
+	DefaultBatchMaxEntries = 50
+)
+
+// WithBatching keeps heartbeats in memory until the reporter is flushed, or
+// maxEntries are pending, and posts them in a single gzip-compressed request
+// with a newline-delimited JSON body. Events are posted right away.
+func WithBatching(maxEntries int) HTTPReporterOption {
+	return func(l *HTTPReporter) {
+		if maxEntries < 1 {
+			maxEntries = 1
+		}
+		l.batch = &batch{maxEntries: maxEntries}
+	}
+}
+
+// batch is the list of serialized heartbeats waiting to be posted.
+type batch struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries [][]byte
+}
+
+// add appends an entry, returning the pending entries once they are full.
+func (b *batch) add(entry []byte) [][]byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries = append(b.entries, entry)
+	if len(b.entries) < b.maxEntries {
+		return nil
+	}
+	entries := b.entries
+	b.entries = nil
+	return entries
+}
+
+func (b *batch) take() [][]byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	entries := b.entries
+	b.entries = nil
+	return entries
+}
+
+// Flush posts the pending batch of heartbeats, if any.
+func (l HTTPReporter) Flush(ctx context.Context) error {
+	if l.batch == nil {
+		return nil
+	}
+	if entries := l.batch.take(); len(entries) > 0 {
+		l.postBatch(ctx, entries)
+	}
+	return nil
+}
+
+func (l HTTPReporter) addToBatch(ctx context.Context, body interface{}) {
+	entry, err := json.Marshal(body)
+	if err != nil {
+		// we only pass known types that will marshal without error (no cycles)
+		return
+	}
+	if entries := l.batch.add(entry); entries != nil {
+		l.postBatch(ctx, entries)
+	}
+}
+
+func (l HTTPReporter) postBatch(ctx context.Context, entries [][]byte) {
+	u, err := url.JoinPath(l.endpoint, "/usage")
+	if err != nil {
+		// we only pass known values here, so this isn't possible in practice
+		return
+	}
+	body, err := gzipNDJSON(entries)
+	if err != nil {
+		return
+	}
+	headers := map[string]string{
+		"Content-Type":     "application/x-ndjson",
+		"Content-Encoding": "gzip",
+	}
+	if !l.send(ctx, u, body, headers) {
+		if l.spool != nil {
+			for _, entry := range entries {
+				_ = l.spool.push(entry)
+			}
+		}
+		return
+	}
+	if l.spool != nil {
+		_ = l.spool.replay(func(entry []byte) bool {
+			return l.send(ctx, u, entry, l.headers())
+		})
+	}
+}
+
+func gzipNDJSON(entries [][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	for _, entry := range entries {
+		if _, err := w.Write(entry); err != nil {
+			return nil, err
+		}
+		if _, err := w.Write([]byte{'\n'}); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func batchingEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(EnvVarMetricsBatch))
+	return enabled
+}
@@ -0,0 +1,81 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestHTTPReporterBatching(t *testing.T) {
+	bodies := make(chan string, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Header.Get("Content-Type"), "application/x-ndjson")
+		assert.Equal(t, r.Header.Get("Content-Encoding"), "gzip")
+		gz, err := gzip.NewReader(r.Body)
+		assert.NilError(t, err)
+		b, err := io.ReadAll(gz)
+		assert.NilError(t, err)
+		bodies <- string(b)
+	}))
+	defer server.Close()
+
+	reporter := NewHTTPReporter(newTestHTTPClient(server), WithSchemaVersion(SchemaV1), WithBatching(3))
+	reporter.Heartbeat(context.Background(), CommandUsage{Command: "compose up"})
+	reporter.Heartbeat(context.Background(), CommandUsage{Command: "compose ps"})
+	assert.Equal(t, len(bodies), 0)
+
+	assert.NilError(t, reporter.Flush(context.Background()))
+	assert.Equal(t, <-bodies, `{"command":"compose up","context":"","source":"","status":""}`+"\n"+
+		`{"command":"compose ps","context":"","source":"","status":""}`+"\n")
+
+	for i := 0; i < 3; i++ {
+		reporter.Heartbeat(context.Background(), CommandUsage{Command: "compose ls"})
+	}
+	assert.Equal(t, strings.Count(<-bodies, "compose ls"), 3)
+	assert.NilError(t, reporter.Flush(context.Background()))
+	assert.Equal(t, len(bodies), 0)
+}
+
+func TestHTTPReporterBatchingSpool(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "spool.jsonl")
+	reporter := NewHTTPReporter(newTestHTTPClient(server),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 1}),
+		WithSpool(path, 10),
+		WithBatching(10),
+	)
+	reporter.Heartbeat(context.Background(), CommandUsage{Command: "compose up"})
+	reporter.Heartbeat(context.Background(), CommandUsage{Command: "compose ps"})
+	assert.NilError(t, reporter.Flush(context.Background()))
+
+	entries, err := readSpoolFile(path)
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 2)
+}
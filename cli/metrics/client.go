@@ -17,8 +17,13 @@
 package metrics
 
 import (
+	"context"
 	"os"
+	"path/filepath"
 	"time"
+
+	"github.com/docker/compose-cli/api/phase"
+	"github.com/sirupsen/logrus"
 )
 
 // EnvVarDebugMetricsPath is an optional environment variable used to debug
@@ -30,10 +35,18 @@ const EnvVarDebugMetricsPath = "DOCKER_METRICS_DEBUG_LOG"
 // acknowledged before giving up.
 const Timeout = 50 * time.Millisecond
 
+// FlushTimeout is the maximum amount of time Close waits for buffered metric
+// events to be delivered before the process exits.
+const FlushTimeout = 200 * time.Millisecond
+
 // CmdResult provides details about process execution.
 type CmdResult struct {
 	// ContextType is `moby` for Docker or the name of a cloud provider.
 	ContextType string
+	// BackendVersion is the version of the program implementing the command
+	// for the context type: the Docker CLI for commands delegated to it,
+	// this CLI otherwise.
+	BackendVersion string
 	// Args minus the process name (argv[0] aka `docker`).
 	Args []string
 	// Status based on exit code as a descriptive value.
@@ -46,6 +59,10 @@ type CmdResult struct {
 	Start time.Time
 	// Duration of process execution.
 	Duration time.Duration
+	// ErrorCategory classifies the failure, see ClassifyError.
+	ErrorCategory string
+	// Phases are the long-running operations of the command.
+	Phases []phase.Phase
 }
 
 type client struct {
@@ -59,10 +76,28 @@ type cliversion struct {
 
 // CommandUsage reports a CLI invocation for aggregation.
 type CommandUsage struct {
-	Command string `json:"command"`
-	Context string `json:"context"`
-	Source  string `json:"source"`
-	Status  string `json:"status"`
+	Command      string  `json:"command"`
+	Context      string  `json:"context"`
+	Source       string  `json:"source"`
+	Status       string  `json:"status"`
+	ExitCode     int     `json:"exit_code,omitempty"`
+	DurationSecs float64 `json:"duration_secs,omitempty"`
+	// BackendVersion is the version of the backend of Context, see
+	// CmdResult.
+	BackendVersion string `json:"backend_version,omitempty"`
+	// Canceled is true if the command was interrupted by a signal.
+	Canceled      bool   `json:"canceled,omitempty"`
+	ErrorCategory string `json:"error_category,omitempty"`
+	// Count is the number of invocations coalesced into this heartbeat when
+	// rate limited; zero means one.
+	Count int `json:"count,omitempty"`
+	// SessionID correlates the commands run from the same shell session.
+	SessionID string `json:"session_id,omitempty"`
+	// InvocationID identifies the CLI process that ran the command.
+	InvocationID string `json:"invocation_id,omitempty"`
+	// MachineID is a non-reversible identifier of the user and machine, see
+	// MachineID.
+	MachineID string `json:"machine_id,omitempty"`
 }
 
 // CLISource is sent for cli metrics
@@ -85,6 +120,11 @@ type Client interface {
 	SendUsage(CommandUsage)
 	// Track creates an event for a command execution and reports it.
 	Track(CmdResult)
+	// Close delivers the buffered metric events, waiting at most
+	// FlushTimeout, and releases the resources of the reporter. It must be
+	// called before the process exits, the client must not be used
+	// afterwards.
+	Close()
 }
 
 // NewClient returns a new metrics client that will send metrics using the
@@ -97,33 +137,92 @@ func NewClient(reporter Reporter) Client {
 }
 
 // NewDefaultClient returns a new metrics client that will send metrics using
-// the default Reporter configuration, which reports via HTTP, and, optionally,
-// to a local file for debugging. (No format guarantees are made!)
+// the default Reporter configuration, see NewDefaultReporter.
 func NewDefaultClient() Client {
-	httpClient := newHTTPClient()
+	return NewClient(NewDefaultReporter())
+}
 
-	var reporter Reporter = NewHTTPReporter(httpClient)
-	if metricsLogPath := os.Getenv(EnvVarDebugMetricsPath); metricsLogPath != "" {
-		if f, err := os.OpenFile(metricsLogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err != nil {
-			panic(err)
+// NewDefaultReporter returns the default Reporter configuration, which reports
+// via HTTP to Docker Desktop or the configured metrics Endpoint, optionally to
+// an OpenTelemetry collector when OTEL_EXPORTER_OTLP_* variables are set, a
+// gRPC collector when DOCKER_METRICS_GRPC_ADDR is set or a StatsD agent when
+// DOCKER_METRICS_STATSD_ADDR is set, and optionally to a local file for
+// debugging, rotated according to DOCKER_METRICS_LOG_MAX_SIZE. (No format
+// guarantees are made, beyond DOCKER_METRICS_FORMAT!)
+//
+// Heartbeats are sampled according to DOCKER_METRICS_SAMPLE_RATE, except for
+// the debug file which receives everything, and rate limited when
+// DOCKER_METRICS_RATE_LIMIT is set. Network reporters are called
+// asynchronously. The payloads of the last command that were sampled and not
+// rate limited are kept in the configuration directory once flushed, see
+// LastPayloads.
+//
+// Paths, hostnames, flag values and the project name are redacted from all
+// events, see RedactingReporter. Nothing is reported if the user opted out,
+// see GetConsent.
+func NewDefaultReporter() Reporter {
+	if !GetConsent(Dir()).Enabled {
+		return noopReporter{}
+	}
+	reporter := newTransportReporter()
+	if IsOTLPConfigured() {
+		otlpReporter, err := NewOTLPReporter()
+		if err != nil {
+			logrus.Warnf("usage metrics are not exported to the OpenTelemetry collector: %v", err)
 		} else {
+			reporter = NewMuxReporter(reporter, otlpReporter).With(WithParallel())
+		}
+	}
+	if address := os.Getenv(EnvVarMetricsGRPCAddress); address != "" {
+		if grpcReporter, err := NewGRPCReporter(address); err == nil {
+			reporter = NewMuxReporter(reporter, grpcReporter).With(WithParallel())
+		}
+	}
+	if address := os.Getenv(EnvVarStatsDAddress); address != "" {
+		if statsDReporter, err := NewStatsDReporter(address, os.Getenv(EnvVarStatsDFormat)); err == nil {
+			reporter = NewMuxReporter(reporter, statsDReporter).With(WithParallel())
+		}
+	}
+	reporter = NewMuxReporter(newPayloadRecorder(Dir()), reporter)
+	if rate := SampleRate(); rate < 1 {
+		reporter = NewSamplingReporter(reporter, rate, SessionID())
+	}
+	if rate := RateLimit(); rate > 0 {
+		reporter = NewRateLimitingReporter(reporter, rate, DefaultRateLimitBurst, filepath.Join(Dir(), rateLimitFileName))
+	}
+	reporter = NewAsyncReporter(reporter, DefaultAsyncQueueSize)
+	if metricsLogPath := os.Getenv(EnvVarDebugMetricsPath); metricsLogPath != "" {
+		if f := newMetricsLogReporter(metricsLogPath); f != nil {
+			if format, err := payloadFormat(); err == nil {
+				f.WriterReporter = f.WriterReporter.WithPayloadFormat(format)
+			}
 			reporter = NewMuxReporter(
-				NewWriterReporter(f),
+				f,
 				reporter,
 			)
 		}
 	}
-	return NewClient(reporter)
+	return NewRedactingReporter(reporter, sensitiveValues()...)
 }
 
 func (c *client) WithCliVersionFunc(f func() string) {
 	c.cliversion.f = f
 }
 
+func (c *client) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), FlushTimeout)
+	defer cancel()
+	_ = flush(ctx, c.reporter)
+	_ = closeReporter(c.reporter)
+}
+
 func (c *client) SendUsage(command CommandUsage) {
+	ctx, cancel := context.WithTimeout(withEnvTraceContext(context.Background()), Timeout)
+	defer cancel()
+
 	result := make(chan bool, 1)
 	go func() {
-		c.reporter.Heartbeat(command)
+		c.reporter.Heartbeat(ctx, command)
 		result <- true
 	}()
 
@@ -131,6 +230,7 @@ func (c *client) SendUsage(command CommandUsage) {
 	// Posting metrics without Desktop listening returns in less than a ms, and a handful of ms (often <2ms) when Desktop is listening
 	select {
 	case <-result:
-	case <-time.After(Timeout):
+		_ = flush(ctx, c.reporter)
+	case <-ctx.Done():
 	}
 }
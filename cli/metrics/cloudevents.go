@@ -0,0 +1,113 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+)
+
+// EnvVarMetricsFormat is an optional environment variable selecting the
+// payload format posted to a configured metrics endpoint and written to the
+// debug file: `json` (the default) or `cloudevents`.
+const EnvVarMetricsFormat = "DOCKER_METRICS_FORMAT"
+
+// PayloadFormat is how reporters encode metric events.
+type PayloadFormat string
+
+const (
+	// PayloadFormatJSON encodes metric events as plain JSON objects.
+	PayloadFormatJSON PayloadFormat = "json"
+	// PayloadFormatCloudEvents wraps metric events in CloudEvents 1.0
+	// envelopes, in structured JSON mode.
+	PayloadFormatCloudEvents PayloadFormat = "cloudevents"
+)
+
+const (
+	// CloudEventHeartbeatType is the CloudEvents type of heartbeats.
+	CloudEventHeartbeatType = "com.docker.cli.command.usage"
+	// CloudEventEventType is the CloudEvents type of analytics events.
+	CloudEventEventType = "com.docker.cli.command.event"
+
+	cloudEventsSpecVersion = "1.0"
+	cloudEventsContentType = "application/cloudevents+json"
+	cloudEventsSource      = "docker-cli"
+)
+
+// CloudEvent is a CloudEvents 1.0 envelope, in structured JSON mode.
+type CloudEvent struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	Time            time.Time   `json:"time"`
+	DataContentType string      `json:"datacontenttype"`
+	Data            interface{} `json:"data"`
+}
+
+func newCloudEvent(eventType string, source string, data interface{}) CloudEvent {
+	id, _ := uuid.GenerateUUID()
+	if source == "" {
+		source = CLISource
+	}
+	return CloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		ID:              id,
+		Source:          cloudEventsSource + "/" + source,
+		Type:            eventType,
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data:            data,
+	}
+}
+
+// wrapHeartbeat returns the heartbeat payload in the given format.
+func wrapHeartbeat(format PayloadFormat, cmd CommandUsage, payload interface{}) interface{} {
+	if format != PayloadFormatCloudEvents {
+		return payload
+	}
+	return newCloudEvent(CloudEventHeartbeatType, cmd.Source, payload)
+}
+
+// wrapEvent returns the analytics event payload in the given format.
+func wrapEvent(format PayloadFormat, payload interface{}) interface{} {
+	if format != PayloadFormatCloudEvents {
+		return payload
+	}
+	return newCloudEvent(CloudEventEventType, "", payload)
+}
+
+func contentType(format PayloadFormat) string {
+	if format == PayloadFormatCloudEvents {
+		return cloudEventsContentType
+	}
+	return "application/json"
+}
+
+func payloadFormat() (PayloadFormat, error) {
+	switch f := PayloadFormat(os.Getenv(EnvVarMetricsFormat)); f {
+	case "":
+		return PayloadFormatJSON, nil
+	case PayloadFormatJSON, PayloadFormatCloudEvents:
+		return f, nil
+	default:
+		return PayloadFormatJSON, fmt.Errorf("invalid %s value %q", EnvVarMetricsFormat, f)
+	}
+}
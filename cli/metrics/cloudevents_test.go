@@ -0,0 +1,90 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestHTTPReporterCloudEvents(t *testing.T) {
+	type request struct {
+		contentType string
+		body        []byte
+	}
+	requests := make(chan request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		requests <- request{contentType: r.Header.Get("Content-Type"), body: b}
+	}))
+	defer server.Close()
+
+	reporter := NewHTTPReporter(newTestHTTPClient(server), WithPayloadFormat(PayloadFormatCloudEvents))
+	reporter.Heartbeat(context.Background(), testUsage)
+	req := <-requests
+	assert.Equal(t, req.contentType, "application/cloudevents+json")
+
+	var event struct {
+		CloudEvent
+		Data json.RawMessage `json:"data"`
+	}
+	assert.NilError(t, json.Unmarshal(req.body, &event))
+	assert.Equal(t, event.SpecVersion, "1.0")
+	assert.Equal(t, event.Type, CloudEventHeartbeatType)
+	assert.Equal(t, event.Source, "docker-cli/cli")
+	assert.Equal(t, event.DataContentType, "application/json")
+	assert.Assert(t, event.ID != "")
+	usage, version, err := DecodeCommandUsage(event.Data)
+	assert.NilError(t, err)
+	assert.Equal(t, version, CurrentSchemaVersion)
+	assert.DeepEqual(t, usage, testUsage)
+}
+
+func TestWriterReporterCloudEvents(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := NewWriterReporter(&buf).WithPayloadFormat(PayloadFormatCloudEvents)
+	reporter.Event(context.Background(), DockerCLIEvent{Command: "compose", Subcommand: "up"})
+
+	var event CloudEvent
+	assert.NilError(t, json.Unmarshal(buf.Bytes(), &event))
+	assert.Equal(t, event.Type, CloudEventEventType)
+	assert.DeepEqual(t, event.Data, map[string]interface{}{
+		"command":    "compose",
+		"subcommand": "up",
+		"exit_code":  float64(0),
+		"start_time": "0001-01-01T00:00:00Z",
+	})
+}
+
+func TestPayloadFormatFromEnv(t *testing.T) {
+	t.Setenv(EnvVarMetricsFormat, "cloudevents")
+	format, err := payloadFormat()
+	assert.NilError(t, err)
+	assert.Equal(t, format, PayloadFormatCloudEvents)
+
+	t.Setenv(EnvVarMetricsFormat, "xml")
+	format, err = payloadFormat()
+	assert.ErrorContains(t, err, "invalid DOCKER_METRICS_FORMAT value")
+	assert.Equal(t, format, PayloadFormatJSON)
+}
@@ -0,0 +1,87 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/docker/compose-cli/api/config"
+)
+
+// EnvVarDoNotTrack is the conventional environment variable used to opt out
+// of telemetry, see https://consoledonottrack.com.
+const EnvVarDoNotTrack = "DO_NOT_TRACK"
+
+// Dir returns the directory holding the metrics consent and state, the
+// default configuration directory, as reporters are created before the
+// --config flag is parsed.
+func Dir() string {
+	return config.DefaultDir()
+}
+
+// Consent describes whether metrics reporting is enabled, and why.
+type Consent struct {
+	Enabled bool
+	Reason  string
+}
+
+// GetConsent returns the metrics reporting consent, based on DO_NOT_TRACK and
+// the Docker configuration file in the given directory. Reporting is enabled
+// unless the user opted out.
+func GetConsent(dir string) Consent {
+	if v, ok := os.LookupEnv(EnvVarDoNotTrack); ok && isTruthy(v) {
+		return Consent{Enabled: false, Reason: EnvVarDoNotTrack + " is set"}
+	}
+	f, err := config.LoadFile(dir)
+	if err != nil {
+		// can't read user preference, don't report anything
+		return Consent{Enabled: false, Reason: "the configuration file can't be read"}
+	}
+	if f.Metrics != nil && f.Metrics.Enabled != nil {
+		if *f.Metrics.Enabled {
+			return Consent{Enabled: true, Reason: "enabled in the configuration file"}
+		}
+		return Consent{Enabled: false, Reason: "disabled in the configuration file"}
+	}
+	return Consent{Enabled: true, Reason: "default"}
+}
+
+// SetConsent records the metrics reporting consent in the Docker
+// configuration file in the given directory.
+func SetConsent(dir string, enabled bool) error {
+	return config.WriteMetricsEnabled(dir, enabled)
+}
+
+func isTruthy(v string) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "", "0", "false", "no":
+		return false
+	default:
+		return true
+	}
+}
+
+// noopReporter discards all metric events.
+type noopReporter struct{}
+
+// Heartbeat reports a metric for aggregation.
+func (noopReporter) Heartbeat(context.Context, CommandUsage) {}
+
+// Event reports an analytics action.
+func (noopReporter) Event(context.Context, DockerCLIEvent) {}
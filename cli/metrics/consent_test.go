@@ -0,0 +1,46 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestConsent(t *testing.T) {
+	dir := t.TempDir()
+	assert.DeepEqual(t, GetConsent(dir), Consent{Enabled: true, Reason: "default"})
+
+	assert.NilError(t, SetConsent(dir, false))
+	assert.DeepEqual(t, GetConsent(dir), Consent{Enabled: false, Reason: "disabled in the configuration file"})
+
+	assert.NilError(t, SetConsent(dir, true))
+	assert.DeepEqual(t, GetConsent(dir), Consent{Enabled: true, Reason: "enabled in the configuration file"})
+
+	t.Setenv(EnvVarDoNotTrack, "0")
+	assert.Assert(t, GetConsent(dir).Enabled)
+
+	t.Setenv(EnvVarDoNotTrack, "1")
+	assert.DeepEqual(t, GetConsent(dir), Consent{Enabled: false, Reason: "DO_NOT_TRACK is set"})
+}
+
+func TestDefaultReporterWithoutConsent(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	t.Setenv(EnvVarDoNotTrack, "1")
+	assert.Equal(t, NewDefaultReporter(), Reporter(noopReporter{}))
+}
@@ -0,0 +1,39 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"os"
+
+	"github.com/hashicorp/go-uuid"
+)
+
+var processInvocationID, _ = uuid.GenerateUUID()
+
+// InvocationID returns a UUID identifying the current CLI process.
+func InvocationID() string {
+	return processInvocationID
+}
+
+// ExportSessionID sets the session ID in the environment if it isn't set
+// already, so that the commands run by this process (CLI plugins, the Docker
+// CLI) report the same session.
+func ExportSessionID() {
+	if os.Getenv(EnvVarSessionID) == "" {
+		_ = os.Setenv(EnvVarSessionID, processSessionID)
+	}
+}
@@ -29,6 +29,13 @@ type DockerCLIEvent struct {
 	ExitCode     int32     `json:"exit_code"`
 	StartTime    time.Time `json:"start_time"`
 	DurationSecs float64   `json:"duration_secs,omitempty"`
+	// ContextType and BackendVersion identify the backend the command ran
+	// on, see CmdResult.
+	ContextType    string `json:"context_type,omitempty"`
+	BackendVersion string `json:"backend_version,omitempty"`
+	// Phase is the long-running operation of the command the event reports,
+	// see NewPhaseEvents, or empty for the command itself.
+	Phase string `json:"phase,omitempty"`
 }
 
 // NewDockerCLIEvent inspects the command line string and returns a stripped down
@@ -86,17 +93,48 @@ func NewDockerCLIEvent(cmd CmdResult) *DockerCLIEvent {
 	}
 
 	event := &DockerCLIEvent{
-		Command:      cmdOrPlugin.name,
-		Subcommand:   subcommand,
-		ExitCode:     int32(cmd.ExitCode),
-		Usage:        usage,
-		StartTime:    cmd.Start,
-		DurationSecs: cmd.Duration.Seconds(),
+		Command:        cmdOrPlugin.name,
+		Subcommand:     subcommand,
+		ExitCode:       int32(cmd.ExitCode),
+		Usage:          usage,
+		StartTime:      cmd.Start,
+		DurationSecs:   cmd.Duration.Seconds(),
+		ContextType:    cmd.ContextType,
+		BackendVersion: cmd.BackendVersion,
 	}
 
 	return event
 }
 
+// NewPhaseEvents returns an event for each long-running operation of the
+// command, see phase.Start, timed on its own and failed with exit code 1 if the
+// operation failed. Unlike NewDockerCLIEvent, all the commands known to
+// GetCommand are reported, as phases are recorded for compose up, pull and
+// build.
+func NewPhaseEvents(cmd CmdResult) []DockerCLIEvent {
+	command := strings.Fields(GetCommand(cmd.Args))
+	if len(command) == 0 {
+		return nil
+	}
+	var events []DockerCLIEvent
+	for _, p := range cmd.Phases {
+		e := DockerCLIEvent{
+			Command:        command[0],
+			Subcommand:     strings.Join(command[1:], "-"),
+			ContextType:    cmd.ContextType,
+			BackendVersion: cmd.BackendVersion,
+			StartTime:      p.Start,
+			DurationSecs:   p.Duration.Seconds(),
+			Phase:          p.Name,
+		}
+		if p.Err != nil {
+			e.ExitCode = 1
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
 func findCommand(args []string) []*cmdNode {
 	if len(args) == 0 {
 		return nil
@@ -159,6 +197,7 @@ var cmdHierarchy = &cmdNode{
 			name:   "compose",
 			plugin: true,
 			children: []*cmdNode{
+				{name: "watch"},
 				{
 					name: "alpha",
 					children: []*cmdNode{
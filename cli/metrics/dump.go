@@ -0,0 +1,148 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const lastPayloadsFileName = "metrics-last.jsonl"
+
+// Payload is a metric event as posted to the metrics endpoint, after
+// redaction and schema conversion.
+type Payload struct {
+	Path string      `json:"path"`
+	Body interface{} `json:"body"`
+}
+
+// LastPayloads returns the payloads reported by the last command, as JSON
+// lines, from the configuration directory.
+func LastPayloads(dir string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(dir, lastPayloadsFileName))
+}
+
+// PreviewPayloads returns the payloads that would be reported for a command
+// execution.
+func PreviewPayloads(cmd CmdResult) []Payload {
+	recorder := &payloadRecorder{schema: CurrentSchemaVersion}
+	if version, err := schemaVersion(); err == nil {
+		recorder.schema = version
+	}
+	reporter := NewRedactingReporter(recorder, sensitiveValues()...)
+	ctx := context.Background()
+	if usage := NewCommandUsage(cmd); usage != nil {
+		usage.Source = CLISource
+		reporter.Heartbeat(ctx, *usage)
+	}
+	if event := NewDockerCLIEvent(cmd); event != nil {
+		reporter.Event(ctx, *event)
+	}
+	return recorder.payloads
+}
+
+// payloadRecorder keeps the payloads reported by the current command, and
+// optionally writes them to a file on Flush, replacing those of the previous
+// command. The file is replaced atomically so that concurrent commands don't
+// truncate each other's payloads, the last one flushed wins.
+type payloadRecorder struct {
+	path   string
+	schema int
+
+	mu       sync.Mutex
+	payloads []Payload
+}
+
+func newPayloadRecorder(dir string) *payloadRecorder {
+	r := &payloadRecorder{
+		path:   filepath.Join(dir, lastPayloadsFileName),
+		schema: CurrentSchemaVersion,
+	}
+	if version, err := schemaVersion(); err == nil {
+		r.schema = version
+	}
+	return r
+}
+
+// Heartbeat reports a metric for aggregation.
+func (r *payloadRecorder) Heartbeat(ctx context.Context, cmd CommandUsage) {
+	body, err := ConvertCommandUsage(cmd, r.schema)
+	if err != nil {
+		return
+	}
+	r.record(Payload{Path: "/usage", Body: body})
+}
+
+// Event reports an analytics action.
+func (r *payloadRecorder) Event(ctx context.Context, cmd DockerCLIEvent) {
+	r.record(Payload{Path: "/analytics/track", Body: AnalyticsRequest{
+		Event: "eventCliCommand",
+		Body:  cmd,
+	}})
+}
+
+func (r *payloadRecorder) record(p Payload) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.payloads = append(r.payloads, p)
+}
+
+// Flush writes the recorded payloads to the file, if any were reported.
+func (r *payloadRecorder) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.path == "" || len(r.payloads) == 0 {
+		return nil
+	}
+	var content []byte
+	for _, payload := range r.payloads {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			// we only pass known types that will marshal without error (no cycles)
+			continue
+		}
+		content = append(content, b...)
+		content = append(content, '\n')
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err != nil || closeErr != nil {
+		_ = os.Remove(tmp.Name())
+		if err == nil {
+			err = closeErr
+		}
+		return err
+	}
+	return os.Rename(tmp.Name(), r.path)
+}
+
+// isMetricsCommand returns true for the commands inspecting metrics, which
+// must not replace the payloads they show.
+func isMetricsCommand(args []string) bool {
+	for i, arg := range args {
+		if arg == "compose" {
+			return i+1 < len(args) && args[i+1] == "metrics"
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestPayloadRecorder(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvVarMetricsSchemaVersion, "1")
+
+	r := newPayloadRecorder(dir)
+	r.Heartbeat(context.Background(), CommandUsage{Command: "compose up", Context: "moby", Source: "cli", Status: SuccessStatus})
+	r.Event(context.Background(), DockerCLIEvent{Command: "compose", Subcommand: "up"})
+	_, err := LastPayloads(dir)
+	assert.Assert(t, os.IsNotExist(err), "payloads are only written on flush")
+
+	assert.NilError(t, r.Flush(context.Background()))
+	b, err := LastPayloads(dir)
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), `{"path":"/usage","body":{"command":"compose up","context":"moby","source":"cli","status":"success"}}`+"\n"+
+		`{"path":"/analytics/track","body":{"event":"eventCliCommand","body":{"command":"compose","subcommand":"up","exit_code":0,"start_time":"0001-01-01T00:00:00Z"}}}`+"\n")
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filepath.Join(dir, lastPayloadsFileName))
+		assert.NilError(t, err)
+		assert.Equal(t, info.Mode().Perm(), os.FileMode(0o600))
+	}
+
+	// a command reporting nothing keeps the payloads
+	assert.NilError(t, newPayloadRecorder(dir).Flush(context.Background()))
+	_, err = LastPayloads(dir)
+	assert.NilError(t, err)
+
+	// the next command replaces the payloads
+	r = newPayloadRecorder(dir)
+	r.Heartbeat(context.Background(), CommandUsage{Command: "ps"})
+	assert.NilError(t, r.Flush(context.Background()))
+	b, err = LastPayloads(dir)
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), `{"path":"/usage","body":{"command":"ps","context":"","source":"","status":""}}`+"\n")
+
+	entries, err := os.ReadDir(dir)
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 1, "no temporary file is left behind")
+}
+
+func TestPreviewPayloads(t *testing.T) {
+	payloads := PreviewPayloads(CmdResult{
+		ContextType: "moby",
+		Args:        []string{"compose", "alpha", "watch"},
+		Status:      SuccessStatus,
+	})
+	assert.Equal(t, len(payloads), 2)
+	body := payloads[0].Body.(versionedCommandUsage)
+	assert.Equal(t, body.Command, "compose alpha watch")
+	assert.Equal(t, payloads[1].Path, "/analytics/track")
+}
+
+func TestIsMetricsCommand(t *testing.T) {
+	assert.Assert(t, isMetricsCommand([]string{"--context", "default", "compose", "metrics", "dump"}))
+	assert.Assert(t, !isMetricsCommand([]string{"compose", "up"}))
+	assert.Assert(t, !isMetricsCommand([]string{"metrics"}))
+}
@@ -0,0 +1,129 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/api/config"
+)
+
+// EnvVarMetricsEndpoint is an optional environment variable setting the base
+// URL metric events are posted to, instead of Docker Desktop. It takes
+// precedence over the `metrics.endpoint` key of the config file.
+const EnvVarMetricsEndpoint = "DOCKER_METRICS_ENDPOINT"
+
+// EnvVarMetricsTransport is an optional environment variable selecting how
+// metric events are sent to Docker Desktop: `http` (the default) posts them
+// over the Docker CLI socket, `socket` writes them as JSON lines to it.
+const EnvVarMetricsTransport = "DOCKER_METRICS_TRANSPORT"
+
+const (
+	transportHTTP   = "http"
+	transportSocket = "socket"
+)
+
+// ipcEndpoint is the base URL used to reach Docker Desktop; the host is
+// ignored as connections are made over the Docker CLI socket.
+const ipcEndpoint = "http://ipc"
+
+// Endpoint returns the configured metrics endpoint, or an empty string if
+// metric events must be sent to Docker Desktop.
+func Endpoint() (string, error) {
+	endpoint := os.Getenv(EnvVarMetricsEndpoint)
+	if endpoint == "" {
+		f, err := config.LoadFile(Dir())
+		if err != nil {
+			return "", err
+		}
+		if f.Metrics != nil {
+			endpoint = f.Metrics.Endpoint
+		}
+	}
+	if endpoint == "" {
+		return "", nil
+	}
+	if err := validateEndpoint(endpoint); err != nil {
+		return "", err
+	}
+	return endpoint, nil
+}
+
+func validateEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid metrics endpoint %q: %w", endpoint, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid metrics endpoint %q: scheme must be http or https", endpoint)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("invalid metrics endpoint %q: missing host", endpoint)
+	}
+	if u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("invalid metrics endpoint %q: credentials, query and fragment are not supported", endpoint)
+	}
+	return nil
+}
+
+// newTransportReporter returns a reporter posting metric events to the
+// configured endpoint over TCP, or to Docker Desktop using the configured
+// transport if none is configured. Heartbeats for a configured endpoint are
+// spooled while it is unreachable, e.g. when working offline, encoded
+// according to DOCKER_METRICS_FORMAT and batched if DOCKER_METRICS_BATCH
+// is set.
+func newTransportReporter() Reporter {
+	endpoint, err := Endpoint()
+	if err != nil {
+		logrus.Warnf("%s, metrics will be sent to Docker Desktop", err)
+	}
+	version, err := schemaVersion()
+	if err != nil {
+		logrus.Warnf("%s, using schema version %d", err, CurrentSchemaVersion)
+		version = CurrentSchemaVersion
+	}
+	if endpoint != "" {
+		format, err := payloadFormat()
+		if err != nil {
+			logrus.Warnf("%s, using %q", err, format)
+		}
+		opts := []HTTPReporterOption{
+			WithEndpoint(endpoint),
+			WithSchemaVersion(version),
+			WithPayloadFormat(format),
+			WithSpool(filepath.Join(Dir(), spoolFileName), DefaultSpoolMaxEntries),
+		}
+		if batchingEnabled() {
+			opts = append(opts, WithBatching(DefaultBatchMaxEntries))
+		}
+		return NewHTTPReporter(&http.Client{Transport: http.DefaultTransport}, opts...)
+	}
+	switch transport := os.Getenv(EnvVarMetricsTransport); transport {
+	case "", transportHTTP:
+	case transportSocket:
+		return NewSocketReporter()
+	default:
+		logrus.Warnf("unknown metrics transport %q, using %q", transport, transportHTTP)
+	}
+	return NewHTTPReporter(newHTTPClient(), WithSchemaVersion(version))
+}
@@ -0,0 +1,109 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"syscall"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/compose"
+	dockerclient "github.com/docker/docker/client"
+)
+
+const (
+	// UserErrorCategory invalid command line or action not available
+	UserErrorCategory = "user-error"
+	// DaemonUnreachableCategory the Docker engine or backend could not be reached
+	DaemonUnreachableCategory = "daemon-unreachable"
+	// BackendAPIErrorCategory the engine or cloud provider API returned an error
+	BackendAPIErrorCategory = "backend-api-error"
+	// ComposeFileInvalidCategory the compose file could not be loaded
+	ComposeFileInvalidCategory = "compose-file-invalid"
+	// CanceledCategory the command was canceled by the user
+	CanceledCategory = "canceled"
+	// UnknownErrorCategory the error could not be classified
+	UnknownErrorCategory = "unknown"
+)
+
+// ClassifyError returns the category of an error returned by a command, or
+// an empty string if there is no error.
+func ClassifyError(err error) string {
+	if err == nil {
+		return ""
+	}
+	if api.IsErrCanceled(err) || errors.Is(err, context.Canceled) {
+		return CanceledCategory
+	}
+	var composeErr compose.Error
+	if errors.As(err, &composeErr) {
+		return errorCategoryFromStatus(composeErr.GetMetricsFailureCategory().MetricsStatus)
+	}
+	if isDaemonUnreachable(err) {
+		return DaemonUnreachableCategory
+	}
+	switch {
+	case api.IsErrNotImplemented(err), api.IsErrUnsupportedFlag(err), api.IsErrParsingFailed(err),
+		errors.Is(err, api.ErrLoginRequired), errors.Is(err, api.ErrLoginFailed), errors.Is(err, api.ErrWrongContextType):
+		return UserErrorCategory
+	case api.IsNotFoundError(err), api.IsAlreadyExistsError(err), api.IsForbiddenError(err), api.IsUnknownError(err):
+		return BackendAPIErrorCategory
+	}
+	msg := err.Error()
+	for _, prefix := range []string{"unknown shorthand flag:", "unknown flag:", "unknown docker command:"} {
+		if strings.HasPrefix(msg, prefix) {
+			return UserErrorCategory
+		}
+	}
+	return UnknownErrorCategory
+}
+
+// ErrorCategoryFromExitCode returns the error category of a command based on
+// its exit code, or an empty string on success.
+func ErrorCategoryFromExitCode(exitCode int) string {
+	if exitCode == 0 {
+		return ""
+	}
+	return errorCategoryFromStatus(FailureCategoryFromExitCode(exitCode).MetricsStatus)
+}
+
+func errorCategoryFromStatus(status string) string {
+	switch status {
+	case SuccessStatus:
+		return ""
+	case CanceledStatus:
+		return CanceledCategory
+	case ComposeParseFailureStatus:
+		return ComposeFileInvalidCategory
+	case FileNotFoundFailureStatus, CommandSyntaxFailureStatus, BuildFailureStatus:
+		return UserErrorCategory
+	case PullFailureStatus:
+		return BackendAPIErrorCategory
+	default:
+		return UnknownErrorCategory
+	}
+}
+
+func isDaemonUnreachable(err error) bool {
+	if dockerclient.IsErrConnectionFailed(err) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "Cannot connect to the Docker daemon") || strings.Contains(msg, "error during connect")
+}
@@ -0,0 +1,66 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/compose"
+	"gotest.tools/v3/assert"
+)
+
+func TestClassifyError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "no error", err: nil, expected: ""},
+		{name: "canceled", err: fmt.Errorf("up: %w", api.ErrCanceled), expected: CanceledCategory},
+		{name: "context canceled", err: context.Canceled, expected: CanceledCategory},
+		{name: "compose parse", err: compose.WrapComposeError(errors.New("services.web must be a mapping")), expected: ComposeFileInvalidCategory},
+		{name: "compose file not found", err: compose.WrapComposeError(&os.PathError{Op: "open", Path: "compose.yaml", Err: os.ErrNotExist}), expected: UserErrorCategory},
+		{name: "pull", err: compose.WrapCategorisedComposeError(errors.New("pull access denied"), compose.PullFailure), expected: BackendAPIErrorCategory},
+		{name: "connection refused", err: &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, expected: DaemonUnreachableCategory},
+		{name: "daemon not running", err: errors.New("Cannot connect to the Docker daemon at unix:///var/run/docker.sock. Is the docker daemon running?"), expected: DaemonUnreachableCategory},
+		{name: "not implemented", err: fmt.Errorf("pause: %w", api.ErrNotImplemented), expected: UserErrorCategory},
+		{name: "login required", err: api.ErrLoginRequired, expected: UserErrorCategory},
+		{name: "unknown flag", err: errors.New("unknown flag: --foo"), expected: UserErrorCategory},
+		{name: "backend not found", err: fmt.Errorf("stack: %w", api.ErrNotFound), expected: BackendAPIErrorCategory},
+		{name: "other", err: errors.New("boom"), expected: UnknownErrorCategory},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, ClassifyError(tc.err), tc.expected)
+		})
+	}
+}
+
+func TestErrorCategoryFromExitCode(t *testing.T) {
+	assert.Equal(t, ErrorCategoryFromExitCode(0), "")
+	assert.Equal(t, ErrorCategoryFromExitCode(15), ComposeFileInvalidCategory)
+	assert.Equal(t, ErrorCategoryFromExitCode(16), UserErrorCategory)
+	assert.Equal(t, ErrorCategoryFromExitCode(130), CanceledCategory)
+	assert.Equal(t, ErrorCategoryFromExitCode(1), UnknownErrorCategory)
+}
@@ -0,0 +1,180 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// EnvVarMetricsLogMaxSize is an optional environment variable setting the
+	// size in megabytes after which the metrics log file is rotated.
+	EnvVarMetricsLogMaxSize = "DOCKER_METRICS_LOG_MAX_SIZE"
+	// EnvVarMetricsLogMaxFiles is an optional environment variable setting the
+	// number of rotated metrics log files kept in addition to the current one.
+	EnvVarMetricsLogMaxFiles = "DOCKER_METRICS_LOG_MAX_FILES"
+
+	// DefaultMetricsLogMaxFiles is the number of rotated files kept when
+	// rotation is enabled without an explicit retention.
+	DefaultMetricsLogMaxFiles = 3
+)
+
+// FileReporter reports metrics as JSON lines to a file, rotating it once it
+// reaches a maximum size. Rotated files are suffixed with `.1` (the most
+// recent) up to `.N`, older files are deleted.
+type FileReporter struct {
+	WriterReporter
+	file *rotatingFile
+}
+
+// NewFileReporter creates a new reporter appending metrics to the file at the
+// given path. The file is rotated once it would exceed maxSize bytes, keeping
+// at most maxFiles rotated files; a maxSize of zero disables rotation.
+func NewFileReporter(path string, maxSize int64, maxFiles int) (*FileReporter, error) {
+	f := &rotatingFile{
+		path:     path,
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return &FileReporter{
+		WriterReporter: NewWriterReporter(f),
+		file:           f,
+	}, nil
+}
+
+// Close closes the current file.
+func (r *FileReporter) Close() error {
+	return r.file.Close()
+}
+
+// newMetricsLogReporter creates the file reporter for the debug metrics log
+// at path. Invalid rotation settings only disable rotation, and a log that
+// can't be opened is skipped, so that the command itself still runs.
+func newMetricsLogReporter(path string) *FileReporter {
+	maxSize, maxFiles, err := metricsLogRotation()
+	if err != nil {
+		logrus.Warnf("metrics log is not rotated: %v", err)
+		maxSize, maxFiles = 0, 0
+	}
+	f, err := NewFileReporter(path, maxSize, maxFiles)
+	if err != nil {
+		logrus.Warnf("usage metrics are not written to %s: %v", path, err)
+		return nil
+	}
+	return f
+}
+
+// metricsLogRotation returns the rotation settings configured from the
+// environment.
+func metricsLogRotation() (int64, int, error) {
+	var maxSize int64
+	if v := os.Getenv(EnvVarMetricsLogMaxSize); v != "" {
+		mb, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || mb < 0 {
+			return 0, 0, fmt.Errorf("invalid %s value %q", EnvVarMetricsLogMaxSize, v)
+		}
+		maxSize = mb * 1024 * 1024
+	}
+	maxFiles := DefaultMetricsLogMaxFiles
+	if v := os.Getenv(EnvVarMetricsLogMaxFiles); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, 0, fmt.Errorf("invalid %s value %q", EnvVarMetricsLogMaxFiles, v)
+		}
+		maxFiles = n
+	}
+	return maxSize, maxFiles, nil
+}
+
+type rotatingFile struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	r.f = f
+	r.size = info.Size()
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+	if r.maxFiles == 0 {
+		if err := os.Remove(r.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return r.open()
+	}
+	_ = os.Remove(r.rotatedPath(r.maxFiles))
+	for i := r.maxFiles - 1; i >= 1; i-- {
+		if err := os.Rename(r.rotatedPath(i), r.rotatedPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(r.path, r.rotatedPath(1)); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+func (r *rotatingFile) rotatedPath(i int) string {
+	return fmt.Sprintf("%s.%d", r.path, i)
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Close()
+}
@@ -0,0 +1,104 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestFileReporterRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.log")
+	line := `{"schema_version":2,"command":"ps","context":"","source":"","status":""}` + "\n"
+
+	// room for 2 lines per file
+	reporter, err := NewFileReporter(path, int64(2*len(line)), 2)
+	assert.NilError(t, err)
+	for i := 0; i < 7; i++ {
+		reporter.Heartbeat(context.Background(), CommandUsage{Command: "ps"})
+	}
+	assert.NilError(t, reporter.Close())
+
+	countLines := func(p string) int {
+		b, err := os.ReadFile(p)
+		assert.NilError(t, err)
+		return strings.Count(string(b), "\n")
+	}
+	assert.Equal(t, countLines(path), 1)
+	assert.Equal(t, countLines(path+".1"), 2)
+	assert.Equal(t, countLines(path+".2"), 2)
+	_, err = os.Stat(path + ".3")
+	assert.Assert(t, os.IsNotExist(err))
+}
+
+func TestFileReporterAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.log")
+	assert.NilError(t, os.WriteFile(path, []byte("existing\n"), 0644))
+
+	reporter, err := NewFileReporter(path, 0, 0)
+	assert.NilError(t, err)
+	reporter.Heartbeat(context.Background(), CommandUsage{Command: "ps"})
+	assert.NilError(t, reporter.Close())
+
+	b, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.HasPrefix(string(b), "existing\n{"))
+}
+
+func TestMetricsLogRotation(t *testing.T) {
+	maxSize, maxFiles, err := metricsLogRotation()
+	assert.NilError(t, err)
+	assert.Equal(t, maxSize, int64(0))
+	assert.Equal(t, maxFiles, DefaultMetricsLogMaxFiles)
+
+	t.Setenv(EnvVarMetricsLogMaxSize, "10")
+	t.Setenv(EnvVarMetricsLogMaxFiles, "5")
+	maxSize, maxFiles, err = metricsLogRotation()
+	assert.NilError(t, err)
+	assert.Equal(t, maxSize, int64(10*1024*1024))
+	assert.Equal(t, maxFiles, 5)
+
+	t.Setenv(EnvVarMetricsLogMaxFiles, "-1")
+	_, _, err = metricsLogRotation()
+	assert.ErrorContains(t, err, "invalid DOCKER_METRICS_LOG_MAX_FILES")
+}
+
+func TestNewMetricsLogReporterInvalidRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.log")
+	t.Setenv(EnvVarMetricsLogMaxSize, "ten")
+	t.Setenv(EnvVarMetricsLogMaxFiles, "-1")
+
+	reporter := newMetricsLogReporter(path)
+	assert.Assert(t, reporter != nil)
+	assert.Equal(t, reporter.file.maxSize, int64(0))
+	reporter.Heartbeat(context.Background(), CommandUsage{Command: "ps"})
+	assert.NilError(t, reporter.Close())
+
+	b, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Equal(t, strings.Count(string(b), "\n"), 1)
+}
+
+func TestNewMetricsLogReporterUnwritable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "metrics.log")
+	assert.Assert(t, newMetricsLogReporter(path) == nil)
+}
@@ -0,0 +1,201 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// EnvVarMetricsGRPCAddress is an optional environment variable setting the
+// address (host:port) of a gRPC collector metric events are also streamed to.
+const EnvVarMetricsGRPCAddress = "DOCKER_METRICS_GRPC_ADDR"
+
+// GRPCReportMethod is the client streaming method collectors must implement.
+// Each message is a google.protobuf.Struct holding an AnalyticsRequest, the
+// response is a google.protobuf.Empty once the stream is closed.
+const GRPCReportMethod = "/docker.cli.metrics.v1.Collector/Report"
+
+var grpcReportStreamDesc = &grpc.StreamDesc{
+	StreamName:    "Report",
+	ClientStreams: true,
+}
+
+// GRPCReporter streams metric events to a collector over gRPC. The stream is
+// opened on the first event and closed by Flush.
+type GRPCReporter struct {
+	conn *grpc.ClientConn
+
+	mu     sync.Mutex
+	stream grpc.ClientStream
+	cancel context.CancelFunc
+}
+
+// NewGRPCReporter creates a new reporter streaming metric events to the
+// collector at the given address. Connections to loopback addresses are made
+// without TLS unless dial options say otherwise.
+func NewGRPCReporter(address string, opts ...grpc.DialOption) (*GRPCReporter, error) {
+	creds := credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	if checkLoopbackAddress(address) == nil {
+		creds = insecure.NewCredentials()
+	}
+	opts = append([]grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                30 * time.Second,
+			Timeout:             Timeout,
+			PermitWithoutStream: false,
+		}),
+	}, opts...)
+	conn, err := grpc.Dial(address, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &GRPCReporter{conn: conn}, nil
+}
+
+// Heartbeat reports a metric for aggregation.
+func (g *GRPCReporter) Heartbeat(ctx context.Context, cmd CommandUsage) {
+	payload, _ := ConvertCommandUsage(cmd, CurrentSchemaVersion)
+	g.send(ctx, AnalyticsRequest{
+		Event: "usage",
+		Body:  payload,
+	})
+}
+
+// Event reports an analytics action.
+func (g *GRPCReporter) Event(ctx context.Context, cmd DockerCLIEvent) {
+	g.send(ctx, AnalyticsRequest{
+		Event: "eventCliCommand",
+		Body:  cmd,
+	})
+}
+
+// Flush closes the current stream and waits for the collector to acknowledge
+// the events, until the context is done.
+func (g *GRPCReporter) Flush(ctx context.Context) error {
+	g.mu.Lock()
+	stream, cancel := g.stream, g.cancel
+	g.stream, g.cancel = nil, nil
+	g.mu.Unlock()
+	if stream == nil {
+		return nil
+	}
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		if err := stream.CloseSend(); err != nil {
+			done <- err
+			return
+		}
+		done <- stream.RecvMsg(&emptypb.Empty{})
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close closes the connection to the collector.
+func (g *GRPCReporter) Close() error {
+	g.mu.Lock()
+	if g.cancel != nil {
+		g.cancel()
+		g.stream, g.cancel = nil, nil
+	}
+	g.mu.Unlock()
+	return g.conn.Close()
+}
+
+func (g *GRPCReporter) send(ctx context.Context, request AnalyticsRequest) {
+	msg, err := toStruct(request)
+	if err != nil {
+		// we only pass known types that will marshal without error (no cycles)
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.stream == nil {
+		if !g.ready(ctx) {
+			return
+		}
+		// the stream outlives the event, only keep the trace context
+		streamCtx, cancel := context.WithCancel(context.Background())
+		if tc, ok := TraceContextFrom(ctx); ok {
+			streamCtx = metadata.AppendToOutgoingContext(streamCtx, "traceparent", tc.TraceParent())
+		}
+		stream, err := g.conn.NewStream(streamCtx, grpcReportStreamDesc, GRPCReportMethod)
+		if err != nil {
+			cancel()
+			return
+		}
+		g.stream, g.cancel = stream, cancel
+	}
+	if err := g.stream.SendMsg(msg); err != nil {
+		// the stream is broken, the next event opens a new one
+		g.cancel()
+		g.stream, g.cancel = nil, nil
+	}
+}
+
+// ready waits for the connection to the collector to be established, for at
+// most Timeout, so that an unreachable collector doesn't block the caller.
+func (g *GRPCReporter) ready(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	g.conn.Connect()
+	for {
+		switch state := g.conn.GetState(); state {
+		case connectivity.Ready:
+			return true
+		case connectivity.Shutdown:
+			return false
+		default:
+			if !g.conn.WaitForStateChange(ctx, state) {
+				return false
+			}
+		}
+	}
+}
+
+func toStruct(v interface{}) (*structpb.Struct, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(m)
+}
@@ -0,0 +1,92 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"gotest.tools/v3/assert"
+)
+
+func TestGRPCReporter(t *testing.T) {
+	received := make(chan *structpb.Struct, 10)
+	server := grpc.NewServer(grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+		method, _ := grpc.MethodFromServerStream(stream)
+		if method != GRPCReportMethod {
+			return errors.New("unexpected method " + method)
+		}
+		for {
+			msg := &structpb.Struct{}
+			if err := stream.RecvMsg(msg); err != nil {
+				if err == io.EOF {
+					return stream.SendMsg(&emptypb.Empty{})
+				}
+				return err
+			}
+			received <- msg
+		}
+	}))
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	go server.Serve(l) //nolint:errcheck
+	defer server.Stop()
+
+	reporter, err := NewGRPCReporter(l.Addr().String())
+	assert.NilError(t, err)
+	defer reporter.Close() //nolint:errcheck
+
+	reporter.Heartbeat(context.Background(), CommandUsage{Command: "compose up", Status: SuccessStatus})
+	reporter.Event(context.Background(), DockerCLIEvent{Command: "compose", Subcommand: "up"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NilError(t, reporter.Flush(ctx))
+	close(received)
+
+	var events []map[string]interface{}
+	for msg := range received {
+		events = append(events, msg.AsMap())
+	}
+	assert.Equal(t, len(events), 2)
+	assert.Equal(t, events[0]["event"], "usage")
+	assert.Equal(t, events[0]["body"].(map[string]interface{})["command"], "compose up")
+	assert.Equal(t, events[1]["event"], "eventCliCommand")
+}
+
+func TestGRPCReporterUnreachable(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	address := l.Addr().String()
+	assert.NilError(t, l.Close())
+
+	reporter, err := NewGRPCReporter(address)
+	assert.NilError(t, err)
+	defer reporter.Close() //nolint:errcheck
+
+	start := time.Now()
+	reporter.Heartbeat(context.Background(), CommandUsage{Command: "ps"})
+	assert.Assert(t, time.Since(start) < time.Second)
+	assert.NilError(t, reporter.Flush(context.Background()))
+}
@@ -0,0 +1,102 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// identitySalt keys the machine identifier hash, so that it can't be matched
+// against the raw machine ID or identifiers derived by other software.
+const identitySalt = "docker-compose-cli/metrics/machine-id/v1"
+
+const machineIDFileName = "metrics-machine-id"
+
+// machineIDFiles are the locations of the OS machine ID, on Linux.
+var machineIDFiles = []string{"/etc/machine-id", "/var/lib/dbus/machine-id"}
+
+var (
+	machineID     string
+	machineIDOnce sync.Once
+)
+
+// MachineID returns a stable identifier of the current user on this machine.
+// It is a salted hash of the OS machine ID (or of a random ID kept in the
+// configuration directory) and of the username, neither of which can be
+// recovered from it.
+func MachineID() string {
+	machineIDOnce.Do(func() {
+		raw := osMachineID()
+		if raw == "" {
+			raw = loadOrCreateID(filepath.Join(Dir(), machineIDFileName))
+		}
+		if raw == "" {
+			return
+		}
+		var username string
+		if u, err := user.Current(); err == nil {
+			username = u.Username
+		}
+		machineID = deriveMachineID(raw, username)
+	})
+	return machineID
+}
+
+func deriveMachineID(raw string, username string) string {
+	mac := hmac.New(sha256.New, []byte(identitySalt))
+	mac.Write([]byte(raw))
+	mac.Write([]byte{0})
+	mac.Write([]byte(username))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+func osMachineID() string {
+	for _, path := range machineIDFiles {
+		if b, err := os.ReadFile(path); err == nil {
+			if id := strings.TrimSpace(string(b)); id != "" {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// loadOrCreateID returns the ID stored in the file at the given path, storing
+// a new random one if there is none.
+func loadOrCreateID(path string) string {
+	if b, err := os.ReadFile(path); err == nil {
+		if id := strings.TrimSpace(string(b)); id != "" {
+			return id
+		}
+	}
+	id := newRandomID()
+	if id == "" {
+		return ""
+	}
+	if err := os.WriteFile(path, []byte(id+"\n"), 0600); err != nil {
+		// not stable across invocations, better not report an identifier
+		return ""
+	}
+	return id
+}
@@ -0,0 +1,43 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestDeriveMachineID(t *testing.T) {
+	id := deriveMachineID("4c4c4544004a", "alice")
+	assert.Equal(t, id, deriveMachineID("4c4c4544004a", "alice"))
+	assert.Equal(t, len(id), 32)
+	assert.Assert(t, id != deriveMachineID("4c4c4544004a", "bob"))
+	assert.Assert(t, id != deriveMachineID("4c4c4544004b", "alice"))
+	assert.Assert(t, !strings.Contains(id, "alice"))
+}
+
+func TestLoadOrCreateID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), machineIDFileName)
+	id := loadOrCreateID(path)
+	assert.Assert(t, id != "")
+	assert.Equal(t, loadOrCreateID(path), id)
+
+	assert.Equal(t, loadOrCreateID(filepath.Join(t.TempDir(), "missing", machineIDFileName)), "")
+}
@@ -17,10 +17,10 @@
 package metrics
 
 import (
+	"context"
 	"os"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/docker/compose/v2/pkg/utils"
 
@@ -28,17 +28,20 @@ import (
 )
 
 func (c *client) Track(cmd CmdResult) {
-	if isInvokedAsCliBackend() {
+	if isInvokedAsCliBackend() || isMetricsCommand(cmd.Args) {
 		return
 	}
 
+	ctx, cancel := context.WithTimeout(withEnvTraceContext(context.Background()), Timeout)
+	defer cancel()
+
 	var wg sync.WaitGroup
 	usageCmd := NewCommandUsage(cmd)
 	if usageCmd != nil {
 		usageCmd.Source = c.getMetadata(CLISource, cmd.Args)
 		wg.Add(1)
 		go func() {
-			c.reporter.Heartbeat(*usageCmd)
+			c.reporter.Heartbeat(ctx, *usageCmd)
 			wg.Done()
 		}()
 	}
@@ -47,7 +50,17 @@ func (c *client) Track(cmd CmdResult) {
 	if eventCmd != nil {
 		wg.Add(1)
 		go func() {
-			c.reporter.Event(*eventCmd)
+			c.reporter.Event(ctx, *eventCmd)
+			wg.Done()
+		}()
+	}
+
+	if phaseEvents := NewPhaseEvents(cmd); len(phaseEvents) > 0 {
+		wg.Add(1)
+		go func() {
+			for _, e := range phaseEvents {
+				c.reporter.Event(ctx, e)
+			}
 			wg.Done()
 		}()
 	}
@@ -60,7 +73,8 @@ func (c *client) Track(cmd CmdResult) {
 
 	select {
 	case <-done:
-	case <-time.After(Timeout):
+		_ = flush(ctx, c.reporter)
+	case <-ctx.Done():
 	}
 }
 
@@ -125,6 +139,8 @@ func GetCommand(args []string) string {
 	return result
 }
 
+// NewCommandUsage returns the heartbeat for a command execution, or nil if the
+// command is unknown.
 func NewCommandUsage(cmd CmdResult) *CommandUsage {
 	command := GetCommand(cmd.Args)
 	if command == "" {
@@ -132,8 +148,16 @@ func NewCommandUsage(cmd CmdResult) *CommandUsage {
 	}
 
 	return &CommandUsage{
-		Command: command,
-		Context: cmd.ContextType,
-		Status:  cmd.Status,
+		Command:        command,
+		Context:        cmd.ContextType,
+		Status:         cmd.Status,
+		ExitCode:       cmd.ExitCode,
+		DurationSecs:   cmd.Duration.Seconds(),
+		BackendVersion: cmd.BackendVersion,
+		Canceled:       cmd.Status == CanceledStatus,
+		ErrorCategory:  cmd.ErrorCategory,
+		SessionID:      SessionID(),
+		InvocationID:   InvocationID(),
+		MachineID:      MachineID(),
 	}
 }
@@ -17,9 +17,13 @@
 package metrics
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/phase"
 )
 
 func TestHasQuietFlag(t *testing.T) {
@@ -412,3 +416,74 @@ func TestScout(t *testing.T) {
 		})
 	}
 }
+
+func TestNewCommandUsage(t *testing.T) {
+	usage := NewCommandUsage(CmdResult{
+		ContextType:    "ecs",
+		BackendVersion: "v1.0.35",
+		Args:           []string{"compose", "up"},
+		Status:         CanceledStatus,
+		ExitCode:       130,
+		Duration:       1500 * time.Millisecond,
+	})
+	assert.DeepEqual(t, usage, &CommandUsage{
+		Command:        "compose up",
+		Context:        "ecs",
+		Status:         CanceledStatus,
+		ExitCode:       130,
+		DurationSecs:   1.5,
+		BackendVersion: "v1.0.35",
+		Canceled:       true,
+		SessionID:      SessionID(),
+		InvocationID:   InvocationID(),
+		MachineID:      MachineID(),
+	})
+
+	event := NewDockerCLIEvent(CmdResult{
+		ContextType:    "ecs",
+		BackendVersion: "v1.0.35",
+		Args:           []string{"compose", "alpha", "watch"},
+	})
+	assert.Equal(t, event.ContextType, "ecs")
+	assert.Equal(t, event.BackendVersion, "v1.0.35")
+
+	assert.Assert(t, NewCommandUsage(CmdResult{Args: []string{"--debug"}}) == nil)
+}
+
+func TestNewPhaseEvents(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	cmd := CmdResult{
+		Args:        []string{"compose", "up"},
+		ContextType: "ecs",
+		ExitCode:    1,
+		Start:       start,
+		Duration:    10 * time.Second,
+		Phases: []phase.Phase{
+			{Name: phase.ImagePull, Start: start.Add(time.Second), Duration: 2 * time.Second},
+			{Name: phase.StackWait, Start: start.Add(3 * time.Second), Duration: 500 * time.Millisecond, Err: errors.New("rollback")},
+		},
+	}
+	assert.DeepEqual(t, NewPhaseEvents(cmd), []DockerCLIEvent{
+		{Command: "compose", Subcommand: "up", StartTime: start.Add(time.Second), DurationSecs: 2, ContextType: "ecs", Phase: phase.ImagePull},
+		{Command: "compose", Subcommand: "up", ExitCode: 1, StartTime: start.Add(3 * time.Second), DurationSecs: 0.5, ContextType: "ecs", Phase: phase.StackWait},
+	})
+
+	cmd.Args = []string{"--debug"}
+	assert.Equal(t, len(NewPhaseEvents(cmd)), 0)
+}
+
+func TestTrackPhaseEvents(t *testing.T) {
+	reporter := NewRecordingReporter()
+	c := NewClient(reporter)
+	c.Track(CmdResult{
+		Args:   []string{"compose", "up"},
+		Status: SuccessStatus,
+		Phases: []phase.Phase{{Name: phase.Build, Duration: time.Second}},
+	})
+
+	assert.DeepEqual(t, reporter.Commands(), []string{"compose up"})
+	events := reporter.Events()
+	assert.Equal(t, len(events), 1)
+	assert.Equal(t, events[0].Subcommand, "up")
+	assert.Equal(t, events[0].Phase, phase.Build)
+}
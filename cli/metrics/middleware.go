@@ -0,0 +1,97 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import "context"
+
+// ReporterMiddleware wraps a Reporter to add behavior around it, such as
+// enriching, filtering or logging metric events.
+type ReporterMiddleware func(Reporter) Reporter
+
+// Chain wraps the reporter with the given middlewares; the first one is the
+// outermost, i.e. it sees metric events first. For example, to enrich the
+// default reporter:
+//
+//	metrics.NewClient(metrics.Chain(metrics.NewDefaultReporter(), myMiddleware))
+func Chain(reporter Reporter, middlewares ...ReporterMiddleware) Reporter {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		reporter = middlewares[i](reporter)
+	}
+	return reporter
+}
+
+// HeartbeatInterceptor returns a middleware calling fn on each heartbeat
+// before forwarding the heartbeat it returns. The heartbeat is dropped if fn
+// returns false. Events are forwarded as is.
+func HeartbeatInterceptor(fn func(CommandUsage) (CommandUsage, bool)) ReporterMiddleware {
+	return func(next Reporter) Reporter {
+		return interceptor{
+			reporter:  next,
+			heartbeat: fn,
+		}
+	}
+}
+
+// EventInterceptor returns a middleware calling fn on each event before
+// forwarding the event it returns. The event is dropped if fn returns false.
+// Heartbeats are forwarded as is.
+func EventInterceptor(fn func(DockerCLIEvent) (DockerCLIEvent, bool)) ReporterMiddleware {
+	return func(next Reporter) Reporter {
+		return interceptor{
+			reporter: next,
+			event:    fn,
+		}
+	}
+}
+
+type interceptor struct {
+	reporter  Reporter
+	heartbeat func(CommandUsage) (CommandUsage, bool)
+	event     func(DockerCLIEvent) (DockerCLIEvent, bool)
+}
+
+// Heartbeat reports a metric for aggregation.
+func (i interceptor) Heartbeat(ctx context.Context, cmd CommandUsage) {
+	if i.heartbeat != nil {
+		var ok bool
+		if cmd, ok = i.heartbeat(cmd); !ok {
+			return
+		}
+	}
+	i.reporter.Heartbeat(ctx, cmd)
+}
+
+// Event reports an analytics action.
+func (i interceptor) Event(ctx context.Context, cmd DockerCLIEvent) {
+	if i.event != nil {
+		var ok bool
+		if cmd, ok = i.event(cmd); !ok {
+			return
+		}
+	}
+	i.reporter.Event(ctx, cmd)
+}
+
+// Flush delivers pending metric events of the wrapped reporter.
+func (i interceptor) Flush(ctx context.Context) error {
+	return flush(ctx, i.reporter)
+}
+
+// Close closes the wrapped reporter.
+func (i interceptor) Close() error {
+	return closeReporter(i.reporter)
+}
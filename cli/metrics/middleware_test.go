@@ -0,0 +1,59 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestChain(t *testing.T) {
+	recorder := NewRecordingReporter()
+	var order []string
+	tag := func(name string) ReporterMiddleware {
+		return HeartbeatInterceptor(func(cmd CommandUsage) (CommandUsage, bool) {
+			order = append(order, name)
+			cmd.Source += name
+			return cmd, cmd.Command != "skip"
+		})
+	}
+	r := Chain(recorder, tag("a"), tag("b"))
+
+	r.Heartbeat(context.Background(), CommandUsage{Command: "up"})
+	r.Heartbeat(context.Background(), CommandUsage{Command: "skip"})
+	assert.DeepEqual(t, order, []string{"a", "b", "a"})
+	assert.DeepEqual(t, recorder.Heartbeats(), []CommandUsage{{Command: "up", Source: "ab"}})
+}
+
+func TestEventInterceptorFlush(t *testing.T) {
+	inner := &blockingReporter{release: make(chan struct{})}
+	close(inner.release)
+	async := NewAsyncReporter(inner, 8)
+	var events int
+	r := Chain(async, EventInterceptor(func(cmd DockerCLIEvent) (DockerCLIEvent, bool) {
+		events++
+		return cmd, false
+	}))
+
+	r.Event(context.Background(), DockerCLIEvent{Command: "compose"})
+	r.Heartbeat(context.Background(), CommandUsage{Command: "up"})
+	assert.NilError(t, flush(context.Background(), r))
+	assert.Equal(t, events, 1)
+	assert.DeepEqual(t, inner.commands, []string{"up"})
+}
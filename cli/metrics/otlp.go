@@ -0,0 +1,426 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// EnvVarOTLPEndpoint is the base URL of the OTLP collector used for all
+	// signals.
+	EnvVarOTLPEndpoint = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	// EnvVarOTLPMetricsEndpoint overrides the full URL used to export metrics.
+	EnvVarOTLPMetricsEndpoint = "OTEL_EXPORTER_OTLP_METRICS_ENDPOINT"
+	// EnvVarOTLPTracesEndpoint overrides the full URL used to export spans.
+	EnvVarOTLPTracesEndpoint = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	// EnvVarOTLPHeaders is a list of `key=value` pairs sent with every export
+	// request.
+	EnvVarOTLPHeaders = "OTEL_EXPORTER_OTLP_HEADERS"
+	// EnvVarOTLPProtocol selects the OTLP transport protocol, `http/protobuf`
+	// (the default) or `http/json`. The `grpc` protocol isn't supported.
+	EnvVarOTLPProtocol = "OTEL_EXPORTER_OTLP_PROTOCOL"
+	// EnvVarOTLPTimeout is the maximum time in milliseconds to wait for an
+	// export, 10 seconds by default.
+	EnvVarOTLPTimeout = "OTEL_EXPORTER_OTLP_TIMEOUT"
+	// EnvVarOTelServiceName sets the `service.name` resource attribute.
+	EnvVarOTelServiceName = "OTEL_SERVICE_NAME"
+
+	otlpProtocolHTTPJSON     = "http/json"
+	otlpProtocolHTTPProtobuf = "http/protobuf"
+	otlpProtocolGRPC         = "grpc"
+	// default export timeout, see the OpenTelemetry exporter specification
+	otlpDefaultTimeout = 10 * time.Second
+	otlpMetricName     = "docker.cli.command.usage"
+	otlpScopeName      = "github.com/docker/compose-cli/cli/metrics"
+	otlpDefaultService = "docker-cli"
+	// aggregation temporality delta, see opentelemetry-proto metrics.proto
+	otlpTemporalityDelta = 1
+	// span kind internal, see opentelemetry-proto trace.proto
+	otlpSpanKindInternal = 1
+	// span status codes, see opentelemetry-proto trace.proto
+	otlpStatusCodeOk    = 1
+	otlpStatusCodeError = 2
+)
+
+// OTLPReporter reports metric events to an OpenTelemetry collector using
+// OTLP over HTTP with JSON or protobuf encoding.
+//
+// Heartbeats are exported as a delta sum metric, events as spans covering
+// the command execution.
+type OTLPReporter struct {
+	client          *http.Client
+	metricsEndpoint string
+	tracesEndpoint  string
+	headers         map[string]string
+	serviceName     string
+	protobuf        bool
+}
+
+// IsOTLPConfigured returns true if an OTLP collector endpoint has been set
+// through the standard OTEL_EXPORTER_OTLP_* environment variables.
+func IsOTLPConfigured() bool {
+	for _, v := range []string{EnvVarOTLPEndpoint, EnvVarOTLPMetricsEndpoint, EnvVarOTLPTracesEndpoint} {
+		if os.Getenv(v) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// NewOTLPReporter creates a new reporter configured from the standard
+// OTEL_EXPORTER_OTLP_* environment variables.
+//
+// The `http/protobuf` protocol, used by default as in the OpenTelemetry
+// specification, and the `http/json` protocol are supported; other protocols,
+// `grpc` included, return an error.
+func NewOTLPReporter() (OTLPReporter, error) {
+	protocol := os.Getenv(EnvVarOTLPProtocol)
+	switch protocol {
+	case "":
+		protocol = otlpProtocolHTTPProtobuf
+	case otlpProtocolHTTPJSON, otlpProtocolHTTPProtobuf:
+	case otlpProtocolGRPC:
+		return OTLPReporter{}, fmt.Errorf("the %q OTLP protocol is not supported, set %s to %q or %q with the collector HTTP endpoint",
+			protocol, EnvVarOTLPProtocol, otlpProtocolHTTPProtobuf, otlpProtocolHTTPJSON)
+	default:
+		return OTLPReporter{}, fmt.Errorf("unsupported OTLP protocol %q, only %q and %q are supported",
+			protocol, otlpProtocolHTTPJSON, otlpProtocolHTTPProtobuf)
+	}
+
+	base := os.Getenv(EnvVarOTLPEndpoint)
+	metricsEndpoint, err := otlpSignalEndpoint(base, os.Getenv(EnvVarOTLPMetricsEndpoint), "v1/metrics")
+	if err != nil {
+		return OTLPReporter{}, err
+	}
+	tracesEndpoint, err := otlpSignalEndpoint(base, os.Getenv(EnvVarOTLPTracesEndpoint), "v1/traces")
+	if err != nil {
+		return OTLPReporter{}, err
+	}
+
+	headers, err := parseOTLPHeaders(os.Getenv(EnvVarOTLPHeaders))
+	if err != nil {
+		return OTLPReporter{}, err
+	}
+
+	timeout := otlpDefaultTimeout
+	if v := os.Getenv(EnvVarOTLPTimeout); v != "" {
+		ms, err := strconv.Atoi(v)
+		if err != nil || ms < 0 {
+			return OTLPReporter{}, fmt.Errorf("invalid %s value %q", EnvVarOTLPTimeout, v)
+		}
+		timeout = time.Duration(ms) * time.Millisecond
+	}
+
+	serviceName := otlpDefaultService
+	if v := os.Getenv(EnvVarOTelServiceName); v != "" {
+		serviceName = v
+	}
+
+	return OTLPReporter{
+		client:          &http.Client{Timeout: timeout},
+		metricsEndpoint: metricsEndpoint,
+		tracesEndpoint:  tracesEndpoint,
+		headers:         headers,
+		serviceName:     serviceName,
+		protobuf:        protocol == otlpProtocolHTTPProtobuf,
+	}, nil
+}
+
+// otlpSignalEndpoint returns the per-signal URL, which is used as-is when
+// set, otherwise the signal path is appended to the base endpoint.
+func otlpSignalEndpoint(base string, signal string, path string) (string, error) {
+	if signal != "" {
+		if _, err := url.ParseRequestURI(signal); err != nil {
+			return "", fmt.Errorf("invalid OTLP endpoint %q: %w", signal, err)
+		}
+		return signal, nil
+	}
+	if base == "" {
+		return "", nil
+	}
+	u, err := url.JoinPath(base, path)
+	if err != nil {
+		return "", fmt.Errorf("invalid OTLP endpoint %q: %w", base, err)
+	}
+	return u, nil
+}
+
+func parseOTLPHeaders(s string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid %s entry %q", EnvVarOTLPHeaders, pair)
+		}
+		value, err := url.QueryUnescape(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s entry %q: %w", EnvVarOTLPHeaders, pair, err)
+		}
+		headers[strings.TrimSpace(k)] = value
+	}
+	return headers, nil
+}
+
+// Heartbeat reports a metric for aggregation.
+func (o OTLPReporter) Heartbeat(ctx context.Context, cmd CommandUsage) {
+	if o.metricsEndpoint == "" {
+		return
+	}
+	now := strconv.FormatInt(time.Now().UnixNano(), 10)
+	o.post(ctx, o.metricsEndpoint, otlpMetricsRequest{
+		ResourceMetrics: []otlpResourceMetrics{{
+			Resource: o.resource(),
+			ScopeMetrics: []otlpScopeMetrics{{
+				Scope: otlpScope{Name: otlpScopeName},
+				Metrics: []otlpMetric{{
+					Name: otlpMetricName,
+					Unit: "{command}",
+					Sum: &otlpSum{
+						AggregationTemporality: otlpTemporalityDelta,
+						IsMonotonic:            true,
+						DataPoints: []otlpNumberDataPoint{{
+							Attributes: []otlpKeyValue{
+								otlpString("command", cmd.Command),
+								otlpString("context", cmd.Context),
+								otlpString("backend_version", cmd.BackendVersion),
+								otlpString("source", cmd.Source),
+								otlpString("status", cmd.Status),
+							},
+							StartTimeUnixNano: now,
+							TimeUnixNano:      now,
+							AsInt:             strconv.Itoa(cmd.count()),
+						}},
+					},
+				}},
+			}},
+		}},
+	})
+}
+
+// Event reports an analytics action.
+func (o OTLPReporter) Event(ctx context.Context, cmd DockerCLIEvent) {
+	if o.tracesEndpoint == "" {
+		return
+	}
+	traceID, spanID, err := newOTLPSpanIDs()
+	if err != nil {
+		return
+	}
+	var parentSpanID string
+	if tc, ok := TraceContextFrom(ctx); ok {
+		traceID, parentSpanID = tc.TraceID, tc.SpanID
+	}
+	name := strings.TrimSpace(cmd.Command + " " + cmd.Subcommand + " " + cmd.Phase)
+	end := cmd.StartTime.Add(time.Duration(cmd.DurationSecs * float64(time.Second)))
+	attributes := []otlpKeyValue{
+		otlpString("command", cmd.Command),
+		otlpString("subcommand", cmd.Subcommand),
+		otlpBool("usage", cmd.Usage),
+		otlpInt("exit_code", int64(cmd.ExitCode)),
+		otlpString("context_type", cmd.ContextType),
+		otlpString("backend_version", cmd.BackendVersion),
+	}
+	if cmd.Phase != "" {
+		attributes = append(attributes, otlpString("phase", cmd.Phase))
+	}
+	status := otlpStatus{Code: otlpStatusCodeOk}
+	if cmd.ExitCode != 0 {
+		status = otlpStatus{Code: otlpStatusCodeError}
+	}
+	o.post(ctx, o.tracesEndpoint, otlpTracesRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: o.resource(),
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: otlpScopeName},
+				Spans: []otlpSpan{{
+					TraceID:           traceID,
+					SpanID:            spanID,
+					ParentSpanID:      parentSpanID,
+					Name:              "docker " + name,
+					Kind:              otlpSpanKindInternal,
+					StartTimeUnixNano: strconv.FormatInt(cmd.StartTime.UnixNano(), 10),
+					EndTimeUnixNano:   strconv.FormatInt(end.UnixNano(), 10),
+					Attributes:        attributes,
+					Status:            status,
+				}},
+			}},
+		}},
+	})
+}
+
+// Close closes the idle connections to the collector.
+func (o OTLPReporter) Close() error {
+	o.client.CloseIdleConnections()
+	return nil
+}
+
+func (o OTLPReporter) resource() otlpResource {
+	return otlpResource{
+		Attributes: []otlpKeyValue{otlpString("service.name", o.serviceName)},
+	}
+}
+
+func (o OTLPReporter) post(ctx context.Context, endpoint string, body otlpRequest) {
+	contentType := "application/json"
+	var entry []byte
+	if o.protobuf {
+		contentType = "application/x-protobuf"
+		entry = body.appendProto(nil)
+	} else {
+		var err error
+		entry, err = json.Marshal(body)
+		if err != nil {
+			// we only pass known types that will marshal without error (no cycles)
+			return
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(entry))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range o.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, _ := o.client.Do(req)
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+}
+
+func newOTLPSpanIDs() (string, string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(b[:16]), hex.EncodeToString(b[16:]), nil
+}
+
+// The types below mirror the protobuf JSON mapping of the OTLP export
+// requests, see https://github.com/open-telemetry/opentelemetry-proto.
+
+type otlpMetricsRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpMetric struct {
+	Name string   `json:"name"`
+	Unit string   `json:"unit,omitempty"`
+	Sum  *otlpSum `json:"sum,omitempty"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpNumberDataPoint `json:"dataPoints"`
+	AggregationTemporality int                   `json:"aggregationTemporality"`
+	IsMonotonic            bool                  `json:"isMonotonic"`
+}
+
+type otlpNumberDataPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	TimeUnixNano      string         `json:"timeUnixNano"`
+	AsInt             string         `json:"asInt"`
+}
+
+type otlpTracesRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code int `json:"code"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+func otlpString(k string, v string) otlpKeyValue {
+	return otlpKeyValue{Key: k, Value: otlpAnyValue{StringValue: &v}}
+}
+
+func otlpBool(k string, v bool) otlpKeyValue {
+	return otlpKeyValue{Key: k, Value: otlpAnyValue{BoolValue: &v}}
+}
+
+func otlpInt(k string, v int64) otlpKeyValue {
+	s := strconv.FormatInt(v, 10)
+	return otlpKeyValue{Key: k, Value: otlpAnyValue{IntValue: &s}}
+}
@@ -0,0 +1,212 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/hex"
+	"strconv"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// otlpRequest is an OTLP export request, marshalled to JSON by the http/json
+// protocol or appended in the protobuf wire format by the http/protobuf one.
+type otlpRequest interface {
+	appendProto(b []byte) []byte
+}
+
+// The methods below encode the OTLP export requests with the field numbers of
+// https://github.com/open-telemetry/opentelemetry-proto, skipping empty
+// fields as proto3 does.
+
+func (r otlpMetricsRequest) appendProto(b []byte) []byte {
+	for _, m := range r.ResourceMetrics {
+		b = appendProtoMessage(b, 1, m.appendProto)
+	}
+	return b
+}
+
+func (r otlpResourceMetrics) appendProto(b []byte) []byte {
+	b = appendProtoMessage(b, 1, r.Resource.appendProto)
+	for _, m := range r.ScopeMetrics {
+		b = appendProtoMessage(b, 2, m.appendProto)
+	}
+	return b
+}
+
+func (s otlpScopeMetrics) appendProto(b []byte) []byte {
+	b = appendProtoMessage(b, 1, s.Scope.appendProto)
+	for _, m := range s.Metrics {
+		b = appendProtoMessage(b, 2, m.appendProto)
+	}
+	return b
+}
+
+func (m otlpMetric) appendProto(b []byte) []byte {
+	b = appendProtoString(b, 1, m.Name)
+	b = appendProtoString(b, 3, m.Unit)
+	if m.Sum != nil {
+		b = appendProtoMessage(b, 7, m.Sum.appendProto)
+	}
+	return b
+}
+
+func (s otlpSum) appendProto(b []byte) []byte {
+	for _, p := range s.DataPoints {
+		b = appendProtoMessage(b, 1, p.appendProto)
+	}
+	b = appendProtoVarint(b, 2, uint64(s.AggregationTemporality))
+	if s.IsMonotonic {
+		b = appendProtoVarint(b, 3, 1)
+	}
+	return b
+}
+
+func (p otlpNumberDataPoint) appendProto(b []byte) []byte {
+	b = appendProtoFixed64(b, 2, parseProtoUint(p.StartTimeUnixNano))
+	b = appendProtoFixed64(b, 3, parseProtoUint(p.TimeUnixNano))
+	b = appendProtoFixed64(b, 6, uint64(parseProtoInt(p.AsInt)))
+	for _, kv := range p.Attributes {
+		b = appendProtoMessage(b, 7, kv.appendProto)
+	}
+	return b
+}
+
+func (r otlpTracesRequest) appendProto(b []byte) []byte {
+	for _, s := range r.ResourceSpans {
+		b = appendProtoMessage(b, 1, s.appendProto)
+	}
+	return b
+}
+
+func (r otlpResourceSpans) appendProto(b []byte) []byte {
+	b = appendProtoMessage(b, 1, r.Resource.appendProto)
+	for _, s := range r.ScopeSpans {
+		b = appendProtoMessage(b, 2, s.appendProto)
+	}
+	return b
+}
+
+func (s otlpScopeSpans) appendProto(b []byte) []byte {
+	b = appendProtoMessage(b, 1, s.Scope.appendProto)
+	for _, span := range s.Spans {
+		b = appendProtoMessage(b, 2, span.appendProto)
+	}
+	return b
+}
+
+func (s otlpSpan) appendProto(b []byte) []byte {
+	b = appendProtoHex(b, 1, s.TraceID)
+	b = appendProtoHex(b, 2, s.SpanID)
+	b = appendProtoHex(b, 4, s.ParentSpanID)
+	b = appendProtoString(b, 5, s.Name)
+	b = appendProtoVarint(b, 6, uint64(s.Kind))
+	b = appendProtoFixed64(b, 7, parseProtoUint(s.StartTimeUnixNano))
+	b = appendProtoFixed64(b, 8, parseProtoUint(s.EndTimeUnixNano))
+	for _, kv := range s.Attributes {
+		b = appendProtoMessage(b, 9, kv.appendProto)
+	}
+	return appendProtoMessage(b, 15, s.Status.appendProto)
+}
+
+func (s otlpStatus) appendProto(b []byte) []byte {
+	return appendProtoVarint(b, 3, uint64(s.Code))
+}
+
+func (r otlpResource) appendProto(b []byte) []byte {
+	for _, kv := range r.Attributes {
+		b = appendProtoMessage(b, 1, kv.appendProto)
+	}
+	return b
+}
+
+func (s otlpScope) appendProto(b []byte) []byte {
+	return appendProtoString(b, 1, s.Name)
+}
+
+func (kv otlpKeyValue) appendProto(b []byte) []byte {
+	b = appendProtoString(b, 1, kv.Key)
+	return appendProtoMessage(b, 2, kv.Value.appendProto)
+}
+
+func (v otlpAnyValue) appendProto(b []byte) []byte {
+	switch {
+	case v.StringValue != nil:
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendString(b, *v.StringValue)
+	case v.BoolValue != nil:
+		b = protowire.AppendTag(b, 2, protowire.VarintType)
+		b = protowire.AppendVarint(b, protowire.EncodeBool(*v.BoolValue))
+	case v.IntValue != nil:
+		b = protowire.AppendTag(b, 3, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(parseProtoInt(*v.IntValue)))
+	}
+	return b
+}
+
+// appendProtoMessage appends the message encoded by appendFn as a length
+// delimited field, even when empty.
+func appendProtoMessage(b []byte, num protowire.Number, appendFn func([]byte) []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, appendFn(nil))
+}
+
+func appendProtoString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+// appendProtoHex appends the bytes of an hex encoded trace or span id.
+func appendProtoHex(b []byte, num protowire.Number, s string) []byte {
+	id, err := hex.DecodeString(s)
+	if err != nil || len(id) == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, id)
+}
+
+func appendProtoVarint(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func appendProtoFixed64(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, v)
+}
+
+// parseProtoUint and parseProtoInt parse the decimal strings the JSON mapping
+// uses for 64 bits integers, we only set valid ones.
+func parseProtoUint(s string) uint64 {
+	v, _ := strconv.ParseUint(s, 10, 64)
+	return v
+}
+
+func parseProtoInt(s string) int64 {
+	v, _ := strconv.ParseInt(s, 10, 64)
+	return v
+}
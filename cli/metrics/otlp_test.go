@@ -0,0 +1,187 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"gotest.tools/v3/assert"
+)
+
+func TestOTLPReporter(t *testing.T) {
+	requests := map[string][]byte{}
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests[r.URL.Path] = body
+		auth = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	t.Setenv(EnvVarOTLPEndpoint, server.URL)
+	t.Setenv(EnvVarOTLPProtocol, "http/json")
+	t.Setenv(EnvVarOTLPHeaders, "Authorization=Bearer%20token")
+	t.Setenv(EnvVarOTLPTimeout, "1000")
+	t.Setenv(EnvVarOTelServiceName, "compose-test")
+	assert.Assert(t, IsOTLPConfigured())
+
+	reporter, err := NewOTLPReporter()
+	assert.NilError(t, err)
+
+	reporter.Heartbeat(context.Background(), CommandUsage{Command: "compose up", Context: "moby", Source: "cli", Status: SuccessStatus})
+	reporter.Event(context.Background(), DockerCLIEvent{Command: "compose", Subcommand: "alpha-watch", ExitCode: 1, StartTime: time.Now(), DurationSecs: 1.5})
+
+	assert.Equal(t, auth, "Bearer token")
+
+	var metrics otlpMetricsRequest
+	assert.NilError(t, json.Unmarshal(requests["/v1/metrics"], &metrics))
+	assert.Equal(t, *metrics.ResourceMetrics[0].Resource.Attributes[0].Value.StringValue, "compose-test")
+	metric := metrics.ResourceMetrics[0].ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, metric.Name, otlpMetricName)
+	assert.Equal(t, metric.Sum.DataPoints[0].AsInt, "1")
+	assert.Equal(t, *metric.Sum.DataPoints[0].Attributes[0].Value.StringValue, "compose up")
+
+	var traces otlpTracesRequest
+	assert.NilError(t, json.Unmarshal(requests["/v1/traces"], &traces))
+	span := traces.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	assert.Equal(t, span.Name, "docker compose alpha-watch")
+	assert.Equal(t, span.Status.Code, otlpStatusCodeError)
+	assert.Equal(t, len(span.TraceID), 32)
+	assert.Equal(t, len(span.SpanID), 16)
+}
+
+func TestOTLPReporterProtobuf(t *testing.T) {
+	requests := map[string][]byte{}
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests[r.URL.Path] = body
+		contentType = r.Header.Get("Content-Type")
+	}))
+	defer server.Close()
+
+	t.Setenv(EnvVarOTLPEndpoint, server.URL)
+	t.Setenv(EnvVarOTLPProtocol, "http/protobuf")
+
+	reporter, err := NewOTLPReporter()
+	assert.NilError(t, err)
+
+	reporter.Heartbeat(context.Background(), CommandUsage{Command: "compose up", Status: SuccessStatus})
+	reporter.Event(context.Background(), DockerCLIEvent{Command: "compose", Subcommand: "up", ExitCode: 1, StartTime: time.Now()})
+
+	assert.Equal(t, contentType, "application/x-protobuf")
+
+	// ResourceMetrics.ScopeMetrics.Metrics.Name
+	metric := protoField(t, protoField(t, protoField(t, requests["/v1/metrics"], 1), 2), 2)
+	assert.Equal(t, string(protoField(t, metric, 1)), otlpMetricName)
+	// Sum.DataPoints.AsInt
+	dataPoint := protoField(t, protoField(t, metric, 7), 1)
+	assert.Equal(t, protoFixed64(t, dataPoint, 6), uint64(1))
+
+	// ResourceSpans.ScopeSpans.Spans
+	span := protoField(t, protoField(t, protoField(t, requests["/v1/traces"], 1), 2), 2)
+	assert.Equal(t, string(protoField(t, span, 5)), "docker compose up")
+	assert.Equal(t, len(protoField(t, span, 1)), 16)
+	assert.Equal(t, len(protoField(t, span, 2)), 8)
+	// Status.Code
+	status := protoField(t, span, 15)
+	num, typ, l := protowire.ConsumeTag(status)
+	assert.Equal(t, num, protowire.Number(3))
+	assert.Equal(t, typ, protowire.VarintType)
+	code, _ := protowire.ConsumeVarint(status[l:])
+	assert.Equal(t, code, uint64(otlpStatusCodeError))
+}
+
+// protoField returns the first length delimited field with the given number.
+func protoField(t *testing.T, b []byte, num protowire.Number) []byte {
+	for len(b) > 0 {
+		n, typ, l := protowire.ConsumeTag(b)
+		assert.Assert(t, l > 0)
+		b = b[l:]
+		if n == num && typ == protowire.BytesType {
+			v, l := protowire.ConsumeBytes(b)
+			assert.Assert(t, l > 0)
+			return v
+		}
+		l = protowire.ConsumeFieldValue(n, typ, b)
+		assert.Assert(t, l > 0)
+		b = b[l:]
+	}
+	t.Fatalf("field %d not found", num)
+	return nil
+}
+
+func protoFixed64(t *testing.T, b []byte, num protowire.Number) uint64 {
+	for len(b) > 0 {
+		n, typ, l := protowire.ConsumeTag(b)
+		assert.Assert(t, l > 0)
+		b = b[l:]
+		if n == num && typ == protowire.Fixed64Type {
+			v, l := protowire.ConsumeFixed64(b)
+			assert.Assert(t, l > 0)
+			return v
+		}
+		l = protowire.ConsumeFieldValue(n, typ, b)
+		assert.Assert(t, l > 0)
+		b = b[l:]
+	}
+	t.Fatalf("field %d not found", num)
+	return 0
+}
+
+func TestOTLPReporterConfig(t *testing.T) {
+	t.Run("signal endpoint", func(t *testing.T) {
+		t.Setenv(EnvVarOTLPEndpoint, "http://collector:4318")
+		t.Setenv(EnvVarOTLPMetricsEndpoint, "http://metrics:9000/custom")
+		reporter, err := NewOTLPReporter()
+		assert.NilError(t, err)
+		assert.Equal(t, reporter.metricsEndpoint, "http://metrics:9000/custom")
+		assert.Equal(t, reporter.tracesEndpoint, "http://collector:4318/v1/traces")
+	})
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv(EnvVarOTLPEndpoint, "http://collector:4318")
+		reporter, err := NewOTLPReporter()
+		assert.NilError(t, err)
+		assert.Assert(t, reporter.protobuf)
+		assert.Equal(t, reporter.client.Timeout, 10*time.Second)
+	})
+	t.Run("grpc protocol", func(t *testing.T) {
+		t.Setenv(EnvVarOTLPEndpoint, "http://collector:4317")
+		t.Setenv(EnvVarOTLPProtocol, "grpc")
+		_, err := NewOTLPReporter()
+		assert.ErrorContains(t, err, `the "grpc" OTLP protocol is not supported, set OTEL_EXPORTER_OTLP_PROTOCOL to "http/protobuf"`)
+	})
+	t.Run("unsupported protocol", func(t *testing.T) {
+		t.Setenv(EnvVarOTLPEndpoint, "http://collector:4318")
+		t.Setenv(EnvVarOTLPProtocol, "http/xml")
+		_, err := NewOTLPReporter()
+		assert.ErrorContains(t, err, `unsupported OTLP protocol "http/xml"`)
+	})
+	t.Run("invalid headers", func(t *testing.T) {
+		t.Setenv(EnvVarOTLPEndpoint, "http://collector:4318")
+		t.Setenv(EnvVarOTLPHeaders, "novalue")
+		_, err := NewOTLPReporter()
+		assert.ErrorContains(t, err, "invalid OTEL_EXPORTER_OTLP_HEADERS entry")
+	})
+}
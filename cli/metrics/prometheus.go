@@ -0,0 +1,127 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// EnvVarPrometheusAddress is an optional environment variable used to expose
+// the metrics aggregated by long-running processes on a Prometheus scrape
+// endpoint. The address must be bound to a loopback interface.
+const EnvVarPrometheusAddress = "DOCKER_METRICS_PROMETHEUS_ADDR"
+
+// PrometheusReporter aggregates metric events in-process and exposes them
+// for scraping in the Prometheus text format.
+type PrometheusReporter struct {
+	registry *prometheus.Registry
+	commands *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewPrometheusReporter creates a new reporter aggregating metric events into
+// Prometheus collectors.
+func NewPrometheusReporter() *PrometheusReporter {
+	p := &PrometheusReporter{
+		registry: prometheus.NewRegistry(),
+		commands: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "docker",
+			Subsystem: "cli",
+			Name:      "commands_total",
+			Help:      "Number of commands executed, by command, context, source and status.",
+		}, []string{"command", "context", "source", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "docker",
+			Subsystem: "cli",
+			Name:      "command_duration_seconds",
+			Help:      "Duration of command executions in seconds.",
+			Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+		}, []string{"command", "subcommand", "exit_code"}),
+	}
+	p.registry.MustRegister(p.commands, p.duration)
+	return p
+}
+
+// Heartbeat reports a metric for aggregation.
+func (p *PrometheusReporter) Heartbeat(ctx context.Context, cmd CommandUsage) {
+	p.commands.WithLabelValues(cmd.Command, cmd.Context, cmd.Source, cmd.Status).Inc()
+}
+
+// Event reports an analytics action. The events of the command phases are
+// left out of the command durations.
+func (p *PrometheusReporter) Event(ctx context.Context, cmd DockerCLIEvent) {
+	if cmd.Phase != "" {
+		return
+	}
+	p.duration.WithLabelValues(cmd.Command, cmd.Subcommand, fmt.Sprint(cmd.ExitCode)).Observe(cmd.DurationSecs)
+}
+
+// Handler returns the HTTP handler serving the aggregated metrics.
+func (p *PrometheusReporter) Handler() http.Handler {
+	return promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{})
+}
+
+// ListenPrometheus creates a TCP listener to serve metrics on. Only loopback
+// addresses are accepted so metrics are never exposed off-machine.
+func ListenPrometheus(address string) (net.Listener, error) {
+	if err := checkLoopbackAddress(address); err != nil {
+		return nil, err
+	}
+	return net.Listen("tcp", address)
+}
+
+// Serve exposes the aggregated metrics on `/metrics` using the given listener
+// until the context is done.
+func (p *PrometheusReporter) Serve(ctx context.Context, listener net.Listener) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", p.Handler())
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	err := server.Serve(listener)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+func checkLoopbackAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid metrics address %q: %w", address, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("metrics address %q must be bound to localhost", address)
+}
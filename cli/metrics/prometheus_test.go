@@ -0,0 +1,54 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestPrometheusReporter(t *testing.T) {
+	reporter := NewPrometheusReporter()
+	reporter.Heartbeat(context.Background(), CommandUsage{Command: "compose up", Context: "moby", Source: "api", Status: SuccessStatus})
+	reporter.Heartbeat(context.Background(), CommandUsage{Command: "compose up", Context: "moby", Source: "api", Status: SuccessStatus})
+	reporter.Heartbeat(context.Background(), CommandUsage{Command: "compose up", Context: "moby", Source: "api", Status: FailureStatus})
+	reporter.Event(context.Background(), DockerCLIEvent{Command: "compose", Subcommand: "alpha-watch", DurationSecs: 0.2})
+
+	rec := httptest.NewRecorder()
+	reporter.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	assert.NilError(t, err)
+
+	assert.Assert(t, is.Contains(string(body), `docker_cli_commands_total{command="compose up",context="moby",source="api",status="success"} 2`))
+	assert.Assert(t, is.Contains(string(body), `docker_cli_commands_total{command="compose up",context="moby",source="api",status="failure"} 1`))
+	assert.Assert(t, is.Contains(string(body), `docker_cli_command_duration_seconds_count{command="compose",exit_code="0",subcommand="alpha-watch"} 1`))
+}
+
+func TestListenPrometheusLoopbackOnly(t *testing.T) {
+	for _, address := range []string{"0.0.0.0:9464", ":9464", "192.168.1.2:9464", "no-port"} {
+		_, err := ListenPrometheus(address)
+		assert.Assert(t, err != nil, address)
+	}
+	listener, err := ListenPrometheus("127.0.0.1:0")
+	assert.NilError(t, err)
+	assert.NilError(t, listener.Close())
+}
@@ -0,0 +1,208 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// EnvVarMetricsRateLimit is an optional environment variable enabling
+	// rate limiting, setting the number of heartbeats per second reported on
+	// average.
+	EnvVarMetricsRateLimit = "DOCKER_METRICS_RATE_LIMIT"
+
+	// DefaultRateLimit is the average number of heartbeats per second when
+	// DOCKER_METRICS_RATE_LIMIT isn't set, zero as heartbeats aren't rate
+	// limited unless asked to.
+	DefaultRateLimit = 0
+	// DefaultRateLimitBurst is the number of heartbeats that can be reported
+	// at once before being limited.
+	DefaultRateLimitBurst = 10
+
+	// maxSuppressed caps the number of distinct coalesced heartbeats kept.
+	maxSuppressed = 50
+
+	rateLimitFileName = "metrics-ratelimit.json"
+)
+
+// RateLimitingReporter limits heartbeats with a token bucket. Heartbeats over
+// the limit are coalesced per command and reported with their Count once the
+// limit allows it again.
+//
+// The bucket can be stored on disk, so that commands run in a loop from a
+// shell (each one being a new process) share it.
+type RateLimitingReporter struct {
+	reporter Reporter
+	rate     float64
+	burst    float64
+	path     string
+	now      func() time.Time
+
+	mu    *sync.Mutex
+	state *rateLimitState // used when not stored on disk
+}
+
+type rateLimitState struct {
+	Tokens     float64        `json:"tokens"`
+	Last       time.Time      `json:"last"`
+	Suppressed []CommandUsage `json:"suppressed,omitempty"`
+}
+
+// NewRateLimitingReporter creates a reporter forwarding on average rate
+// heartbeats per second, and bursts of up to burst heartbeats. The bucket is
+// stored in the file at the given path, or in memory if path is empty.
+func NewRateLimitingReporter(reporter Reporter, rate float64, burst int, path string) RateLimitingReporter {
+	return RateLimitingReporter{
+		reporter: reporter,
+		rate:     rate,
+		burst:    float64(burst),
+		path:     path,
+		now:      time.Now,
+		mu:       &sync.Mutex{},
+		state:    &rateLimitState{},
+	}
+}
+
+// RateLimit returns the heartbeats rate limit configured from the
+// environment. Invalid values are ignored and the DefaultRateLimit is used.
+func RateLimit() float64 {
+	v, ok := os.LookupEnv(EnvVarMetricsRateLimit)
+	if !ok {
+		return DefaultRateLimit
+	}
+	rate, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsNaN(rate) || math.IsInf(rate, 0) || rate < 0 {
+		return DefaultRateLimit
+	}
+	return rate
+}
+
+// Heartbeat reports a metric for aggregation.
+func (r RateLimitingReporter) Heartbeat(ctx context.Context, cmd CommandUsage) {
+	allowed, pending := r.take(cmd)
+	if !allowed {
+		return
+	}
+	for _, usage := range pending {
+		r.reporter.Heartbeat(ctx, usage)
+	}
+	r.reporter.Heartbeat(ctx, cmd)
+}
+
+// Event reports an analytics action.
+func (r RateLimitingReporter) Event(ctx context.Context, cmd DockerCLIEvent) {
+	r.reporter.Event(ctx, cmd)
+}
+
+// Flush delivers pending metric events of the wrapped reporter.
+func (r RateLimitingReporter) Flush(ctx context.Context) error {
+	return flush(ctx, r.reporter)
+}
+
+// Close closes the wrapped reporter.
+func (r RateLimitingReporter) Close() error {
+	return closeReporter(r.reporter)
+}
+
+// take consumes a token for the heartbeat, returning the coalesced
+// heartbeats to report along with it, or coalesces it if no token is left.
+func (r RateLimitingReporter) take(cmd CommandUsage) (bool, []CommandUsage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state := r.load()
+	now := r.now()
+	if state.Last.IsZero() {
+		state.Tokens = r.burst
+	} else if elapsed := now.Sub(state.Last).Seconds(); elapsed > 0 {
+		state.Tokens = math.Min(r.burst, state.Tokens+elapsed*r.rate)
+	}
+	state.Last = now
+
+	var pending []CommandUsage
+	allowed := state.Tokens >= 1
+	if allowed {
+		state.Tokens--
+		pending, state.Suppressed = state.Suppressed, nil
+	} else {
+		state.Suppressed = coalesce(state.Suppressed, cmd)
+	}
+	r.save(state)
+	return allowed, pending
+}
+
+func coalesce(suppressed []CommandUsage, cmd CommandUsage) []CommandUsage {
+	for i, usage := range suppressed {
+		if usage.Command == cmd.Command && usage.Context == cmd.Context && usage.Source == cmd.Source && usage.Status == cmd.Status {
+			cmd.Count = usage.count() + cmd.count()
+			suppressed[i] = cmd
+			return suppressed
+		}
+	}
+	if len(suppressed) >= maxSuppressed {
+		return suppressed
+	}
+	cmd.Count = cmd.count()
+	return append(suppressed, cmd)
+}
+
+func (r RateLimitingReporter) load() *rateLimitState {
+	if r.path == "" {
+		return r.state
+	}
+	state := &rateLimitState{}
+	if b, err := os.ReadFile(r.path); err == nil {
+		// a corrupted file just resets the bucket
+		_ = json.Unmarshal(b, state)
+	}
+	return state
+}
+
+func (r RateLimitingReporter) save(state *rateLimitState) {
+	if r.path == "" {
+		return
+	}
+	b, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(b)
+	if closeErr := tmp.Close(); err != nil || closeErr != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	_ = os.Rename(tmp.Name(), r.path)
+}
+
+func (c CommandUsage) count() int {
+	if c.Count == 0 {
+		return 1
+	}
+	return c.Count
+}
@@ -0,0 +1,66 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRateLimitingReporterCoalesces(t *testing.T) {
+	for _, path := range []string{"", filepath.Join(t.TempDir(), "ratelimit.json")} {
+		recorder := NewRecordingReporter()
+		now := time.Now()
+		newReporter := func() RateLimitingReporter {
+			r := NewRateLimitingReporter(recorder, 1, 2, path)
+			r.now = func() time.Time { return now }
+			return r
+		}
+		r := newReporter()
+		for i := 0; i < 5; i++ {
+			r.Heartbeat(context.Background(), CommandUsage{Command: "compose ps", Status: SuccessStatus})
+		}
+		r.Heartbeat(context.Background(), CommandUsage{Command: "compose up", Status: FailureStatus})
+		assert.Equal(t, len(recorder.Heartbeats()), 2, path)
+
+		if path != "" {
+			// another process shares the bucket
+			r = newReporter()
+		}
+		now = now.Add(time.Second)
+		r.Heartbeat(context.Background(), CommandUsage{Command: "compose ls"})
+		assert.DeepEqual(t, recorder.Heartbeats()[2:], []CommandUsage{
+			{Command: "compose ps", Status: SuccessStatus, Count: 3},
+			{Command: "compose up", Status: FailureStatus, Count: 1},
+			{Command: "compose ls"},
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	assert.Equal(t, RateLimit(), float64(0))
+	t.Setenv(EnvVarMetricsRateLimit, "0.5")
+	assert.Equal(t, RateLimit(), 0.5)
+	t.Setenv(EnvVarMetricsRateLimit, "-1")
+	assert.Equal(t, RateLimit(), float64(DefaultRateLimit))
+	t.Setenv(EnvVarMetricsRateLimit, "0")
+	assert.Equal(t, RateLimit(), float64(0))
+}
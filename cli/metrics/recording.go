@@ -0,0 +1,93 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"sync"
+)
+
+// RecordingReporter keeps the metric events it is given in memory, so that
+// tests can assert on the telemetry emitted by a command. It is safe for
+// concurrent use.
+type RecordingReporter struct {
+	mu         sync.Mutex
+	heartbeats []CommandUsage
+	events     []DockerCLIEvent
+}
+
+// NewRecordingReporter creates a new, empty, recording reporter.
+func NewRecordingReporter() *RecordingReporter {
+	return &RecordingReporter{}
+}
+
+// Heartbeat records a metric.
+func (r *RecordingReporter) Heartbeat(ctx context.Context, cmd CommandUsage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.heartbeats = append(r.heartbeats, cmd)
+}
+
+// Event records an analytics action.
+func (r *RecordingReporter) Event(ctx context.Context, cmd DockerCLIEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, cmd)
+}
+
+// Heartbeats returns a copy of the recorded heartbeats, in reporting order.
+func (r *RecordingReporter) Heartbeats() []CommandUsage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]CommandUsage(nil), r.heartbeats...)
+}
+
+// Events returns a copy of the recorded events, in reporting order.
+func (r *RecordingReporter) Events() []DockerCLIEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]DockerCLIEvent(nil), r.events...)
+}
+
+// Commands returns the commands of the recorded heartbeats.
+func (r *RecordingReporter) Commands() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	commands := make([]string, len(r.heartbeats))
+	for i, cmd := range r.heartbeats {
+		commands[i] = cmd.Command
+	}
+	return commands
+}
+
+// LastHeartbeat returns the most recently recorded heartbeat, if any.
+func (r *RecordingReporter) LastHeartbeat() (CommandUsage, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.heartbeats) == 0 {
+		return CommandUsage{}, false
+	}
+	return r.heartbeats[len(r.heartbeats)-1], true
+}
+
+// Reset discards all recorded heartbeats and events.
+func (r *RecordingReporter) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.heartbeats = nil
+	r.events = nil
+}
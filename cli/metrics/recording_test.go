@@ -0,0 +1,58 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRecordingReporter(t *testing.T) {
+	r := NewRecordingReporter()
+	_, ok := r.LastHeartbeat()
+	assert.Assert(t, !ok)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Heartbeat(context.Background(), CommandUsage{Command: "ps"})
+			r.Event(context.Background(), DockerCLIEvent{Command: "compose"})
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, len(r.Heartbeats()), 10)
+	assert.Equal(t, len(r.Events()), 10)
+
+	r.Heartbeat(context.Background(), CommandUsage{Command: "up"})
+	last, ok := r.LastHeartbeat()
+	assert.Assert(t, ok)
+	assert.Equal(t, last.Command, "up")
+	assert.Equal(t, r.Commands()[10], "up")
+
+	heartbeats := r.Heartbeats()
+	heartbeats[0].Command = "changed"
+	assert.Equal(t, r.Heartbeats()[0].Command, "ps")
+
+	r.Reset()
+	assert.Equal(t, len(r.Heartbeats()), 0)
+	assert.Equal(t, len(r.Events()), 0)
+}
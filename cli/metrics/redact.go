@@ -0,0 +1,129 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// tokens are separated by whitespace, and by `;` in sources
+	tokenPattern    = regexp.MustCompile(`[^\s;]+`)
+	hostnamePattern = regexp.MustCompile(`^[a-zA-Z0-9-]+(\.[a-zA-Z0-9-]+)+(:[0-9]+)?$`)
+)
+
+// RedactingReporter strips or hashes potentially sensitive values (file
+// paths, registry hostnames, flag values, project names) from metric events
+// before forwarding them to the wrapped reporter.
+type RedactingReporter struct {
+	reporter  Reporter
+	sensitive []string
+}
+
+// NewRedactingReporter creates a reporter redacting metric events before
+// forwarding them. Occurrences of the sensitive values, such as the project
+// name, are hashed in addition to the built-in rules.
+func NewRedactingReporter(reporter Reporter, sensitive ...string) RedactingReporter {
+	var values []string
+	for _, v := range sensitive {
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+	return RedactingReporter{
+		reporter:  reporter,
+		sensitive: values,
+	}
+}
+
+// Heartbeat reports a metric for aggregation.
+func (r RedactingReporter) Heartbeat(ctx context.Context, cmd CommandUsage) {
+	cmd.Command = r.redact(cmd.Command)
+	cmd.Context = r.redact(cmd.Context)
+	cmd.Source = r.redact(cmd.Source)
+	r.reporter.Heartbeat(ctx, cmd)
+}
+
+// Event reports an analytics action.
+func (r RedactingReporter) Event(ctx context.Context, cmd DockerCLIEvent) {
+	cmd.Command = r.redact(cmd.Command)
+	cmd.Subcommand = r.redact(cmd.Subcommand)
+	r.reporter.Event(ctx, cmd)
+}
+
+// Flush delivers pending metric events of the wrapped reporter.
+func (r RedactingReporter) Flush(ctx context.Context) error {
+	return flush(ctx, r.reporter)
+}
+
+// Close closes the wrapped reporter.
+func (r RedactingReporter) Close() error {
+	return closeReporter(r.reporter)
+}
+
+func (r RedactingReporter) redact(s string) string {
+	return tokenPattern.ReplaceAllStringFunc(s, r.redactToken)
+}
+
+func (r RedactingReporter) redactToken(token string) string {
+	if strings.HasPrefix(token, "-") {
+		// only keep the flag name
+		if i := strings.Index(token, "="); i >= 0 {
+			return token[:i]
+		}
+		return token
+	}
+	if isCommand(token) {
+		return token
+	}
+	for _, v := range r.sensitive {
+		if token == v {
+			return hashToken(token)
+		}
+	}
+	if isPath(token) || hostnamePattern.MatchString(token) {
+		return hashToken(token)
+	}
+	return token
+}
+
+func isPath(token string) bool {
+	return strings.ContainsAny(token, `/\`) || strings.HasPrefix(token, "~") || strings.HasPrefix(token, ".")
+}
+
+// hashToken returns a stable anonymized replacement for a sensitive value, so
+// that identical values can still be counted together.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "redacted-" + hex.EncodeToString(sum[:4])
+}
+
+// sensitiveValues returns values from the environment of the current
+// invocation that must never be reported as is.
+func sensitiveValues() []string {
+	values := []string{os.Getenv("COMPOSE_PROJECT_NAME")}
+	if wd, err := os.Getwd(); err == nil {
+		values = append(values, filepath.Base(wd))
+	}
+	return values
+}
@@ -0,0 +1,66 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type lastReporter struct {
+	usage CommandUsage
+	event DockerCLIEvent
+}
+
+func (r *lastReporter) Heartbeat(ctx context.Context, cmd CommandUsage) {
+	r.usage = cmd
+}
+
+func (r *lastReporter) Event(ctx context.Context, cmd DockerCLIEvent) {
+	r.event = cmd
+}
+
+func TestRedact(t *testing.T) {
+	r := NewRedactingReporter(nil, "myproject", "")
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{input: "compose up", expected: "compose up"},
+		{input: "--context=prod ps", expected: "--context ps"},
+		{input: "build /home/me/app", expected: "build " + hashToken("/home/me/app")},
+		{input: "push registry.corp.com:5000", expected: "push " + hashToken("registry.corp.com:5000")},
+		{input: "cli-buildx;docker-container", expected: "cli-buildx;docker-container"},
+		{input: "compose myproject", expected: "compose " + hashToken("myproject")},
+		{input: "", expected: ""},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, r.redact(testCase.input), testCase.expected, testCase.input)
+	}
+}
+
+func TestRedactingReporter(t *testing.T) {
+	last := &lastReporter{}
+	r := NewRedactingReporter(last, "build")
+	r.Heartbeat(context.Background(), CommandUsage{Command: "build", Context: "moby", Source: "cli-buildx;~/builder"})
+	assert.DeepEqual(t, last.usage, CommandUsage{Command: "build", Context: "moby", Source: "cli-buildx;" + hashToken("~/builder")})
+
+	r.Event(context.Background(), DockerCLIEvent{Command: "compose", Subcommand: "./up"})
+	assert.DeepEqual(t, last.event, DockerCLIEvent{Command: "compose", Subcommand: hashToken("./up")})
+}
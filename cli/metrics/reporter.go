@@ -18,10 +18,14 @@ package metrics
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type AnalyticsRequest struct {
@@ -30,7 +34,35 @@ type AnalyticsRequest struct {
 }
 
 // Reporter reports metric events generated by the client.
+//
+// Reporting should stop once the context is done, and trace context carried
+// by it (see WithTraceContext) is propagated where the transport allows it.
 type Reporter interface {
+	// Heartbeat reports a metric for aggregation.
+	Heartbeat(ctx context.Context, cmd CommandUsage)
+
+	// Event reports an analytics action.
+	Event(ctx context.Context, cmd DockerCLIEvent)
+}
+
+// FlushingReporter is a Reporter buffering metric events or holding resources,
+// such as connections or files. Reporters wrapping other reporters forward
+// both calls to them.
+type FlushingReporter interface {
+	Reporter
+
+	// Flush delivers pending metric events, or gives up once the context is
+	// done.
+	Flush(ctx context.Context) error
+
+	// Close releases the resources of the reporter. Events that were not
+	// flushed may be lost, and the reporter must not be used afterwards.
+	Close() error
+}
+
+// LegacyReporter is the context unaware reporter interface, kept for
+// reporters written before Reporter took a context.
+type LegacyReporter interface {
 	// Heartbeat reports a metric for aggregation.
 	Heartbeat(cmd CommandUsage)
 
@@ -38,69 +70,287 @@ type Reporter interface {
 	Event(cmd DockerCLIEvent)
 }
 
+// FromLegacy adapts a LegacyReporter to the Reporter interface; the context
+// is ignored.
+func FromLegacy(r LegacyReporter) Reporter {
+	return legacyReporter{reporter: r}
+}
+
+// ToLegacy adapts a Reporter to the LegacyReporter interface, reporting with
+// a background context bounded by Timeout.
+func ToLegacy(r Reporter) LegacyReporter {
+	return contextlessReporter{reporter: r}
+}
+
+type legacyReporter struct {
+	reporter LegacyReporter
+}
+
+func (l legacyReporter) Heartbeat(_ context.Context, cmd CommandUsage) {
+	l.reporter.Heartbeat(cmd)
+}
+
+func (l legacyReporter) Event(_ context.Context, cmd DockerCLIEvent) {
+	l.reporter.Event(cmd)
+}
+
+func (l legacyReporter) Flush(ctx context.Context) error {
+	if f, ok := l.reporter.(flusher); ok {
+		return f.Flush(ctx)
+	}
+	return nil
+}
+
+func (l legacyReporter) Close() error {
+	if c, ok := l.reporter.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+type contextlessReporter struct {
+	reporter Reporter
+}
+
+func (c contextlessReporter) Heartbeat(cmd CommandUsage) {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	c.reporter.Heartbeat(ctx, cmd)
+}
+
+func (c contextlessReporter) Event(cmd DockerCLIEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	c.reporter.Event(ctx, cmd)
+}
+
+func (c contextlessReporter) Flush(ctx context.Context) error {
+	return flush(ctx, c.reporter)
+}
+
+func (c contextlessReporter) Close() error {
+	return closeReporter(c.reporter)
+}
+
+// flusher is implemented by reporters that buffer metric events and need
+// to deliver them before the process exits.
+type flusher interface {
+	Flush(ctx context.Context) error
+}
+
+func flush(ctx context.Context, r Reporter) error {
+	if f, ok := r.(flusher); ok {
+		return f.Flush(ctx)
+	}
+	return nil
+}
+
+func closeReporter(r Reporter) error {
+	if c, ok := r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
 // HTTPReporter reports metric events to an HTTP endpoint.
+//
+// Failed posts are retried according to its RetryPolicy, and attempts stop for
+// the rest of the session once repeated posts have failed. Heartbeats that
+// can't be delivered are optionally spooled to disk, see WithSpool, and
+// optionally batched until flushed, see WithBatching.
 type HTTPReporter struct {
-	client *http.Client
+	client   *http.Client
+	endpoint string
+	retry    RetryPolicy
+	breaker  *circuitBreaker
+	schema   int
+	spool    *spool
+	format   PayloadFormat
+	batch    *batch
+}
+
+// HTTPReporterOption configures an HTTPReporter.
+type HTTPReporterOption func(*HTTPReporter)
+
+// WithEndpoint sets the base URL metric events are posted to, instead of the
+// Docker Desktop IPC endpoint.
+func WithEndpoint(endpoint string) HTTPReporterOption {
+	return func(l *HTTPReporter) {
+		l.endpoint = endpoint
+	}
+}
+
+// WithRetryPolicy sets how failed posts are retried, instead of the
+// DefaultRetryPolicy.
+func WithRetryPolicy(retry RetryPolicy) HTTPReporterOption {
+	return func(l *HTTPReporter) {
+		l.retry = retry
+	}
+}
+
+// WithSchemaVersion sets the usage payload schema version, instead of the
+// CurrentSchemaVersion.
+func WithSchemaVersion(version int) HTTPReporterOption {
+	return func(l *HTTPReporter) {
+		l.schema = version
+	}
+}
+
+// WithSpool keeps up to maxEntries heartbeats that couldn't be delivered in
+// the file at the given path, and replays them after the next successful post.
+func WithSpool(path string, maxEntries int) HTTPReporterOption {
+	return func(l *HTTPReporter) {
+		l.spool = newSpool(path, maxEntries)
+	}
+}
+
+// WithPayloadFormat sets how metric events are encoded, instead of
+// PayloadFormatJSON.
+func WithPayloadFormat(format PayloadFormat) HTTPReporterOption {
+	return func(l *HTTPReporter) {
+		l.format = format
+	}
 }
 
 // NewHTTPReporter creates a new reporter that will report metric events using
 // the provided HTTP client.
-func NewHTTPReporter(client *http.Client) HTTPReporter {
-	return HTTPReporter{client: client}
+func NewHTTPReporter(client *http.Client, opts ...HTTPReporterOption) HTTPReporter {
+	l := HTTPReporter{
+		client:   client,
+		endpoint: ipcEndpoint,
+		retry:    DefaultRetryPolicy,
+		schema:   CurrentSchemaVersion,
+		format:   PayloadFormatJSON,
+	}
+	for _, opt := range opts {
+		opt(&l)
+	}
+	l.breaker = &circuitBreaker{threshold: l.retry.BreakerThreshold}
+	return l
 }
 
 // Heartbeat reports a metric for aggregation.
-func (l HTTPReporter) Heartbeat(cmd CommandUsage) {
-	l.post("/usage", cmd)
+func (l HTTPReporter) Heartbeat(ctx context.Context, cmd CommandUsage) {
+	payload, err := ConvertCommandUsage(cmd, l.schema)
+	if err != nil {
+		return
+	}
+	if l.batch != nil {
+		l.addToBatch(ctx, wrapHeartbeat(l.format, cmd, payload))
+		return
+	}
+	l.post(ctx, "/usage", wrapHeartbeat(l.format, cmd, payload), l.spool)
 }
 
 // Event reports an analytics action.
-func (l HTTPReporter) Event(cmd DockerCLIEvent) {
+func (l HTTPReporter) Event(ctx context.Context, cmd DockerCLIEvent) {
 	event := AnalyticsRequest{
 		Event: "eventCliCommand",
 		Body:  cmd,
 	}
-	l.post("/analytics/track", event)
+	l.post(ctx, "/analytics/track", wrapEvent(l.format, event), nil)
 }
 
-func (l HTTPReporter) post(path string, body interface{}) {
+func (l HTTPReporter) post(ctx context.Context, path string, body interface{}, spool *spool) {
 	entry, err := json.Marshal(body)
 	if err != nil {
 		// we only pass known types that will marshal without error (no cycles)
 		return
 	}
 
-	u, err := url.JoinPath("http://ipc", path)
+	u, err := url.JoinPath(l.endpoint, path)
 	if err != nil {
 		// we only pass known values here, so this isn't possible in practice
 		return
 	}
 
-	resp, _ := l.client.Post(u, "application/json", bytes.NewReader(entry))
-	if resp != nil && resp.Body != nil {
-		_ = resp.Body.Close()
+	if !l.send(ctx, u, entry, l.headers()) {
+		if spool != nil {
+			_ = spool.push(entry)
+		}
+		return
+	}
+	if spool != nil {
+		_ = spool.replay(func(entry []byte) bool {
+			return l.send(ctx, u, entry, l.headers())
+		})
+	}
+}
+
+// Close closes the idle connections to the endpoint.
+func (l HTTPReporter) Close() error {
+	l.client.CloseIdleConnections()
+	return nil
+}
+
+func (l HTTPReporter) headers() map[string]string {
+	return map[string]string{"Content-Type": contentType(l.format)}
+}
+
+func (l HTTPReporter) send(ctx context.Context, u string, body []byte, headers map[string]string) bool {
+	if l.breaker.isOpen() {
+		return false
+	}
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+		if err != nil {
+			return false
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := l.client.Do(req)
+		if resp != nil && resp.Body != nil {
+			_ = resp.Body.Close()
+		}
+		if err == nil && resp.StatusCode < http.StatusInternalServerError {
+			l.breaker.success()
+			return true
+		}
+		if ctx.Err() != nil {
+			// the caller gave up, this isn't a failure of the endpoint
+			return false
+		}
+		if attempt >= l.retry.MaxAttempts {
+			l.breaker.failure()
+			return false
+		}
+		select {
+		case <-time.After(l.retry.backoff(attempt)):
+		case <-ctx.Done():
+			return false
+		}
 	}
 }
 
 // WriterReporter reports metrics as JSON lines to the provided writer.
 type WriterReporter struct {
-	w io.Writer
+	w      io.Writer
+	format PayloadFormat
 }
 
 // NewWriterReporter creates a new reporter that will write metrics to the
 // provided writer as JSON lines.
 func NewWriterReporter(w io.Writer) WriterReporter {
-	return WriterReporter{w: w}
+	return WriterReporter{w: w, format: PayloadFormatJSON}
+}
+
+// WithPayloadFormat returns a copy of the reporter encoding metric events in
+// the given format.
+func (w WriterReporter) WithPayloadFormat(format PayloadFormat) WriterReporter {
+	w.format = format
+	return w
 }
 
 // Heartbeat reports a metric for aggregation.
-func (w WriterReporter) Heartbeat(cmd CommandUsage) {
-	w.write(cmd)
+func (w WriterReporter) Heartbeat(ctx context.Context, cmd CommandUsage) {
+	payload, _ := ConvertCommandUsage(cmd, CurrentSchemaVersion)
+	w.write(wrapHeartbeat(w.format, cmd, payload))
 }
 
 // Event reports an analytics action.
-func (w WriterReporter) Event(cmd DockerCLIEvent) {
-	w.write(cmd)
+func (w WriterReporter) Event(ctx context.Context, cmd DockerCLIEvent) {
+	w.write(wrapEvent(w.format, cmd))
 }
 
 func (w WriterReporter) write(v interface{}) {
@@ -115,26 +365,158 @@ func (w WriterReporter) write(v interface{}) {
 
 // MuxReporter wraps multiple reporter instances and reports metrics to each
 // instance on invocation.
+//
+// Each reporter is isolated from the others: a reporter that panics or
+// doesn't return within the timeout is counted as failed and skipped.
 type MuxReporter struct {
 	reporters []Reporter
+	timeout   time.Duration
+	parallel  bool
+	failures  []uint64
+}
+
+// MuxReporterOption configures a MuxReporter.
+type MuxReporterOption func(*MuxReporter)
+
+// WithReporterTimeout sets how long each wrapped reporter is waited for,
+// instead of Timeout. A zero timeout waits indefinitely.
+func WithReporterTimeout(timeout time.Duration) MuxReporterOption {
+	return func(m *MuxReporter) {
+		m.timeout = timeout
+	}
+}
+
+// WithParallel calls the wrapped reporters concurrently instead of one after
+// the other.
+func WithParallel() MuxReporterOption {
+	return func(m *MuxReporter) {
+		m.parallel = true
+	}
 }
 
 // NewMuxReporter creates a reporter that will report metrics to each of the
 // provided reporter instances.
 func NewMuxReporter(reporters ...Reporter) MuxReporter {
-	return MuxReporter{reporters: reporters}
+	return MuxReporter{
+		reporters: reporters,
+		timeout:   Timeout,
+		failures:  make([]uint64, len(reporters)),
+	}
+}
+
+// With returns a copy of the reporter configured with the given options,
+// sharing its failure counts.
+func (m MuxReporter) With(opts ...MuxReporterOption) MuxReporter {
+	for _, opt := range opts {
+		opt(&m)
+	}
+	return m
 }
 
 // Heartbeat reports a metric for aggregation.
-func (m MuxReporter) Heartbeat(cmd CommandUsage) {
+func (m MuxReporter) Heartbeat(ctx context.Context, cmd CommandUsage) {
+	m.each(ctx, func(ctx context.Context, r Reporter) {
+		r.Heartbeat(ctx, cmd)
+	})
+}
+
+// Event reports an analytics action.
+func (m MuxReporter) Event(ctx context.Context, cmd DockerCLIEvent) {
+	m.each(ctx, func(ctx context.Context, r Reporter) {
+		r.Event(ctx, cmd)
+	})
+}
+
+// Flush delivers pending metric events of the wrapped reporters.
+func (m MuxReporter) Flush(ctx context.Context) error {
+	var err error
 	for i := range m.reporters {
-		m.reporters[i].Heartbeat(cmd)
+		if e := flush(ctx, m.reporters[i]); e != nil && err == nil {
+			err = e
+		}
 	}
+	return err
 }
 
-// Event reports an analytics action.
-func (m MuxReporter) Event(cmd DockerCLIEvent) {
+// Close closes the wrapped reporters.
+func (m MuxReporter) Close() error {
+	var err error
+	for i := range m.reporters {
+		if e := closeReporter(m.reporters[i]); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Failures returns, for each wrapped reporter in order, how many calls
+// panicked or timed out.
+func (m MuxReporter) Failures() []uint64 {
+	failures := make([]uint64, len(m.failures))
+	for i := range m.failures {
+		failures[i] = atomic.LoadUint64(&m.failures[i])
+	}
+	return failures
+}
+
+func (m MuxReporter) each(ctx context.Context, fn func(context.Context, Reporter)) {
+	if !m.parallel {
+		for i := range m.reporters {
+			m.call(ctx, i, fn)
+		}
+		return
+	}
+	var wg sync.WaitGroup
 	for i := range m.reporters {
-		m.reporters[i].Event(cmd)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			m.call(ctx, i, fn)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func (m MuxReporter) call(ctx context.Context, i int, fn func(context.Context, Reporter)) {
+	if m.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+	}
+	done := make(chan bool, 1)
+	run := func() {
+		defer func() {
+			if recover() != nil {
+				done <- false
+			}
+		}()
+		fn(ctx, m.reporters[i])
+		done <- true
+	}
+	if m.timeout <= 0 {
+		run()
+	} else {
+		go run()
+	}
+
+	var timeout <-chan time.Time
+	if m.timeout > 0 {
+		timer := time.NewTimer(m.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case ok := <-done:
+		if !ok {
+			m.fail(i)
+		}
+	case <-timeout:
+		m.fail(i)
+	}
+}
+
+func (m MuxReporter) fail(i int) {
+	if i < len(m.failures) {
+		atomic.AddUint64(&m.failures[i], 1)
 	}
 }
@@ -0,0 +1,88 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// RetryPolicy configures how failed metric posts are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts for a single post,
+	// including the first one.
+	MaxAttempts int
+	// InitialBackoff is the upper bound of the wait before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the exponentially growing wait between retries.
+	MaxBackoff time.Duration
+	// BreakerThreshold is the number of consecutive failed posts after which
+	// no more attempts are made for the rest of the session. Zero disables
+	// the circuit breaker.
+	BreakerThreshold int
+}
+
+// DefaultRetryPolicy keeps retries within the metrics Timeout, so that
+// a transient daemon restart doesn't lose usage data.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:      3,
+	InitialBackoff:   5 * time.Millisecond,
+	MaxBackoff:       20 * time.Millisecond,
+	BreakerThreshold: 3,
+}
+
+// backoff returns a random wait, up to an exponentially growing bound, before
+// the given retry attempt (starting at 1).
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	bound := p.InitialBackoff
+	for i := 1; i < retry && bound < p.MaxBackoff; i++ {
+		bound *= 2
+	}
+	if p.MaxBackoff > 0 && bound > p.MaxBackoff {
+		bound = p.MaxBackoff
+	}
+	if bound <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(bound)))
+}
+
+// circuitBreaker stops attempts after repeated consecutive failures.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	failures  int
+	threshold int
+}
+
+func (c *circuitBreaker) isOpen() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.threshold > 0 && c.failures >= c.threshold
+}
+
+func (c *circuitBreaker) success() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures = 0
+}
+
+func (c *circuitBreaker) failure() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures++
+}
@@ -0,0 +1,102 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+// newTestHTTPClient returns a client sending all requests to the test server.
+func newTestHTTPClient(server *httptest.Server) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "tcp", server.Listener.Addr().String())
+			},
+		},
+	}
+}
+
+func TestHTTPReporterRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	reporter := NewHTTPReporter(newTestHTTPClient(server), WithRetryPolicy(RetryPolicy{
+		MaxAttempts:      3,
+		InitialBackoff:   time.Millisecond,
+		MaxBackoff:       2 * time.Millisecond,
+		BreakerThreshold: 1,
+	}))
+	reporter.Heartbeat(context.Background(), CommandUsage{Command: "up"})
+	assert.Equal(t, atomic.LoadInt32(&calls), int32(3))
+	assert.Assert(t, !reporter.breaker.isOpen())
+}
+
+func TestHTTPReporterCircuitBreaker(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	reporter := NewHTTPReporter(newTestHTTPClient(server), WithRetryPolicy(RetryPolicy{
+		MaxAttempts:      2,
+		BreakerThreshold: 2,
+	}))
+	for i := 0; i < 5; i++ {
+		reporter.Heartbeat(context.Background(), CommandUsage{Command: "up"})
+	}
+	// 2 posts of 2 attempts each, then the breaker is open
+	assert.Equal(t, atomic.LoadInt32(&calls), int32(4))
+	assert.Assert(t, reporter.breaker.isOpen())
+}
+
+func TestHTTPReporterDoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	NewHTTPReporter(newTestHTTPClient(server)).Heartbeat(context.Background(), CommandUsage{Command: "up"})
+	assert.Equal(t, atomic.LoadInt32(&calls), int32(1))
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 40 * time.Millisecond}
+	for i := 0; i < 100; i++ {
+		assert.Assert(t, policy.backoff(1) < 10*time.Millisecond)
+		assert.Assert(t, policy.backoff(2) < 20*time.Millisecond)
+		assert.Assert(t, policy.backoff(10) < 40*time.Millisecond)
+	}
+	assert.Equal(t, RetryPolicy{}.backoff(3), time.Duration(0))
+}
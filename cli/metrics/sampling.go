@@ -0,0 +1,121 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"os"
+	"strconv"
+)
+
+const (
+	// EnvVarSampleRate is an optional environment variable setting the
+	// fraction (between 0 and 1) of heartbeats that are reported.
+	EnvVarSampleRate = "DOCKER_METRICS_SAMPLE_RATE"
+	// EnvVarSessionID is an optional environment variable identifying a
+	// session spanning multiple CLI invocations. Sampling decisions are keyed
+	// on it so that all commands of a session are either reported or not.
+	EnvVarSessionID = "DOCKER_METRICS_SESSION_ID"
+)
+
+// SessionID returns the session ID inherited from the environment, or a new
+// random one scoped to the current process.
+func SessionID() string {
+	if v := os.Getenv(EnvVarSessionID); v != "" {
+		return v
+	}
+	return processSessionID
+}
+
+var processSessionID = newRandomID()
+
+func newRandomID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// SampleRate returns the sample rate configured from the environment. Invalid
+// or out of range values are ignored and everything is reported.
+func SampleRate() float64 {
+	v, ok := os.LookupEnv(EnvVarSampleRate)
+	if !ok {
+		return 1
+	}
+	rate, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsNaN(rate) || rate < 0 || rate > 1 {
+		return 1
+	}
+	return rate
+}
+
+// SamplingReporter only forwards heartbeats to the wrapped reporter for a
+// fraction of sessions.
+type SamplingReporter struct {
+	reporter Reporter
+	sampled  bool
+}
+
+// NewSamplingReporter creates a reporter that forwards heartbeats only if the
+// session is part of the sample. The decision is deterministic for a given
+// session ID and rate.
+func NewSamplingReporter(reporter Reporter, rate float64, sessionID string) SamplingReporter {
+	return SamplingReporter{
+		reporter: reporter,
+		sampled:  isSampled(rate, sessionID),
+	}
+}
+
+func isSampled(rate float64, sessionID string) bool {
+	if rate >= 1 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+	sum := sha256.Sum256([]byte(sessionID))
+	return float64(binary.BigEndian.Uint64(sum[:8]))/math.MaxUint64 < rate
+}
+
+// Heartbeat reports a metric for aggregation.
+func (s SamplingReporter) Heartbeat(ctx context.Context, cmd CommandUsage) {
+	if s.sampled {
+		s.reporter.Heartbeat(ctx, cmd)
+	}
+}
+
+// Event reports an analytics action.
+func (s SamplingReporter) Event(ctx context.Context, cmd DockerCLIEvent) {
+	s.reporter.Event(ctx, cmd)
+}
+
+// Flush delivers pending metric events of the wrapped reporter.
+func (s SamplingReporter) Flush(ctx context.Context) error {
+	return flush(ctx, s.reporter)
+}
+
+// Close closes the wrapped reporter.
+func (s SamplingReporter) Close() error {
+	return closeReporter(s.reporter)
+}
@@ -0,0 +1,101 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type countingReporter struct {
+	heartbeats int
+	events     int
+}
+
+func (c *countingReporter) Heartbeat(context.Context, CommandUsage) { c.heartbeats++ }
+
+func (c *countingReporter) Event(context.Context, DockerCLIEvent) { c.events++ }
+
+func TestSamplingReporterIsDeterministic(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		sessionID := fmt.Sprintf("session-%d", i)
+		first := &countingReporter{}
+		second := &countingReporter{}
+		NewSamplingReporter(first, 0.5, sessionID).Heartbeat(context.Background(), CommandUsage{})
+		NewSamplingReporter(second, 0.5, sessionID).Heartbeat(context.Background(), CommandUsage{})
+		assert.Equal(t, first.heartbeats, second.heartbeats, sessionID)
+	}
+}
+
+func TestSamplingReporterRate(t *testing.T) {
+	sampled := 0
+	for i := 0; i < 1000; i++ {
+		if isSampled(0.1, fmt.Sprintf("session-%d", i)) {
+			sampled++
+		}
+	}
+	assert.Assert(t, sampled > 50 && sampled < 150, "sampled %d out of 1000", sampled)
+
+	r := &countingReporter{}
+	NewSamplingReporter(r, 0, "abc").Heartbeat(context.Background(), CommandUsage{})
+	NewSamplingReporter(r, 0, "abc").Event(context.Background(), DockerCLIEvent{})
+	NewSamplingReporter(r, 1, "abc").Heartbeat(context.Background(), CommandUsage{})
+	assert.Equal(t, r.heartbeats, 1)
+	assert.Equal(t, r.events, 1)
+}
+
+func TestSampleRate(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected float64
+	}{
+		{value: "0.25", expected: 0.25},
+		{value: "0", expected: 0},
+		{value: "1.5", expected: 1},
+		{value: "-1", expected: 1},
+		{value: "NaN", expected: 1},
+		{value: "abc", expected: 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			t.Setenv(EnvVarSampleRate, tc.value)
+			assert.Equal(t, SampleRate(), tc.expected)
+		})
+	}
+}
+
+func TestSessionID(t *testing.T) {
+	assert.Equal(t, SessionID(), processSessionID)
+	t.Setenv(EnvVarSessionID, "shell-session")
+	assert.Equal(t, SessionID(), "shell-session")
+}
+
+func TestExportSessionID(t *testing.T) {
+	t.Setenv(EnvVarSessionID, "")
+	ExportSessionID()
+	assert.Equal(t, os.Getenv(EnvVarSessionID), processSessionID)
+	assert.Equal(t, SessionID(), processSessionID)
+
+	t.Setenv(EnvVarSessionID, "inherited")
+	ExportSessionID()
+	assert.Equal(t, SessionID(), "inherited")
+	assert.Assert(t, InvocationID() != "")
+}
@@ -0,0 +1,104 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+const (
+	// SchemaV1 is the original usage payload: command, context, source and
+	// status, without a schema version field.
+	SchemaV1 = 1
+	// SchemaV2 adds the schema version, exit code, duration, backend version,
+	// cancellation, error category, count, session, invocation and machine
+	// ID fields.
+	SchemaV2 = 2
+
+	// CurrentSchemaVersion is the usage payload version sent by default.
+	CurrentSchemaVersion = SchemaV2
+)
+
+// EnvVarMetricsSchemaVersion is an optional environment variable setting the
+// usage payload version posted to the metrics endpoint, for collectors that
+// can't decode the current one.
+const EnvVarMetricsSchemaVersion = "DOCKER_METRICS_SCHEMA_VERSION"
+
+// commandUsageV1 is the usage payload decoded by legacy collectors.
+type commandUsageV1 struct {
+	Command string `json:"command"`
+	Context string `json:"context"`
+	Source  string `json:"source"`
+	Status  string `json:"status"`
+}
+
+// versionedCommandUsage is the usage payload with its schema version.
+type versionedCommandUsage struct {
+	SchemaVersion int `json:"schema_version"`
+	CommandUsage
+}
+
+// ConvertCommandUsage returns the serializable usage payload for the given
+// schema version, dropping the fields that version doesn't know about.
+func ConvertCommandUsage(cmd CommandUsage, version int) (interface{}, error) {
+	switch version {
+	case SchemaV1:
+		return commandUsageV1{
+			Command: cmd.Command,
+			Context: cmd.Context,
+			Source:  cmd.Source,
+			Status:  cmd.Status,
+		}, nil
+	case SchemaV2:
+		return versionedCommandUsage{
+			SchemaVersion: SchemaV2,
+			CommandUsage:  cmd,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported metrics schema version %d", version)
+	}
+}
+
+// DecodeCommandUsage decodes a usage payload of any schema version and
+// returns it along with its version. Payloads without a version are SchemaV1.
+func DecodeCommandUsage(data []byte) (CommandUsage, int, error) {
+	var v versionedCommandUsage
+	if err := json.Unmarshal(data, &v); err != nil {
+		return CommandUsage{}, 0, err
+	}
+	if v.SchemaVersion == 0 {
+		v.SchemaVersion = SchemaV1
+	}
+	return v.CommandUsage, v.SchemaVersion, nil
+}
+
+// schemaVersion returns the usage payload version configured from the
+// environment.
+func schemaVersion() (int, error) {
+	v := os.Getenv(EnvVarMetricsSchemaVersion)
+	if v == "" {
+		return CurrentSchemaVersion, nil
+	}
+	version, err := strconv.Atoi(v)
+	if err != nil || version < SchemaV1 || version > CurrentSchemaVersion {
+		return 0, fmt.Errorf("invalid %s value %q", EnvVarMetricsSchemaVersion, v)
+	}
+	return version, nil
+}
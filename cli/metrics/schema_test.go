@@ -0,0 +1,103 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+var testUsage = CommandUsage{
+	Command:       "compose up",
+	Context:       "moby",
+	Source:        "cli",
+	Status:        FailureStatus,
+	ExitCode:      1,
+	DurationSecs:  1.5,
+	ErrorCategory: UnknownErrorCategory,
+}
+
+func TestConvertCommandUsage(t *testing.T) {
+	payload, err := ConvertCommandUsage(testUsage, SchemaV1)
+	assert.NilError(t, err)
+	b, err := json.Marshal(payload)
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), `{"command":"compose up","context":"moby","source":"cli","status":"failure"}`)
+
+	payload, err = ConvertCommandUsage(testUsage, SchemaV2)
+	assert.NilError(t, err)
+	b, err = json.Marshal(payload)
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), `{"schema_version":2,"command":"compose up","context":"moby","source":"cli","status":"failure",`+
+		`"exit_code":1,"duration_secs":1.5,"error_category":"unknown"}`)
+
+	_, err = ConvertCommandUsage(testUsage, 42)
+	assert.ErrorContains(t, err, "unsupported metrics schema version 42")
+}
+
+func TestDecodeCommandUsage(t *testing.T) {
+	for _, version := range []int{SchemaV1, SchemaV2} {
+		payload, err := ConvertCommandUsage(testUsage, version)
+		assert.NilError(t, err)
+		b, err := json.Marshal(payload)
+		assert.NilError(t, err)
+
+		usage, decoded, err := DecodeCommandUsage(b)
+		assert.NilError(t, err)
+		assert.Equal(t, decoded, version)
+		assert.Equal(t, usage.Command, testUsage.Command)
+		assert.Equal(t, usage.Status, testUsage.Status)
+		if version == SchemaV2 {
+			assert.DeepEqual(t, usage, testUsage)
+		}
+	}
+}
+
+func TestHTTPReporterSchemaVersion(t *testing.T) {
+	bodies := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies <- string(b)
+	}))
+	defer server.Close()
+
+	reporter := NewHTTPReporter(newTestHTTPClient(server), WithSchemaVersion(SchemaV1))
+	reporter.Heartbeat(context.Background(), testUsage)
+	assert.Equal(t, <-bodies, `{"command":"compose up","context":"moby","source":"cli","status":"failure"}`)
+}
+
+func TestSchemaVersionFromEnv(t *testing.T) {
+	t.Setenv(EnvVarMetricsSchemaVersion, "")
+	v, err := schemaVersion()
+	assert.NilError(t, err)
+	assert.Equal(t, v, CurrentSchemaVersion)
+
+	t.Setenv(EnvVarMetricsSchemaVersion, "1")
+	v, err = schemaVersion()
+	assert.NilError(t, err)
+	assert.Equal(t, v, SchemaV1)
+
+	t.Setenv(EnvVarMetricsSchemaVersion, "3")
+	_, err = schemaVersion()
+	assert.ErrorContains(t, err, "invalid DOCKER_METRICS_SCHEMA_VERSION value")
+}
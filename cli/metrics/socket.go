@@ -0,0 +1,87 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"time"
+)
+
+// SocketReporter reports metric events as JSON lines written directly to a
+// local socket (a unix domain socket, or a named pipe on Windows), without
+// the HTTP framing.
+//
+// Each line is an AnalyticsRequest, with the `usage` event for heartbeats.
+type SocketReporter struct {
+	dial func() (net.Conn, error)
+}
+
+// NewSocketReporter creates a new reporter that will write metric events to
+// the Docker CLI socket used by Docker Desktop.
+func NewSocketReporter() SocketReporter {
+	return NewSocketReporterWithDialer(conn)
+}
+
+// NewSocketReporterWithDialer creates a new reporter that will write metric
+// events to connections opened with the provided dial function.
+func NewSocketReporterWithDialer(dial func() (net.Conn, error)) SocketReporter {
+	return SocketReporter{dial: dial}
+}
+
+// Heartbeat reports a metric for aggregation.
+func (s SocketReporter) Heartbeat(ctx context.Context, cmd CommandUsage) {
+	payload, _ := ConvertCommandUsage(cmd, CurrentSchemaVersion)
+	s.write(ctx, AnalyticsRequest{
+		Event: "usage",
+		Body:  payload,
+	})
+}
+
+// Event reports an analytics action.
+func (s SocketReporter) Event(ctx context.Context, cmd DockerCLIEvent) {
+	s.write(ctx, AnalyticsRequest{
+		Event: "eventCliCommand",
+		Body:  cmd,
+	})
+}
+
+func (s SocketReporter) write(ctx context.Context, v interface{}) {
+	if ctx.Err() != nil {
+		return
+	}
+	entry, err := json.Marshal(v)
+	if err != nil {
+		// we only pass known types that will marshal without error (no cycles)
+		return
+	}
+	entry = append(entry, '\n')
+
+	c, err := s.dial()
+	if err != nil {
+		return
+	}
+	// nolint errcheck
+	defer c.Close()
+	deadline := time.Now().Add(Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = c.SetWriteDeadline(deadline)
+	_, _ = c.Write(entry)
+}
@@ -0,0 +1,54 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSocketReporter(t *testing.T) {
+	lines := make(chan string, 2)
+	reporter := NewSocketReporterWithDialer(func() (net.Conn, error) {
+		client, server := net.Pipe()
+		go func() {
+			line, _ := bufio.NewReader(server).ReadString('\n')
+			lines <- line
+			_ = server.Close()
+		}()
+		return client, nil
+	})
+
+	reporter.Heartbeat(context.Background(), CommandUsage{Command: "compose up", Context: "moby", Source: "cli", Status: SuccessStatus})
+	assert.Equal(t, <-lines, `{"event":"usage","body":{"schema_version":2,"command":"compose up","context":"moby","source":"cli","status":"success"}}`+"\n")
+
+	reporter.Event(context.Background(), DockerCLIEvent{Command: "compose", Subcommand: "alpha-watch"})
+	assert.Equal(t, <-lines, `{"event":"eventCliCommand","body":{"command":"compose","subcommand":"alpha-watch","exit_code":0,"start_time":"0001-01-01T00:00:00Z"}}`+"\n")
+}
+
+func TestSocketReporterUnreachable(t *testing.T) {
+	reporter := NewSocketReporterWithDialer(func() (net.Conn, error) {
+		return nil, errors.New("connection refused")
+	})
+	// must not panic nor block
+	reporter.Heartbeat(context.Background(), CommandUsage{Command: "compose up"})
+}
@@ -0,0 +1,137 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// DefaultSpoolMaxEntries is the number of heartbeats kept on disk while the
+	// metrics endpoint is unreachable; the oldest ones are dropped first.
+	DefaultSpoolMaxEntries = 100
+
+	spoolFileName = "metrics-spool.jsonl"
+)
+
+// spool is a capped on-disk queue of serialized heartbeats, one per line,
+// replayed once the endpoint is reachable again.
+type spool struct {
+	path       string
+	maxEntries int
+	mu         sync.Mutex
+}
+
+func newSpool(path string, maxEntries int) *spool {
+	return &spool{
+		path:       path,
+		maxEntries: maxEntries,
+	}
+}
+
+// push appends an entry, dropping the oldest ones past the cap.
+func (s *spool) push(entry []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := readSpoolFile(s.path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	entries = append(entries, entry)
+	if len(entries) > s.maxEntries {
+		entries = entries[len(entries)-s.maxEntries:]
+	}
+	return s.write(entries)
+}
+
+// replay sends the spooled entries in order until one fails; the remaining
+// ones are kept for later.
+func (s *spool) replay(send func(entry []byte) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// take the spool so that concurrent CLI processes don't replay it twice
+	taken := fmt.Sprintf("%s.%d", s.path, os.Getpid())
+	if err := os.Rename(s.path, taken); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	entries, err := readSpoolFile(taken)
+	_ = os.Remove(taken)
+	if err != nil {
+		return err
+	}
+	for i, entry := range entries {
+		if !send(entry) {
+			pending, err := readSpoolFile(s.path)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			entries = append(entries[i:], pending...)
+			if len(entries) > s.maxEntries {
+				entries = entries[len(entries)-s.maxEntries:]
+			}
+			return s.write(entries)
+		}
+	}
+	return nil
+}
+
+func (s *spool) write(entries [][]byte) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if _, err := tmp.Write(append(entry, '\n')); err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+			return err
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func readSpoolFile(path string) ([][]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		if line := scanner.Bytes(); len(line) > 0 {
+			entries = append(entries, append([]byte(nil), line...))
+		}
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,101 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSpoolCap(t *testing.T) {
+	s := newSpool(filepath.Join(t.TempDir(), "spool.jsonl"), 2)
+	for _, entry := range []string{"1", "2", "3"} {
+		assert.NilError(t, s.push([]byte(entry)))
+	}
+
+	var replayed []string
+	assert.NilError(t, s.replay(func(entry []byte) bool {
+		replayed = append(replayed, string(entry))
+		return true
+	}))
+	assert.DeepEqual(t, replayed, []string{"2", "3"})
+	_, err := os.Stat(s.path)
+	assert.Assert(t, os.IsNotExist(err))
+}
+
+func TestSpoolReplayKeepsUndelivered(t *testing.T) {
+	s := newSpool(filepath.Join(t.TempDir(), "spool.jsonl"), 10)
+	for _, entry := range []string{"1", "2", "3"} {
+		assert.NilError(t, s.push([]byte(entry)))
+	}
+	assert.NilError(t, s.replay(func(entry []byte) bool {
+		return string(entry) == "1"
+	}))
+	entries, err := readSpoolFile(s.path)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, entries, [][]byte{[]byte("2"), []byte("3")})
+}
+
+func TestHTTPReporterSpool(t *testing.T) {
+	var (
+		online int32
+		mu     sync.Mutex
+		bodies []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&online) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		bodies = append(bodies, string(b))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "spool.jsonl")
+	newReporter := func() HTTPReporter {
+		return NewHTTPReporter(newTestHTTPClient(server),
+			WithRetryPolicy(RetryPolicy{MaxAttempts: 1, BreakerThreshold: 5}),
+			WithSchemaVersion(SchemaV1),
+			WithSpool(path, 10),
+		)
+	}
+
+	offline := newReporter()
+	offline.Heartbeat(context.Background(), CommandUsage{Command: "build"})
+	offline.Heartbeat(context.Background(), CommandUsage{Command: "up"})
+	assert.Equal(t, len(bodies), 0)
+
+	atomic.StoreInt32(&online, 1)
+	newReporter().Heartbeat(context.Background(), CommandUsage{Command: "ps"})
+	assert.DeepEqual(t, bodies, []string{
+		`{"command":"ps","context":"","source":"","status":""}`,
+		`{"command":"build","context":"","source":"","status":""}`,
+		`{"command":"up","context":"","source":"","status":""}`,
+	})
+}
@@ -0,0 +1,126 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// EnvVarStatsDAddress is an optional environment variable setting the
+	// address (host:port) of a StatsD agent heartbeats are also sent to.
+	EnvVarStatsDAddress = "DOCKER_METRICS_STATSD_ADDR"
+	// EnvVarStatsDFormat is an optional environment variable selecting how
+	// tags are sent to the StatsD agent: `dogstatsd` (the default) or
+	// `influx`, as understood by Telegraf.
+	EnvVarStatsDFormat = "DOCKER_METRICS_STATSD_FORMAT"
+
+	// StatsDFormatDogStatsD appends tags as `|#key:value,...`.
+	StatsDFormatDogStatsD = "dogstatsd"
+	// StatsDFormatInflux appends tags to the metric name as `,key=value,...`.
+	StatsDFormatInflux = "influx"
+
+	statsDCountMetric    = "docker.cli.command.count"
+	statsDDurationMetric = "docker.cli.command.duration"
+)
+
+var statsDTagReplacer = strings.NewReplacer(" ", "_", ":", "_", "|", "_", ",", "_", "#", "_", "@", "_", "=", "_")
+
+// StatsDReporter reports heartbeats as StatsD counter and timing metrics,
+// tagged with the command, backend and status. Events are not reported.
+type StatsDReporter struct {
+	conn   net.Conn
+	format string
+}
+
+// NewStatsDReporter creates a new reporter sending metrics over UDP to the
+// StatsD agent at the given address, using the given tags format.
+func NewStatsDReporter(address string, format string) (StatsDReporter, error) {
+	switch format {
+	case "":
+		format = StatsDFormatDogStatsD
+	case StatsDFormatDogStatsD, StatsDFormatInflux:
+	default:
+		return StatsDReporter{}, fmt.Errorf("unsupported StatsD format %q", format)
+	}
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return StatsDReporter{}, err
+	}
+	return StatsDReporter{
+		conn:   conn,
+		format: format,
+	}, nil
+}
+
+// Heartbeat reports a metric for aggregation.
+func (s StatsDReporter) Heartbeat(ctx context.Context, cmd CommandUsage) {
+	tags := [][2]string{
+		{"command", cmd.Command},
+		{"backend", cmd.Context},
+		{"status", cmd.Status},
+	}
+	lines := []string{s.line(statsDCountMetric, strconv.Itoa(cmd.count()), "c", tags)}
+	if cmd.DurationSecs > 0 {
+		ms := strconv.FormatFloat(cmd.DurationSecs*1000, 'f', -1, 64)
+		lines = append(lines, s.line(statsDDurationMetric, ms, "ms", tags))
+	}
+	_ = s.conn.SetWriteDeadline(time.Now().Add(Timeout))
+	// a single datagram, agents split it on new lines
+	_, _ = s.conn.Write([]byte(strings.Join(lines, "\n")))
+}
+
+// Event reports an analytics action.
+func (s StatsDReporter) Event(context.Context, DockerCLIEvent) {}
+
+// Close closes the connection to the agent.
+func (s StatsDReporter) Close() error {
+	return s.conn.Close()
+}
+
+func (s StatsDReporter) line(name string, value string, kind string, tags [][2]string) string {
+	var b strings.Builder
+	b.WriteString(name)
+	if s.format == StatsDFormatInflux {
+		for _, tag := range tags {
+			fmt.Fprintf(&b, ",%s=%s", tag[0], statsDTagValue(tag[1]))
+		}
+	}
+	fmt.Fprintf(&b, ":%s|%s", value, kind)
+	if s.format == StatsDFormatDogStatsD {
+		for i, tag := range tags {
+			sep := ","
+			if i == 0 {
+				sep = "|#"
+			}
+			fmt.Fprintf(&b, "%s%s:%s", sep, tag[0], statsDTagValue(tag[1]))
+		}
+	}
+	return b.String()
+}
+
+func statsDTagValue(v string) string {
+	if v == "" {
+		return "none"
+	}
+	return statsDTagReplacer.Replace(v)
+}
@@ -0,0 +1,67 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestStatsDReporter(t *testing.T) {
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format: StatsDFormatDogStatsD,
+			expected: "docker.cli.command.count:1|c|#command:compose_up,backend:moby,status:success\n" +
+				"docker.cli.command.duration:1500|ms|#command:compose_up,backend:moby,status:success",
+		},
+		{
+			format: StatsDFormatInflux,
+			expected: "docker.cli.command.count,command=compose_up,backend=moby,status=success:1|c\n" +
+				"docker.cli.command.duration,command=compose_up,backend=moby,status=success:1500|ms",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.format, func(t *testing.T) {
+			agent, err := net.ListenPacket("udp", "127.0.0.1:0")
+			assert.NilError(t, err)
+			defer agent.Close() //nolint:errcheck
+
+			reporter, err := NewStatsDReporter(agent.LocalAddr().String(), testCase.format)
+			assert.NilError(t, err)
+			defer reporter.Close() //nolint:errcheck
+			reporter.Heartbeat(context.Background(), CommandUsage{Command: "compose up", Context: "moby", Status: SuccessStatus, DurationSecs: 1.5})
+
+			buf := make([]byte, 1024)
+			assert.NilError(t, agent.SetReadDeadline(time.Now().Add(5*time.Second)))
+			n, _, err := agent.ReadFrom(buf)
+			assert.NilError(t, err)
+			assert.Equal(t, string(buf[:n]), testCase.expected)
+		})
+	}
+}
+
+func TestStatsDReporterFormat(t *testing.T) {
+	_, err := NewStatsDReporter("127.0.0.1:8125", "graphite")
+	assert.ErrorContains(t, err, `unsupported StatsD format "graphite"`)
+}
@@ -0,0 +1,89 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvVarTraceParent is the environment variable conventionally used to pass a
+// W3C trace context to a child process, e.g. by CI systems.
+const EnvVarTraceParent = "TRACEPARENT"
+
+// TraceContext identifies the span metric events are reported under.
+type TraceContext struct {
+	// TraceID is 16 bytes, hex encoded.
+	TraceID string
+	// SpanID is 8 bytes, hex encoded.
+	SpanID string
+}
+
+type traceContextKey struct{}
+
+// WithTraceContext returns a context carrying the trace context.
+func WithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, tc)
+}
+
+// TraceContextFrom returns the trace context carried by the context, if any.
+func TraceContextFrom(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(traceContextKey{}).(TraceContext)
+	return tc, ok
+}
+
+// ParseTraceParent parses a W3C `traceparent` header value.
+func ParseTraceParent(s string) (TraceContext, error) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) != 4 || parts[0] != "00" || !isHexID(parts[1], 16) || !isHexID(parts[2], 8) {
+		return TraceContext{}, fmt.Errorf("invalid traceparent %q", s)
+	}
+	return TraceContext{TraceID: parts[1], SpanID: parts[2]}, nil
+}
+
+// TraceParent returns the W3C `traceparent` header value of the trace context.
+func (tc TraceContext) TraceParent() string {
+	return fmt.Sprintf("00-%s-%s-01", tc.TraceID, tc.SpanID)
+}
+
+func isHexID(s string, size int) bool {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != size {
+		return false
+	}
+	for _, c := range b {
+		if c != 0 {
+			return true
+		}
+	}
+	// all zeroes is invalid
+	return false
+}
+
+// withEnvTraceContext returns a context carrying the trace context inherited
+// from the environment, if any.
+func withEnvTraceContext(ctx context.Context) context.Context {
+	if v := os.Getenv(EnvVarTraceParent); v != "" {
+		if tc, err := ParseTraceParent(v); err == nil {
+			return WithTraceContext(ctx, tc)
+		}
+	}
+	return ctx
+}
@@ -0,0 +1,104 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceParent(t *testing.T) {
+	tc, err := ParseTraceParent(testTraceParent)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, tc, TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"})
+	assert.Equal(t, tc.TraceParent(), testTraceParent)
+
+	for _, invalid := range []string{
+		"",
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba9-01",
+	} {
+		_, err := ParseTraceParent(invalid)
+		assert.ErrorContains(t, err, "invalid traceparent", invalid)
+	}
+}
+
+func TestOTLPReporterTraceContext(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+	t.Setenv(EnvVarOTLPTracesEndpoint, server.URL)
+	t.Setenv(EnvVarOTLPProtocol, "http/json")
+	reporter, err := NewOTLPReporter()
+	assert.NilError(t, err)
+
+	t.Setenv(EnvVarTraceParent, testTraceParent)
+	ctx := withEnvTraceContext(context.Background())
+	reporter.Event(ctx, DockerCLIEvent{Command: "compose", Subcommand: "up", StartTime: time.Now()})
+
+	var traces otlpTracesRequest
+	assert.NilError(t, json.Unmarshal(body, &traces))
+	span := traces.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	assert.Equal(t, span.TraceID, "4bf92f3577b34da6a3ce929d0e0e4736")
+	assert.Equal(t, span.ParentSpanID, "00f067aa0ba902b7")
+	assert.Assert(t, span.SpanID != span.ParentSpanID)
+}
+
+type legacyCounter struct {
+	heartbeats int
+}
+
+func (l *legacyCounter) Heartbeat(CommandUsage) {
+	l.heartbeats++
+}
+
+func (l *legacyCounter) Event(DockerCLIEvent) {}
+
+func TestLegacyAdapters(t *testing.T) {
+	legacy := &legacyCounter{}
+	r := FromLegacy(legacy)
+	r.Heartbeat(context.Background(), CommandUsage{})
+	ToLegacy(r).Heartbeat(CommandUsage{})
+	assert.Equal(t, legacy.heartbeats, 2)
+}
+
+func TestHTTPReporterCanceled(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	reporter := NewHTTPReporter(newTestHTTPClient(server))
+	reporter.Heartbeat(ctx, CommandUsage{Command: "up"})
+	assert.Equal(t, calls, 0)
+	assert.Assert(t, !reporter.breaker.isOpen())
+}
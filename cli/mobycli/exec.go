@@ -87,25 +87,32 @@ func Exec(_ *cobra.Command) {
 			exitCode := exiterr.ExitCode()
 			metricsClient.Track(
 				metrics.CmdResult{
-					ContextType: store.DefaultContextType,
-					Args:        os.Args[1:],
-					Status:      metrics.FailureCategoryFromExitCode(exitCode).MetricsStatus,
-					ExitCode:    exitCode,
-					Start:       start,
-					Duration:    duration,
+					ContextType:    store.DefaultContextType,
+					BackendVersion: CliVersion(),
+					Args:           os.Args[1:],
+					Status:         metrics.FailureCategoryFromExitCode(exitCode).MetricsStatus,
+					ExitCode:       exitCode,
+					Start:          start,
+					Duration:       duration,
+					ErrorCategory:  metrics.ErrorCategoryFromExitCode(exitCode),
 				},
 			)
+			metricsClient.Close()
 			os.Exit(exitCode)
 		}
 		metricsClient.Track(
 			metrics.CmdResult{
-				ContextType: store.DefaultContextType,
-				Args:        os.Args[1:],
-				Status:      metrics.FailureStatus,
-				Start:       start,
-				Duration:    duration,
+				ContextType:    store.DefaultContextType,
+				BackendVersion: CliVersion(),
+				Args:           os.Args[1:],
+				Status:         metrics.FailureStatus,
+				ExitCode:       1,
+				Start:          start,
+				Duration:       duration,
+				ErrorCategory:  metrics.ClassifyError(err),
 			},
 		)
+		metricsClient.Close()
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -123,15 +130,17 @@ func Exec(_ *cobra.Command) {
 
 	metricsClient.Track(
 		metrics.CmdResult{
-			ContextType: store.DefaultContextType,
-			Args:        os.Args[1:],
-			Status:      metrics.SuccessStatus,
-			ExitCode:    0,
-			Start:       start,
-			Duration:    duration,
+			ContextType:    store.DefaultContextType,
+			BackendVersion: CliVersion(),
+			Args:           os.Args[1:],
+			Status:         metrics.SuccessStatus,
+			ExitCode:       0,
+			Start:          start,
+			Duration:       duration,
 		},
 	)
 
+	metricsClient.Close()
 	os.Exit(0)
 }
 
@@ -23,6 +23,7 @@ import (
 
 	"github.com/docker/compose-cli/cli/metrics"
 	"github.com/docker/compose-cli/cli/server/proxy"
+	"github.com/docker/compose-cli/internal"
 )
 
 var (
@@ -67,10 +68,11 @@ func metricsServerInterceptor(client metrics.Client) grpc.UnaryServerInterceptor
 		command := methodMapping[info.FullMethod]
 		if command != "" {
 			client.SendUsage(metrics.CommandUsage{
-				Command: command,
-				Context: contextType,
-				Source:  metrics.APISource,
-				Status:  status,
+				Command:        command,
+				Context:        contextType,
+				Source:         metrics.APISource,
+				Status:         status,
+				BackendVersion: internal.Version,
 			})
 		}
 		return data, err
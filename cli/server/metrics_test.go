@@ -38,10 +38,11 @@ import (
 	streamsv1 "github.com/docker/compose-cli/cli/server/protos/streams/v1"
 	volumesv1 "github.com/docker/compose-cli/cli/server/protos/volumes/v1"
 	"github.com/docker/compose-cli/cli/server/proxy"
+	"github.com/docker/compose-cli/internal"
 )
 
 func TestAllMethodsHaveCorrespondingCliCommand(t *testing.T) {
-	s := setupServer()
+	s := setupServer(t)
 	i := s.GetServiceInfo()
 	for k, v := range i {
 		if k == "grpc.health.v1.Health" {
@@ -60,7 +61,7 @@ func TestAllMethodsHaveCorrespondingCliCommand(t *testing.T) {
 
 func TestTrackSuccess(t *testing.T) {
 	var mockMetrics = &mockMetricsClient{}
-	mockMetrics.On("SendUsage", metrics.CommandUsage{Command: "ps", Context: "aci", Status: "success", Source: "api"}).Return()
+	mockMetrics.On("SendUsage", metrics.CommandUsage{Command: "ps", Context: "aci", Status: "success", Source: "api", BackendVersion: internal.Version}).Return()
 	newClient := client.NewClient("aci", noopService{})
 	interceptor := metricsServerInterceptor(mockMetrics)
 
@@ -99,9 +100,11 @@ func incomingContext(status string) context.Context {
 	return ctx
 }
 
-func setupServer() *grpc.Server {
+func setupServer(t *testing.T) *grpc.Server {
+	// keep the metrics state out of the user configuration directory
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
 	ctx := context.TODO()
-	s := New(ctx)
+	s := New(ctx, metrics.NewDefaultClient())
 	p := proxy.New(ctx)
 	containersv1.RegisterContainersServer(s, p)
 	streamsv1.RegisterStreamingServer(s, p)
@@ -133,3 +136,7 @@ func (s *mockMetricsClient) SendUsage(command metrics.CommandUsage) {
 func (s *mockMetricsClient) Track(cmd metrics.CmdResult) {
 	s.Called(cmd)
 }
+
+func (s *mockMetricsClient) Close() {
+	s.Called()
+}
@@ -28,12 +28,12 @@ import (
 	"github.com/docker/compose-cli/cli/metrics"
 )
 
-// New returns a new GRPC server.
-func New(ctx context.Context) *grpc.Server {
+// New returns a new GRPC server reporting usage through the given metrics client.
+func New(ctx context.Context, metricsClient metrics.Client) *grpc.Server {
 	s := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			unaryServerInterceptor(ctx),
-			metricsServerInterceptor(metrics.NewDefaultClient()),
+			metricsServerInterceptor(metricsClient),
 		),
 		grpc.StreamInterceptor(streamServerInterceptor(ctx)),
 	)
@@ -18,6 +18,7 @@ package ecs
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/ecs"
@@ -44,12 +45,15 @@ type API interface {
 	IsPublicSubnet(ctx context.Context, subNetID string) (bool, error)
 	GetRoleArn(ctx context.Context, name string) (string, error)
 	StackExists(ctx context.Context, name string) (bool, error)
-	CreateStack(ctx context.Context, name string, region string, template []byte) error
-	CreateChangeSet(ctx context.Context, name string, region string, template []byte) (string, error)
+	CreateStack(ctx context.Context, name string, region string, template []byte, tags map[string]string) error
+	CreateChangeSet(ctx context.Context, name string, region string, template []byte, tags map[string]string) (string, error)
 	UpdateStack(ctx context.Context, changeset string) error
+	DescribeChangeSetChanges(ctx context.Context, changeset string) ([]stackChange, error)
+	DeleteChangeSet(ctx context.Context, changeset string) error
 	WaitStackComplete(ctx context.Context, name string, operation int) error
 	GetStackID(ctx context.Context, name string) (string, error)
 	ListStacks(ctx context.Context) ([]api.Stack, error)
+	ListStackTags(ctx context.Context, key string) (map[string]string, error)
 	GetStackClusterID(ctx context.Context, stack string) (string, error)
 	GetServiceTaskDefinition(ctx context.Context, cluster string, serviceArns []string) (map[string]string, error)
 	ListStackServices(ctx context.Context, stack string) ([]string, error)
@@ -63,9 +67,12 @@ type API interface {
 	InspectSecret(ctx context.Context, id string) (secrets.Secret, error)
 	ListSecrets(ctx context.Context) ([]secrets.Secret, error)
 	DeleteSecret(ctx context.Context, id string, recover bool) error
-	GetLogs(ctx context.Context, name string, consumer func(container string, service string, message string), follow bool) error
+	GetLogs(ctx context.Context, name string, consumer func(container string, service string, message string), follow bool, since time.Time, until time.Time) error
 	DescribeService(ctx context.Context, cluster string, arn string) (api.ServiceStatus, error)
 	DescribeServiceTasks(ctx context.Context, cluster string, project string, service string) ([]api.ContainerSummary, error)
+	RestartService(ctx context.Context, cluster string, arn string) error
+	ScaleService(ctx context.Context, cluster string, arn string, replicas int) error
+	DescribeServiceImages(ctx context.Context, cluster string, project string, service string) ([]api.ImageSummary, error)
 	getURLWithPortMapping(ctx context.Context, targetGroupArns []string) ([]api.PortPublisher, error)
 	ListTasks(ctx context.Context, cluster string, family string) ([]string, error)
 	GetPublicIPs(ctx context.Context, interfaces ...string) (map[string]string, error)
@@ -19,7 +19,6 @@ package ecs
 import (
 	"context"
 	"fmt"
-	"os"
 	"regexp"
 	"strings"
 
@@ -124,6 +123,9 @@ func (b *ecsAPIService) convert(ctx context.Context, project *types.Project) (*c
 	if err != nil {
 		return nil, err
 	}
+	if err := utils.CheckProfiles(project); err != nil {
+		return nil, err
+	}
 
 	template := cloudformation.NewTemplate()
 	resources, err := b.parse(ctx, project, template)
@@ -298,7 +300,7 @@ func (b *ecsAPIService) createSecret(project *types.Project, name string, s type
 	if s.External.External {
 		return nil
 	}
-	sensitiveData, err := os.ReadFile(s.File)
+	sensitiveData, err := utils.SecretContent(project, name, s)
 	if err != nil {
 		return err
 	}
@@ -32,6 +32,7 @@ import (
 	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
 	"github.com/awslabs/goformation/v4/cloudformation/iam"
 	"github.com/awslabs/goformation/v4/cloudformation/logs"
+	"github.com/awslabs/goformation/v4/cloudformation/secretsmanager"
 	"github.com/compose-spec/compose-go/loader"
 	"github.com/compose-spec/compose-go/types"
 	"github.com/docker/compose/v2/pkg/api"
@@ -74,6 +75,36 @@ x-aws-logs_retention: 10
 	assert.Equal(t, logGroup.RetentionInDays, 10)
 }
 
+func TestLoggingDrivers(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+    logging:
+      driver: splunk
+      options:
+        splunk-url: https://splunk.example.com:8088
+  bar:
+    image: hello_world
+    logging:
+      driver: fluentd
+      options:
+        fluentd-address: localhost:24224
+        mode: non-blocking
+`, nil, useDefaultVPC)
+	def := template.Resources["FooTaskDefinition"].(*ecs.TaskDefinition)
+	logging := getMainContainer(def, t).LogConfiguration
+	assert.Equal(t, logging.LogDriver, "splunk")
+	assert.DeepEqual(t, logging.Options, map[string]string{"splunk-url": "https://splunk.example.com:8088"})
+
+	def = template.Resources["BarTaskDefinition"].(*ecs.TaskDefinition)
+	logging = getMainContainer(def, t).LogConfiguration
+	assert.Equal(t, logging.LogDriver, "awslogs")
+	assert.Equal(t, logging.Options["mode"], "non-blocking")
+	_, ok := logging.Options["fluentd-address"]
+	assert.Assert(t, !ok)
+}
+
 func TestEnvFile(t *testing.T) {
 	template := convertYaml(t, `
 services:
@@ -116,6 +147,50 @@ services:
 	assert.Check(t, found, "environment variable FOO not set")
 }
 
+func TestMultipleEnvFiles(t *testing.T) {
+	zot := "ZOT"
+	project := &types.Project{WorkingDir: "testdata/input"}
+	service := types.ServiceConfig{
+		Name:        "foo",
+		EnvFile:     types.StringList{"envfile", "envfile.override"},
+		Environment: types.MappingWithEquals{"QIX": &zot},
+	}
+	env, err := createEnvironment(project, service)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, env, []ecs.TaskDefinition_KeyValuePair{
+		{Name: "BAR", Value: "QIX"},
+		{Name: "FOO", Value: "BAZ"},
+		{Name: "QIX", Value: "ZOT"},
+	})
+
+	service.EnvFile = append(service.EnvFile, "missing")
+	_, err = createEnvironment(project, service)
+	assert.Error(t, err, `service "foo": env file testdata/input/missing not found`)
+}
+
+func TestEnvironmentSecret(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  foo:
+    image: hello_world
+    secrets:
+      - token
+secrets:
+  token:
+    x-environment: TOKEN
+`)
+	project.Environment = map[string]string{"TOKEN": "s3cr3t"}
+	template := cloudformation.NewTemplate()
+	backend := &ecsAPIService{}
+	assert.NilError(t, backend.createSecret(project, "token", project.Secrets["token"], template))
+	secret := template.Resources["TestEnvironmentSecrettokenSecret"].(*secretsmanager.Secret)
+	assert.Equal(t, secret.SecretString, "s3cr3t")
+
+	project.Environment = map[string]string{}
+	err := backend.createSecret(project, "token", project.Secrets["token"], template)
+	assert.Error(t, err, `secret "token": environment variable TOKEN is not set`)
+}
+
 func TestRollingUpdateLimits(t *testing.T) {
 	template := convertYaml(t, `
 services:
@@ -336,6 +411,60 @@ services:
 	assert.Equal(t, def.Memory, "")
 }
 
+func TestNvidiaRuntime(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+    runtime: nvidia
+`, nil, useDefaultVPC, useGPU)
+	def := template.Resources["TestTaskDefinition"].(*ecs.TaskDefinition)
+	container := getMainContainer(def, t)
+	assert.DeepEqual(t, container.ResourceRequirements, []ecs.TaskDefinition_ResourceRequirement{
+		{Type: "GPU", Value: "1"},
+	})
+	assert.Check(t, template.Resources["LaunchConfiguration"] != nil)
+}
+
+func TestUlimits(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+    ulimits:
+      nofile:
+        soft: 20000
+        hard: 40000
+  db:
+    image: postgres
+    ulimits:
+      nofile: 65535
+`, nil, useDefaultVPC)
+	def := template.Resources["TestTaskDefinition"].(*ecs.TaskDefinition)
+	container := getMainContainer(def, t)
+	assert.DeepEqual(t, container.Ulimits, []ecs.TaskDefinition_Ulimit{
+		{Name: "nofile", SoftLimit: 20000, HardLimit: 40000},
+	})
+
+	def = template.Resources["DbTaskDefinition"].(*ecs.TaskDefinition)
+	container = getMainContainer(def, t)
+	assert.DeepEqual(t, container.Ulimits, []ecs.TaskDefinition_Ulimit{
+		{Name: "nofile", SoftLimit: 65535, HardLimit: 65535},
+	})
+}
+
+func TestStopGracePeriod(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+    stop_grace_period: 45s
+`, nil, useDefaultVPC)
+	def := template.Resources["TestTaskDefinition"].(*ecs.TaskDefinition)
+	container := getMainContainer(def, t)
+	assert.Equal(t, container.StopTimeout, 45)
+}
+
 func TestLoadBalancerTypeNetwork(t *testing.T) {
 	template := convertYaml(t, `
 services:
@@ -18,11 +18,17 @@ package ecs
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/compose-spec/compose-go/compatibility"
 	"github.com/compose-spec/compose-go/errdefs"
 	"github.com/compose-spec/compose-go/types"
+	"github.com/containerd/containerd/platforms"
 	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/utils"
 )
 
 func (b *ecsAPIService) checkCompatibility(project *types.Project) error {
@@ -76,6 +82,7 @@ var compatibleComposeAttributes = []string{
 	"services.entrypoint",
 	"services.environment",
 	"services.env_file",
+	"services.extends",
 	"services.healthcheck",
 	"services.healthcheck.interval",
 	"services.healthcheck.retries",
@@ -88,13 +95,17 @@ var compatibleComposeAttributes = []string{
 	"services.logging",
 	"services.logging.options",
 	"services.networks",
+	"services.platform",
 	"services.ports",
 	"services.ports.mode",
 	"services.ports.target",
 	"services.ports.protocol",
+	"services.pull_policy",
 	"services.secrets",
 	"services.secrets.source",
 	"services.secrets.target",
+	"services.stop_grace_period",
+	"services.sysctls",
 	"services.user",
 	"services.volumes",
 	"services.volumes.read_only",
@@ -117,6 +128,14 @@ func (c *fargateCompatibilityChecker) CheckImage(service *types.ServiceConfig) {
 	}
 }
 
+// CheckPullPolicy rejects the never policy as Fargate pulls images each time
+// a task starts, the other policies all resolve to pulling images.
+func (c *fargateCompatibilityChecker) CheckPullPolicy(service *types.ServiceConfig) {
+	if service.PullPolicy == types.PullPolicyNever {
+		c.Incompatible("service %s: ECS Fargate pulls images when tasks start, pull_policy never can't be honored", service.Name)
+	}
+}
+
 func (c *fargateCompatibilityChecker) CheckPortsPublished(p *types.ServicePortConfig) {
 	if p.Published == 0 {
 		p.Published = p.Target
@@ -148,9 +167,11 @@ func (c *fargateCompatibilityChecker) CheckCapAdd(service *types.ServiceConfig)
 	service.CapAdd = add
 }
 
+// CheckLoggingDriver warns about the logging drivers Fargate doesn't support,
+// the logs of the services using them being sent to CloudWatch Logs
 func (c *fargateCompatibilityChecker) CheckLoggingDriver(config *types.LoggingConfig) {
-	if config.Driver != "" && config.Driver != "awslogs" {
-		c.Unsupported("services.logging.driver %s is not supported", config.Driver)
+	if config.Driver != "" && config.Driver != "awslogs" && config.Driver != "splunk" {
+		c.Unsupported("services.logging.driver %s is not supported by Fargate, which supports awslogs and splunk, logs are sent to CloudWatch Logs", config.Driver)
 	}
 }
 
@@ -163,6 +184,103 @@ func (c *fargateCompatibilityChecker) CheckUlimits(service *types.ServiceConfig)
 	}
 }
 
+// CheckRestart warns about the restart policies the ECS service scheduler
+// can't honor, as it replaces the tasks which stop
+func (c *fargateCompatibilityChecker) CheckRestart(service *types.ServiceConfig) {
+	switch strings.SplitN(service.Restart, ":", 2)[0] {
+	case "", "always", "unless-stopped":
+	default:
+		c.Unsupported("service %s: the ECS service scheduler replaces the tasks which stop, restart %s can't be honored", service.Name, service.Restart)
+	}
+	service.Restart = ""
+}
+
+// CheckDeployRestartPolicy warns about the restart policy conditions the ECS
+// service scheduler can't honor, as it replaces the tasks which stop, without
+// delay nor limit on the number of attempts
+func (c *fargateCompatibilityChecker) CheckDeployRestartPolicy(deploy *types.DeployConfig) bool {
+	policy := deploy.RestartPolicy
+	if policy.Condition != "" && policy.Condition != "any" {
+		c.Unsupported("services.deploy.restart_policy.condition %s can't be honored, the ECS service scheduler replaces the tasks which stop", policy.Condition)
+	}
+	if policy.Delay != nil || policy.MaxAttempts != nil || policy.Window != nil {
+		c.Unsupported("services.deploy.restart_policy delay, max_attempts and window are not supported by the ECS service scheduler")
+	}
+	deploy.RestartPolicy = nil
+	return false
+}
+
+// fargateMaxStopTimeout is the longest Fargate waits for containers to stop
+// before killing them
+const fargateMaxStopTimeout = 2 * time.Minute
+
+// CheckStopGracePeriod caps the stop grace period to the one Fargate supports
+func (c *fargateCompatibilityChecker) CheckStopGracePeriod(service *types.ServiceConfig) {
+	if service.StopGracePeriod == nil || time.Duration(*service.StopGracePeriod) <= fargateMaxStopTimeout {
+		return
+	}
+	c.Unsupported("service %s: stop_grace_period %s exceeds the %s Fargate waits at most for containers to stop", service.Name, time.Duration(*service.StopGracePeriod), fargateMaxStopTimeout)
+	period := types.Duration(fargateMaxStopTimeout)
+	service.StopGracePeriod = &period
+}
+
+// fargatePlatform is the platform of the task definitions, which don't set a
+// runtime platform
+const fargatePlatform = "linux/amd64"
+
+// CheckPlatform rejects the platforms other than the one of the task
+// definitions
+func (c *fargateCompatibilityChecker) CheckPlatform(service *types.ServiceConfig) {
+	if service.Platform == "" {
+		return
+	}
+	p, err := platforms.Parse(service.Platform)
+	if err != nil {
+		c.Incompatible("service %s: invalid platform %q: %s", service.Name, service.Platform, err)
+		return
+	}
+	if platforms.Format(platforms.Normalize(p)) != fargatePlatform {
+		c.Incompatible("service %s: platform %s is not supported, this integration only runs %s containers on ECS", service.Name, service.Platform, fargatePlatform)
+	}
+}
+
+// CheckSysctls rejects the kernel parameters Fargate can't set, only the
+// network ones being namespaced in tasks
+func (c *fargateCompatibilityChecker) CheckSysctls(service *types.ServiceConfig) {
+	var unsupported []string
+	for k := range service.Sysctls {
+		if !strings.HasPrefix(k, "net.") {
+			unsupported = append(unsupported, k)
+		}
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		c.Incompatible("service %s: ECS Fargate only supports net.* sysctls, %s can't be set", service.Name, strings.Join(unsupported, ", "))
+	}
+}
+
+// CheckRuntime replaces the nvidia runtime, ECS tasks can't set, with the GPU
+// device reservation it stands for
+func (c *fargateCompatibilityChecker) CheckRuntime(service *types.ServiceConfig) {
+	if service.Runtime != utils.NvidiaRuntime {
+		c.AllowList.CheckRuntime(service)
+		return
+	}
+	device, _ := utils.GPUReservation(*service)
+	service.Runtime = ""
+	if _, ok := utils.GPUReservation(*service); ok {
+		return
+	}
+	if service.Deploy == nil {
+		service.Deploy = &types.DeployConfig{}
+	}
+	if service.Deploy.Resources.Reservations == nil {
+		service.Deploy.Resources.Reservations = &types.Resource{}
+	}
+	reservations := service.Deploy.Resources.Reservations
+	reservations.Devices = append(reservations.Devices, device)
+}
+
 func (c *fargateCompatibilityChecker) CheckDeployResourcesDevicesCapabilities(s string, r types.DeviceRequest) {
 	for _, cap := range r.Capabilities {
 		if cap != "gpu" {
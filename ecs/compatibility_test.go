@@ -0,0 +1,228 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/loader"
+	"github.com/compose-spec/compose-go/types"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCompatibilityPullPolicy(t *testing.T) {
+	backend := &ecsAPIService{}
+	project := loadConfig(t, `
+services:
+  web:
+    image: nginx
+    pull_policy: always
+`)
+	assert.NilError(t, backend.checkCompatibility(project))
+	assert.Equal(t, project.Services[0].PullPolicy, "always")
+
+	project = loadConfig(t, `
+services:
+  web:
+    image: nginx
+    pull_policy: never
+`)
+	assert.ErrorContains(t, backend.checkCompatibility(project), "pull_policy never can't be honored")
+}
+
+func TestCompatibilityExtends(t *testing.T) {
+	backend := &ecsAPIService{}
+	project := loadConfig(t, `
+services:
+  base:
+    image: nginx
+    environment:
+      MODE: base
+  web:
+    extends:
+      service: base
+    environment:
+      MODE: web
+  worker:
+    extends:
+      file: testdata/input/base-service.yaml
+      service: worker
+`)
+	assert.NilError(t, backend.checkCompatibility(project))
+	web, err := project.GetService("web")
+	assert.NilError(t, err)
+	assert.Equal(t, web.Image, "nginx")
+	assert.Equal(t, *web.Environment["MODE"], "web")
+	assert.Check(t, web.Extends != nil)
+	worker, err := project.GetService("worker")
+	assert.NilError(t, err)
+	assert.Equal(t, worker.Image, "busybox")
+}
+
+func TestCompatibilityExtendsErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		yaml string
+		err  string
+	}{
+		{
+			name: "cycle",
+			yaml: `
+services:
+  web:
+    extends:
+      service: worker
+  worker:
+    extends:
+      service: web
+`,
+			err: "Circular reference",
+		},
+		{
+			name: "missing base",
+			yaml: `
+services:
+  web:
+    extends:
+      service: base
+`,
+			err: `cannot extend service "base"`,
+		},
+		{
+			name: "missing base in file",
+			yaml: `
+services:
+  web:
+    extends:
+      file: testdata/input/base-service.yaml
+      service: base
+`,
+			err: `cannot extend service "base" in testdata/input/base-service.yaml: service not found`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dict, err := loader.ParseYAML([]byte(tc.yaml))
+			assert.NilError(t, err)
+			_, err = loader.Load(types.ConfigDetails{
+				ConfigFiles: []types.ConfigFile{{Config: dict}},
+			}, func(options *loader.Options) {
+				options.Name = "extends"
+			})
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestCompatibilitySysctls(t *testing.T) {
+	backend := &ecsAPIService{}
+	project := loadConfig(t, `
+services:
+  web:
+    image: nginx
+    sysctls:
+      net.core.somaxconn: 1024
+      net.ipv4.tcp_syncookies: 0
+`)
+	assert.NilError(t, backend.checkCompatibility(project))
+	assert.Equal(t, len(project.Services[0].Sysctls), 2)
+
+	project = loadConfig(t, `
+services:
+  web:
+    image: nginx
+    sysctls:
+      net.core.somaxconn: 1024
+      kernel.shmmax: 1024
+      fs.mqueue.msg_max: 100
+`)
+	assert.ErrorContains(t, backend.checkCompatibility(project), "ECS Fargate only supports net.* sysctls, fs.mqueue.msg_max, kernel.shmmax can't be set")
+}
+
+func TestCompatibilityRestart(t *testing.T) {
+	backend := &ecsAPIService{}
+	project := loadConfig(t, `
+services:
+  web:
+    image: nginx
+    restart: unless-stopped
+  worker:
+    image: worker
+    deploy:
+      restart_policy:
+        condition: any
+`)
+	assert.NilError(t, backend.checkCompatibility(project))
+
+	checker := &fargateCompatibilityChecker{}
+	web := types.ServiceConfig{Name: "web", Restart: "unless-stopped"}
+	checker.CheckRestart(&web)
+	checker.CheckDeployRestartPolicy(&types.DeployConfig{RestartPolicy: &types.RestartPolicy{Condition: "any"}})
+	assert.Equal(t, len(checker.Errors()), 0)
+
+	web.Restart = "on-failure:3"
+	checker.CheckRestart(&web)
+	deploy := &types.DeployConfig{RestartPolicy: &types.RestartPolicy{Condition: "none"}}
+	checker.CheckDeployRestartPolicy(deploy)
+	assert.Equal(t, len(checker.Errors()), 2)
+	assert.ErrorContains(t, checker.Errors()[0], "restart on-failure:3 can't be honored")
+	assert.ErrorContains(t, checker.Errors()[1], "condition none can't be honored")
+	assert.Equal(t, web.Restart, "")
+	assert.Assert(t, deploy.RestartPolicy == nil)
+}
+
+func TestCompatibilityStopGracePeriod(t *testing.T) {
+	backend := &ecsAPIService{}
+	project := loadConfig(t, `
+services:
+  web:
+    image: nginx
+    stop_grace_period: 1m
+  worker:
+    image: worker
+    stop_grace_period: 5m
+`)
+	assert.NilError(t, backend.checkCompatibility(project))
+	web, err := project.GetService("web")
+	assert.NilError(t, err)
+	assert.Equal(t, *web.StopGracePeriod, types.Duration(time.Minute))
+	worker, err := project.GetService("worker")
+	assert.NilError(t, err)
+	assert.Equal(t, *worker.StopGracePeriod, types.Duration(2*time.Minute))
+}
+
+func TestCompatibilityPlatform(t *testing.T) {
+	backend := &ecsAPIService{}
+	project := loadConfig(t, `
+services:
+  web:
+    image: nginx
+    platform: linux/amd64
+`)
+	assert.NilError(t, backend.checkCompatibility(project))
+
+	project = loadConfig(t, `
+services:
+  web:
+    image: nginx
+    platform: linux/arm64
+`)
+	err := backend.checkCompatibility(project)
+	assert.ErrorContains(t, err, "service web: platform linux/arm64 is not supported, this integration only runs linux/amd64 containers on ECS")
+}
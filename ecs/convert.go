@@ -269,26 +269,20 @@ func createSecretsSideCar(project *types.Project, service types.ServiceConfig, l
 	return secretsVolume, secretsMount, secretsSideCar, nil
 }
 
+// createEnvironment returns the environment of the service containers. The
+// env_file entries are read in order, a file overriding the variables set by
+// the files listed before it, and variables set by environment override the
+// ones of all env files. Relative env_file paths are resolved against the
+// project working directory.
 func createEnvironment(project *types.Project, service types.ServiceConfig) ([]ecs.TaskDefinition_KeyValuePair, error) {
 	environment := map[string]*string{}
 	for _, f := range service.EnvFile {
-		if !filepath.IsAbs(f) {
-			f = filepath.Join(project.WorkingDir, f)
-		}
-		if _, err := os.Stat(f); os.IsNotExist(err) {
-			return nil, err
-		}
-		file, err := os.Open(f)
-		if err != nil {
-			return nil, err
-		}
-		defer file.Close() // nolint:errcheck
-
-		env, err := godotenv.Parse(file)
+		env, err := readEnvFile(project, service, f)
 		if err != nil {
 			return nil, err
 		}
 		for k, v := range env {
+			v := v
 			environment[k] = &v
 		}
 	}
@@ -318,7 +312,43 @@ func createEnvironment(project *types.Project, service types.ServiceConfig) ([]e
 	return pairs, nil
 }
 
+// readEnvFile returns the variables set by the given env_file entry of the
+// service.
+func readEnvFile(project *types.Project, service types.ServiceConfig, f string) (map[string]string, error) {
+	if !filepath.IsAbs(f) {
+		f = filepath.Join(project.WorkingDir, f)
+	}
+	file, err := os.Open(f)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("service %q: env file %s not found", service.Name, f)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() // nolint:errcheck
+
+	env, err := godotenv.Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("service %q: invalid env file %s: %w", service.Name, f, err)
+	}
+	return env, nil
+}
+
+// awslogsOptions are the options of the awslogs driver not prefixed with
+// awslogs-
+var awslogsOptions = map[string]bool{
+	"mode":            true,
+	"max-buffer-size": true,
+}
+
 func getLogConfiguration(service types.ServiceConfig, project *types.Project) *ecs.TaskDefinition_LogConfiguration {
+	if service.Logging != nil && service.Logging.Driver == ecsapi.LogDriverSplunk {
+		// logs are sent to splunk directly, with the options of the service
+		return &ecs.TaskDefinition_LogConfiguration{
+			LogDriver: ecsapi.LogDriverSplunk,
+			Options:   service.Logging.Options,
+		}
+	}
 	options := map[string]string{
 		"awslogs-region":        cloudformation.Ref("AWS::Region"),
 		"awslogs-group":         cloudformation.Ref("LogGroup"),
@@ -326,7 +356,7 @@ func getLogConfiguration(service types.ServiceConfig, project *types.Project) *e
 	}
 	if service.Logging != nil {
 		for k, v := range service.Logging.Options {
-			if strings.HasPrefix(k, "awslogs-") {
+			if strings.HasPrefix(k, "awslogs-") || awslogsOptions[k] {
 				options[k] = v
 			}
 		}
@@ -346,6 +376,9 @@ func toSystemControls(sysctls types.Mapping) []ecs.TaskDefinition_SystemControl
 			Value:     v,
 		})
 	}
+	sort.Slice(sys, func(i, j int) bool {
+		return sys[i].Namespace < sys[j].Namespace
+	})
 	return sys
 }
 
@@ -477,12 +510,20 @@ func toUlimits(ulimits map[string]*types.UlimitsConfig) []ecs.TaskDefinition_Uli
 	}
 	u := []ecs.TaskDefinition_Ulimit{}
 	for k, v := range ulimits {
+		// a single value sets both the soft and hard limits
+		soft, hard := v.Soft, v.Hard
+		if v.Single != 0 {
+			soft, hard = v.Single, v.Single
+		}
 		u = append(u, ecs.TaskDefinition_Ulimit{
 			Name:      k,
-			SoftLimit: v.Soft,
-			HardLimit: v.Hard,
+			SoftLimit: soft,
+			HardLimit: hard,
 		})
 	}
+	sort.Slice(u, func(i, j int) bool {
+		return u[i].Name < u[j].Name
+	})
 	return u
 }
 
@@ -584,6 +625,9 @@ func gpuRequirements(s types.ServiceConfig) int64 {
 			}
 			for _, device := range reservations.Devices {
 				if len(device.Capabilities) == 1 && device.Capabilities[0] == "gpu" {
+					if device.Count <= 0 {
+						return 1
+					}
 					return device.Count
 				}
 			}
@@ -18,9 +18,11 @@ package ecs
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/docker/compose/v2/pkg/api"
 	"github.com/docker/compose/v2/pkg/progress"
+	"github.com/hashicorp/go-multierror"
 
 	"github.com/docker/compose-cli/utils"
 )
@@ -29,12 +31,15 @@ func (b *ecsAPIService) Down(ctx context.Context, projectName string, options ap
 	if err := checkUnsupportedDownOptions(ctx, options); err != nil {
 		return err
 	}
+	if utils.IsDryRun(ctx) {
+		return b.dryRunDown(ctx, projectName, options)
+	}
 	return progress.Run(ctx, func(ctx context.Context) error {
-		return b.down(ctx, projectName)
+		return b.down(ctx, projectName, options)
 	})
 }
 
-func (b *ecsAPIService) down(ctx context.Context, projectName string) error {
+func (b *ecsAPIService) down(ctx context.Context, projectName string, options api.DownOptions) error {
 	resources, err := b.aws.ListStackResources(ctx, projectName)
 	if err != nil {
 		return err
@@ -59,7 +64,38 @@ func (b *ecsAPIService) down(ctx context.Context, projectName string) error {
 	if err != nil {
 		return err
 	}
-	return b.WaitStackCompletion(ctx, projectName, stackDelete, previousEvents...)
+	err = b.WaitStackCompletion(ctx, projectName, stackDelete, previousEvents...)
+	if err != nil {
+		return err
+	}
+	if options.Volumes {
+		return b.deleteVolumes(ctx, projectName)
+	}
+	return nil
+}
+
+// deleteVolumes deletes the project file systems, which the stack retains on
+// deletion so that data is kept across `compose down` and `compose up`
+func (b *ecsAPIService) deleteVolumes(ctx context.Context, projectName string) error {
+	filesystems, err := b.aws.ListFileSystems(ctx, map[string]string{
+		api.ProjectLabel: projectName,
+	})
+	if err != nil {
+		return err
+	}
+	w := progress.ContextWriter(ctx)
+	var errs *multierror.Error
+	for _, fs := range filesystems {
+		eventName := fmt.Sprintf("Volume %s", fs.ID())
+		w.Event(progress.RemovingEvent(eventName))
+		if err := b.aws.DeleteFileSystem(ctx, fs.ID()); err != nil {
+			w.Event(progress.ErrorEvent(eventName))
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		w.Event(progress.RemovedEvent(eventName))
+	}
+	return errs.ErrorOrNil()
 }
 
 func (b *ecsAPIService) previousStackEvents(ctx context.Context, project string) ([]string, error) {
@@ -88,15 +124,16 @@ func doDelete(ctx context.Context, delete func(ctx context.Context, arn string)
 	}
 }
 
+// checkUnsupportedDownOptions accepts --remove-orphans as deleting the stack
+// removes all the project services, including the ones removed from the
+// compose file.
 func checkUnsupportedDownOptions(ctx context.Context, o api.DownOptions) error {
 	var errs error
 	checks := []struct {
 		toCheck, expected interface{}
 		option            string
 	}{
-		{o.Volumes, false, "volumes"},
 		{o.Images, "", "images"},
-		{o.RemoveOrphans, false, "remove-orphans"},
 		{o.Timeout, nil, "timeout"},
 	}
 	for _, c := range checks {
@@ -0,0 +1,120 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/awslabs/goformation/v4/cloudformation/logs"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/config"
+	"github.com/docker/compose-cli/api/context/store"
+	"github.com/docker/compose-cli/utils"
+)
+
+func TestDeleteVolumes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	m.EXPECT().ListFileSystems(gomock.Any(), map[string]string{api.ProjectLabel: "demo"}).Return([]awsResource{
+		existingAWSResource{id: "fs-1"},
+		existingAWSResource{id: "fs-2"},
+	}, nil)
+	m.EXPECT().DeleteFileSystem(gomock.Any(), "fs-1").Return(errors.New("file system in use"))
+	m.EXPECT().DeleteFileSystem(gomock.Any(), "fs-2").Return(nil)
+
+	backend := &ecsAPIService{aws: m}
+	err := backend.deleteVolumes(context.TODO(), "demo")
+	assert.ErrorContains(t, err, "file system in use")
+}
+
+func TestDownUnsupportedOptions(t *testing.T) {
+	ctx := context.WithValue(context.Background(), config.ContextTypeKey, store.EcsContextType)
+	err := checkUnsupportedDownOptions(ctx, api.DownOptions{Volumes: true, RemoveOrphans: true})
+	assert.NilError(t, err)
+	err = checkUnsupportedDownOptions(ctx, api.DownOptions{Images: "all"})
+	assert.ErrorContains(t, err, "images")
+}
+
+func TestStackOperations(t *testing.T) {
+	template := cloudformation.NewTemplate()
+	template.Resources["Cluster"] = &ecs.Cluster{}
+	template.Resources["LogGroup"] = &logs.LogGroup{}
+	assert.DeepEqual(t, createStackOperations("demo", template), []utils.Operation{
+		{Action: "create", Resource: "stack", Name: "demo"},
+		{Action: "create", Resource: "AWS::ECS::Cluster", Name: "Cluster"},
+		{Action: "create", Resource: "AWS::Logs::LogGroup", Name: "LogGroup"},
+	})
+
+	assert.DeepEqual(t, changeSetOperations("demo", []stackChange{
+		{Action: "Modify", LogicalID: "WebService", Type: "AWS::ECS::Service"},
+		{Action: "Remove", LogicalID: "WorkerService", Type: "AWS::ECS::Service"},
+	}), []utils.Operation{
+		{Action: "update", Resource: "stack", Name: "demo"},
+		{Action: "modify", Resource: "AWS::ECS::Service", Name: "WebService"},
+		{Action: "remove", Resource: "AWS::ECS::Service", Name: "WorkerService"},
+	})
+}
+
+func TestDryRunUpKeepsDisabledDependencies(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  web:
+    image: nginx
+    depends_on:
+      - db
+  db:
+    image: postgres
+`)
+	// up --no-deps web
+	var services types.Services
+	for _, s := range project.Services {
+		if s.Name == "db" {
+			project.DisabledServices = append(project.DisabledServices, s)
+		} else {
+			services = append(services, s)
+		}
+	}
+	project.Services = services
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	useDefaultVPC(m.EXPECT())
+	m.EXPECT().StackExists(gomock.Any(), project.Name).Return(true, nil)
+	var template string
+	m.EXPECT().CreateChangeSet(gomock.Any(), project.Name, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, body []byte, _ map[string]string) (string, error) {
+			template = string(body)
+			return "", errors.New("The submitted information didn't contain changes")
+		})
+
+	backend := &ecsAPIService{aws: m}
+	assert.NilError(t, backend.dryRunUp(context.TODO(), project))
+	assert.Assert(t, strings.Contains(template, "DbTaskDefinition"))
+	assert.DeepEqual(t, project.ServiceNames(), []string{"web"})
+	assert.Equal(t, len(project.DisabledServices), 1)
+}
@@ -0,0 +1,122 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+// dryRunUp prints the changes compose up would make to the project stack.
+// Changes to an existing stack are previewed with a changeset, deleted
+// without being executed.
+func (b *ecsAPIService) dryRunUp(ctx context.Context, project *types.Project) error {
+	project = utils.KeepDisabledDependencies(project)
+	template, err := b.convert(ctx, project)
+	if err != nil {
+		return err
+	}
+	exists, err := b.aws.StackExists(ctx, project.Name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return utils.PrintOperations(os.Stdout, createStackOperations(project.Name, template))
+	}
+
+	body, err := marshall(template, "yaml")
+	if err != nil {
+		return err
+	}
+	changeset, err := b.aws.CreateChangeSet(ctx, project.Name, b.Region, body, configFilesTags(project))
+	if changeset != "" {
+		defer b.aws.DeleteChangeSet(ctx, changeset) // nolint:errcheck
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "didn't contain changes") {
+			return utils.PrintOperations(os.Stdout, nil)
+		}
+		return err
+	}
+	changes, err := b.aws.DescribeChangeSetChanges(ctx, changeset)
+	if err != nil {
+		return err
+	}
+	return utils.PrintOperations(os.Stdout, changeSetOperations(project.Name, changes))
+}
+
+// dryRunDown prints the resources compose down would delete
+func (b *ecsAPIService) dryRunDown(ctx context.Context, projectName string, options api.DownOptions) error {
+	resources, err := b.aws.ListStackResources(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	var operations []utils.Operation
+	for _, r := range resources {
+		operations = append(operations, utils.Operation{Action: "delete", Resource: r.Type, Name: r.LogicalID})
+	}
+	operations = append(operations, utils.Operation{Action: "delete", Resource: "stack", Name: projectName})
+	if options.Volumes {
+		filesystems, err := b.aws.ListFileSystems(ctx, map[string]string{
+			api.ProjectLabel: projectName,
+		})
+		if err != nil {
+			return err
+		}
+		for _, fs := range filesystems {
+			operations = append(operations, utils.Operation{Action: "delete", Resource: "file system", Name: fs.ID()})
+		}
+	}
+	return utils.PrintOperations(os.Stdout, operations)
+}
+
+func createStackOperations(name string, template *cloudformation.Template) []utils.Operation {
+	operations := []utils.Operation{{Action: "create", Resource: "stack", Name: name}}
+	var logicalIDs []string
+	for id := range template.Resources {
+		logicalIDs = append(logicalIDs, id)
+	}
+	sort.Strings(logicalIDs)
+	for _, id := range logicalIDs {
+		operations = append(operations, utils.Operation{
+			Action:   "create",
+			Resource: template.Resources[id].AWSCloudFormationType(),
+			Name:     id,
+		})
+	}
+	return operations
+}
+
+func changeSetOperations(name string, changes []stackChange) []utils.Operation {
+	operations := []utils.Operation{{Action: "update", Resource: "stack", Name: name}}
+	for _, c := range changes {
+		operations = append(operations, utils.Operation{
+			Action:   strings.ToLower(c.Action),
+			Resource: c.Type,
+			Name:     c.LogicalID,
+		})
+	}
+	return operations
+}
@@ -0,0 +1,53 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/utils"
+)
+
+// Images lists the images of the service tasks, identified by their digest
+func (b *ecsAPIService) Images(ctx context.Context, projectName string, options api.ImagesOptions) ([]api.ImageSummary, error) {
+	cluster, err := b.aws.GetStackClusterID(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+	servicesARN, err := b.aws.ListStackServices(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	images := []api.ImageSummary{}
+	for _, arn := range servicesARN {
+		service, err := b.aws.DescribeService(ctx, cluster, arn)
+		if err != nil {
+			return nil, err
+		}
+		if len(options.Services) > 0 && !utils.StringContains(options.Services, service.Name) {
+			continue
+		}
+		serviceImages, err := b.aws.DescribeServiceImages(ctx, cluster, projectName, service.Name)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, serviceImages...)
+	}
+	return images, nil
+}
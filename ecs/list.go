@@ -19,6 +19,7 @@ package ecs
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/docker/compose/v2/pkg/api"
 
@@ -117,3 +118,17 @@ func (b *ecsAPIService) checkServiceState(ctx context.Context, cluster string, s
 	}
 	return fmt.Errorf("%s", reason)
 }
+
+// ProjectConfigFiles returns the compose files of the projects, as tagged on
+// their stacks
+func (b *ecsAPIService) ProjectConfigFiles(ctx context.Context) (map[string][]string, error) {
+	values, err := b.aws.ListStackTags(ctx, api.ConfigFilesLabel)
+	if err != nil {
+		return nil, err
+	}
+	configFiles := make(map[string][]string, len(values))
+	for name, value := range values {
+		configFiles[name] = strings.Split(value, ",")
+	}
+	return configFiles, nil
+}
@@ -0,0 +1,51 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestProjectConfigFiles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	m.EXPECT().ListStackTags(gomock.Any(), api.ConfigFilesLabel).Return(map[string]string{
+		"demo": "/src/compose.yaml,/src/compose.prod.yaml",
+	}, nil)
+
+	backend := &ecsAPIService{aws: m}
+	configFiles, err := backend.ProjectConfigFiles(context.TODO())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, configFiles, map[string][]string{
+		"demo": {"/src/compose.yaml", "/src/compose.prod.yaml"},
+	})
+}
+
+func TestConfigFilesTags(t *testing.T) {
+	project := &types.Project{Name: "demo", ComposeFiles: []string{"/src/compose.yaml"}}
+	assert.DeepEqual(t, configFilesTags(project), map[string]string{api.ConfigFilesLabel: "/src/compose.yaml"})
+
+	project.ComposeFiles = nil
+	assert.Assert(t, configFilesTags(project) == nil)
+}
@@ -25,10 +25,11 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/compose-spec/compose-go/types"
-	"github.com/pkg/errors"
 	"github.com/sanathkr/go-yaml"
 
 	"github.com/docker/compose/v2/pkg/api"
+
+	"github.com/docker/compose-cli/utils"
 )
 
 func (e ecsLocalSimulation) Build(ctx context.Context, project *types.Project, options api.BuildOptions) error {
@@ -36,7 +37,17 @@ func (e ecsLocalSimulation) Build(ctx context.Context, project *types.Project, o
 }
 
 func (e ecsLocalSimulation) Push(ctx context.Context, project *types.Project, options api.PushOptions) error {
-	return e.compose.Push(ctx, project, options)
+	err := utils.PushWithRetry(ctx, func(ctx context.Context) error {
+		return e.compose.Push(ctx, project, options)
+	})
+	if err != nil {
+		return err
+	}
+	pushed, err := utils.PushedImages(ctx, e.moby, project, options)
+	if err != nil {
+		return err
+	}
+	return utils.PrintPushedImages(os.Stdout, pushed)
 }
 
 func (e ecsLocalSimulation) Pull(ctx context.Context, project *types.Project, options api.PullOptions) error {
@@ -176,7 +187,28 @@ func (e ecsLocalSimulation) List(ctx context.Context, opts api.ListOptions) ([]a
 	return e.compose.List(ctx, opts)
 }
 func (e ecsLocalSimulation) RunOneOffContainer(ctx context.Context, project *types.Project, opts api.RunOptions) (int, error) {
-	return 0, errors.Wrap(api.ErrNotImplemented, "use docker-compose run")
+	enhanced, err := e.enhanceForLocalSimulation(project)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := enhanced.GetService(opts.Service); err != nil {
+		return 0, err
+	}
+	for i, service := range enhanced.Services {
+		if service.Name != opts.Service {
+			continue
+		}
+		// the static addresses belong to the service containers, which may be
+		// running. The networks are copied, being shared with the project
+		// of the caller.
+		networks := make(map[string]*types.ServiceNetworkConfig, len(service.Networks))
+		for name, network := range service.Networks {
+			networks[name] = network
+		}
+		networks["credentials_network"] = nil
+		enhanced.Services[i].Networks = networks
+	}
+	return e.compose.RunOneOffContainer(ctx, enhanced, opts)
 }
 
 func (e ecsLocalSimulation) Remove(ctx context.Context, project *types.Project, options api.RemoveOptions) error {
@@ -0,0 +1,67 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"gotest.tools/v3/assert"
+)
+
+// fakeRunService records the project one-off containers are run with
+type fakeRunService struct {
+	api.Service
+	project *types.Project
+}
+
+func (f *fakeRunService) RunOneOffContainer(ctx context.Context, project *types.Project, opts api.RunOptions) (int, error) {
+	f.project = project
+	return 0, nil
+}
+
+func TestRunOneOffContainer(t *testing.T) {
+	network := &types.ServiceNetworkConfig{Aliases: []string{"api"}}
+	project := &types.Project{
+		Name:     "demo",
+		Networks: types.Networks{"default": {Name: "demo_default"}},
+		Services: types.Services{
+			{Name: "web", Networks: map[string]*types.ServiceNetworkConfig{"default": network}, Environment: types.MappingWithEquals{}},
+			{Name: "db", Networks: map[string]*types.ServiceNetworkConfig{"default": nil}, Environment: types.MappingWithEquals{}},
+		},
+	}
+	web := project.Services[0]
+
+	fake := &fakeRunService{}
+	e := ecsLocalSimulation{compose: fake}
+	_, err := e.RunOneOffContainer(context.Background(), project, api.RunOptions{Service: "web"})
+	assert.NilError(t, err)
+
+	run, err := fake.project.GetService("web")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, run.Networks, map[string]*types.ServiceNetworkConfig{"default": network, "credentials_network": nil})
+	// the service containers keep their static address
+	assert.Equal(t, web.Networks["credentials_network"].Ipv4Address, "169.254.170.3")
+	db, err := fake.project.GetService("db")
+	assert.NilError(t, err)
+	assert.Equal(t, db.Networks["credentials_network"].Ipv4Address, "169.254.170.4")
+
+	_, err = e.RunOneOffContainer(context.Background(), project, api.RunOptions{Service: "missing"})
+	assert.ErrorContains(t, err, "missing")
+}
@@ -31,8 +31,11 @@ func (b *ecsAPIService) Logs(ctx context.Context, projectName string, consumer a
 	if len(options.Services) > 0 {
 		consumer = utils.FilteredLogConsumer(consumer, options.Services)
 	}
-	err := b.aws.GetLogs(ctx, projectName, consumer.Log, options.Follow)
-	return err
+	since, until, err := utils.ParseLogTimeBounds(options)
+	if err != nil {
+		return err
+	}
+	return b.aws.GetLogs(ctx, projectName, consumer.Log, options.Follow, since, until)
 }
 
 func checkUnsupportedLogOptions(ctx context.Context, o api.LogOptions) error {
@@ -41,10 +44,8 @@ func checkUnsupportedLogOptions(ctx context.Context, o api.LogOptions) error {
 		toCheck, expected interface{}
 		option            string
 	}{
-		{o.Since, "", "since"},
 		{o.Tail, "all", "tail"},
 		{o.Timestamps, false, "timestamps"},
-		{o.Until, "", "until"},
 	}
 	for _, c := range checks {
 		errs = utils.CheckUnsupported(ctx, errs, c.toCheck, c.expected, "logs", c.option)
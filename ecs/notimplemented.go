@@ -21,6 +21,8 @@ import (
 
 	"github.com/compose-spec/compose-go/types"
 	"github.com/docker/compose/v2/pkg/api"
+
+	"github.com/docker/compose-cli/utils"
 )
 
 func (b *ecsAPIService) Build(ctx context.Context, project *types.Project, options api.BuildOptions) error {
@@ -36,35 +38,15 @@ func (b *ecsAPIService) Pull(ctx context.Context, project *types.Project, option
 }
 
 func (b *ecsAPIService) Create(ctx context.Context, project *types.Project, opts api.CreateOptions) error {
-	return api.ErrNotImplemented
-}
-
-func (b *ecsAPIService) Start(ctx context.Context, project *types.Project, options api.StartOptions) error {
-	return api.ErrNotImplemented
-}
-
-func (b *ecsAPIService) Restart(ctx context.Context, project *types.Project, options api.RestartOptions) error {
-	return api.ErrNotImplemented
-}
-
-func (b *ecsAPIService) Stop(ctx context.Context, project *types.Project, options api.StopOptions) error {
-	return api.ErrNotImplemented
+	return utils.UnsupportedCommand(ctx, "create", `ECS services are created running, the application can be deployed with "compose up"`)
 }
 
 func (b *ecsAPIService) Pause(ctx context.Context, project string, options api.PauseOptions) error {
-	return api.ErrNotImplemented
+	return utils.UnsupportedCommand(ctx, "pause", `ECS tasks can't be frozen, the application can be removed with "compose down"`)
 }
 
 func (b *ecsAPIService) UnPause(ctx context.Context, project string, options api.PauseOptions) error {
-	return api.ErrNotImplemented
-}
-
-func (b *ecsAPIService) Events(ctx context.Context, project string, options api.EventsOptions) error {
-	return api.ErrNotImplemented
-}
-
-func (b *ecsAPIService) Port(ctx context.Context, project string, service string, port int, options api.PortOptions) (string, int, error) {
-	return "", 0, api.ErrNotImplemented
+	return utils.UnsupportedCommand(ctx, "unpause", `ECS tasks can't be frozen`)
 }
 
 func (b *ecsAPIService) Copy(ctx context.Context, project *types.Project, options api.CopyOptions) error {
@@ -79,18 +61,10 @@ func (b *ecsAPIService) Remove(ctx context.Context, project *types.Project, opti
 	return api.ErrNotImplemented
 }
 
-func (b *ecsAPIService) Images(ctx context.Context, projectName string, options api.ImagesOptions) ([]api.ImageSummary, error) {
-	return nil, api.ErrNotImplemented
-}
-
-func (b *ecsAPIService) Top(ctx context.Context, projectName string, services []string) ([]api.ContainerProcSummary, error) {
-	return nil, api.ErrNotImplemented
-}
-
 func (b *ecsAPIService) Exec(ctx context.Context, project string, opts api.RunOptions) (int, error) {
 	return 0, api.ErrNotImplemented
 }
 
 func (b *ecsAPIService) Kill(ctx context.Context, project *types.Project, options api.KillOptions) error {
-	return api.ErrNotImplemented
+	return utils.UnsupportedCommand(ctx, "kill", `ECS tasks can't be sent signals, the application can be removed with "compose down"`)
 }
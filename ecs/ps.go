@@ -63,6 +63,31 @@ func (b *ecsAPIService) Ps(ctx context.Context, projectName string, options api.
 	return summary, nil
 }
 
+// Top describes the tasks of the services, ECS doesn't expose their processes
+func (b *ecsAPIService) Top(ctx context.Context, projectName string, services []string) ([]api.ContainerProcSummary, error) {
+	tasks, err := b.Ps(ctx, projectName, api.PsOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return utils.ContainersTop(tasks, services), nil
+}
+
+// Events reports the changes of the service tasks, as ECS has no events API
+func (b *ecsAPIService) Events(ctx context.Context, projectName string, options api.EventsOptions) error {
+	return utils.PollEvents(ctx, utils.EventsPollInterval, func(ctx context.Context) ([]api.ContainerSummary, error) {
+		return b.Ps(ctx, projectName, api.PsOptions{})
+	}, options)
+}
+
+// Port returns the load balancer address publishing the service port
+func (b *ecsAPIService) Port(ctx context.Context, projectName string, service string, port int, options api.PortOptions) (string, int, error) {
+	tasks, err := b.Ps(ctx, projectName, api.PsOptions{})
+	if err != nil {
+		return "", 0, err
+	}
+	return utils.PublishedPort(tasks, service, port, options)
+}
+
 func checkUnsupportedPsOptions(ctx context.Context, o api.PsOptions) error {
 	return utils.CheckUnsupported(ctx, nil, o.All, false, "ps", "all")
 }
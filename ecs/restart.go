@@ -0,0 +1,94 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/compose"
+	"github.com/docker/compose/v2/pkg/progress"
+	utils2 "github.com/docker/compose/v2/pkg/utils"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+// Restart replaces the tasks of the services with new ones, dependencies
+// first. Tasks are stopped with their services' stop_grace_period, as the
+// timeout can't be set per deployment, other timeouts than the default one
+// being rejected.
+func (b *ecsAPIService) Restart(ctx context.Context, project *types.Project, options api.RestartOptions) error {
+	if err := checkUnsupportedRestartOptions(ctx, options); err != nil {
+		return err
+	}
+	return progress.Run(ctx, func(ctx context.Context) error {
+		return b.restart(ctx, project, options)
+	})
+}
+
+func checkUnsupportedRestartOptions(ctx context.Context, o api.RestartOptions) error {
+	return utils.CheckUnsupportedRestartTimeout(ctx, nil, o.Timeout)
+}
+
+func (b *ecsAPIService) restart(ctx context.Context, project *types.Project, options api.RestartOptions) error {
+	cluster, arns, err := b.serviceARNs(ctx, project.Name)
+	if err != nil {
+		return err
+	}
+
+	services := options.Services
+	if len(services) == 0 {
+		services = project.ServiceNames()
+	}
+	w := progress.ContextWriter(ctx)
+	return compose.InDependencyOrder(ctx, project, func(ctx context.Context, service string) error {
+		arn, ok := arns[service]
+		if !ok || !utils2.StringContains(services, service) {
+			return nil
+		}
+		w.Event(progress.RestartingEvent(service))
+		if err := b.aws.RestartService(ctx, cluster, arn); err != nil {
+			w.Event(progress.ErrorEvent(service))
+			return err
+		}
+		w.Event(progress.StartedEvent(service))
+		return nil
+	})
+}
+
+// serviceARNs returns the cluster of the stack and the ARNs of its ECS
+// services by compose service name
+func (b *ecsAPIService) serviceARNs(ctx context.Context, project string) (string, map[string]string, error) {
+	cluster, err := b.aws.GetStackClusterID(ctx, project)
+	if err != nil {
+		return "", nil, err
+	}
+	servicesARN, err := b.aws.ListStackServices(ctx, project)
+	if err != nil {
+		return "", nil, err
+	}
+	arns := map[string]string{}
+	for _, arn := range servicesARN {
+		service, err := b.aws.DescribeService(ctx, cluster, arn)
+		if err != nil {
+			return "", nil, err
+		}
+		arns[service.Name] = arn
+	}
+	return cluster, arns, nil
+}
@@ -0,0 +1,73 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/config"
+	"github.com/docker/compose-cli/api/context/store"
+)
+
+func TestRestartInDependencyOrder(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  web:
+    image: nginx
+    depends_on:
+      - db
+  db:
+    image: postgres
+  cache:
+    image: redis
+`)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	m.EXPECT().GetStackClusterID(gomock.Any(), project.Name).Return("cluster", nil)
+	m.EXPECT().ListStackServices(gomock.Any(), project.Name).Return([]string{"arn-web", "arn-db", "arn-cache"}, nil)
+	for _, name := range []string{"web", "db", "cache"} {
+		m.EXPECT().DescribeService(gomock.Any(), "cluster", "arn-"+name).Return(api.ServiceStatus{Name: name}, nil)
+	}
+	gomock.InOrder(
+		m.EXPECT().RestartService(gomock.Any(), "cluster", "arn-db").Return(nil),
+		m.EXPECT().RestartService(gomock.Any(), "cluster", "arn-web").Return(nil),
+	)
+
+	backend := &ecsAPIService{aws: m}
+	err := backend.Restart(context.TODO(), project, api.RestartOptions{Services: []string{"web", "db"}})
+	assert.NilError(t, err)
+}
+
+func TestRestartUnsupportedOptions(t *testing.T) {
+	ctx := context.WithValue(context.Background(), config.ContextTypeKey, store.EcsContextType)
+	err := checkUnsupportedRestartOptions(ctx, api.RestartOptions{Services: []string{"web"}})
+	assert.NilError(t, err)
+	// the compose library always sets the default timeout
+	defaultTimeout := 10 * time.Second
+	err = checkUnsupportedRestartOptions(ctx, api.RestartOptions{Services: []string{"web"}, Timeout: &defaultTimeout})
+	assert.NilError(t, err)
+	timeout := 5 * time.Second
+	err = checkUnsupportedRestartOptions(ctx, api.RestartOptions{Timeout: &timeout})
+	assert.ErrorContains(t, err, `option "restart --timeout" on context type ECS`)
+}
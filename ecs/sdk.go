@@ -21,6 +21,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -59,6 +60,7 @@ import (
 
 	"github.com/docker/compose-cli/api/secrets"
 	"github.com/docker/compose-cli/internal"
+	"github.com/docker/compose-cli/utils"
 )
 
 type sdk struct {
@@ -328,7 +330,7 @@ func (s sdk) withTemplate(ctx context.Context, name string, template []byte, reg
 	return fn(nil, aws.String(upload.Location))
 }
 
-func (s sdk) CreateStack(ctx context.Context, name string, region string, template []byte) error {
+func (s sdk) CreateStack(ctx context.Context, name string, region string, template []byte, tags map[string]string) error {
 	logrus.Debug("Create CloudFormation stack")
 
 	stackID, err := s.withTemplate(ctx, name, template, region, func(body *string, url *string) (string, error) {
@@ -341,12 +343,7 @@ func (s sdk) CreateStack(ctx context.Context, name string, region string, templa
 			Capabilities: []*string{
 				aws.String(cloudformation.CapabilityCapabilityIam),
 			},
-			Tags: []*cloudformation.Tag{
-				{
-					Key:   aws.String(api.ProjectLabel),
-					Value: aws.String(name),
-				},
-			},
+			Tags: stackTags(name, tags),
 		})
 		if err != nil {
 			return "", err
@@ -357,7 +354,7 @@ func (s sdk) CreateStack(ctx context.Context, name string, region string, templa
 	return err
 }
 
-func (s sdk) CreateChangeSet(ctx context.Context, name string, region string, template []byte) (string, error) {
+func (s sdk) CreateChangeSet(ctx context.Context, name string, region string, template []byte, tags map[string]string) (string, error) {
 	logrus.Debug("Create CloudFormation Changeset")
 	update := fmt.Sprintf("Update%s", time.Now().Format("2006-01-02-15-04-05"))
 
@@ -371,6 +368,7 @@ func (s sdk) CreateChangeSet(ctx context.Context, name string, region string, te
 			Capabilities: []*string{
 				aws.String(cloudformation.CapabilityCapabilityIam),
 			},
+			Tags: stackTags(name, tags),
 		})
 		if err != nil {
 			return "", err
@@ -416,6 +414,49 @@ func (s sdk) UpdateStack(ctx context.Context, changeset string) error {
 	return err
 }
 
+// stackChange is a resource change of a changeset
+type stackChange struct {
+	Action    string
+	LogicalID string
+	Type      string
+}
+
+// DescribeChangeSetChanges returns the resource changes of the changeset
+func (s sdk) DescribeChangeSetChanges(ctx context.Context, changeset string) ([]stackChange, error) {
+	var token *string
+	var changes []stackChange
+	for {
+		desc, err := s.CF.DescribeChangeSetWithContext(ctx, &cloudformation.DescribeChangeSetInput{
+			ChangeSetName: aws.String(changeset),
+			NextToken:     token,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range desc.Changes {
+			if c.ResourceChange == nil {
+				continue
+			}
+			changes = append(changes, stackChange{
+				Action:    aws.StringValue(c.ResourceChange.Action),
+				LogicalID: aws.StringValue(c.ResourceChange.LogicalResourceId),
+				Type:      aws.StringValue(c.ResourceChange.ResourceType),
+			})
+		}
+		if desc.NextToken == nil {
+			return changes, nil
+		}
+		token = desc.NextToken
+	}
+}
+
+func (s sdk) DeleteChangeSet(ctx context.Context, changeset string) error {
+	_, err := s.CF.DeleteChangeSetWithContext(ctx, &cloudformation.DeleteChangeSetInput{
+		ChangeSetName: aws.String(changeset),
+	})
+	return err
+}
+
 const (
 	stackCreate = iota
 	stackUpdate
@@ -484,6 +525,55 @@ func (s sdk) ListStacks(ctx context.Context) ([]api.Stack, error) {
 	}
 }
 
+// ListStackTags returns the value of the tag with the given key on the
+// compose stacks, by stack name
+func (s sdk) ListStackTags(ctx context.Context, key string) (map[string]string, error) {
+	var token *string
+	values := map[string]string{}
+	for {
+		response, err := s.CF.DescribeStacksWithContext(ctx, &cloudformation.DescribeStacksInput{
+			NextToken: token,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, stack := range response.Stacks {
+			for _, t := range stack.Tags {
+				if aws.StringValue(t.Key) == key {
+					values[aws.StringValue(stack.StackName)] = aws.StringValue(t.Value)
+				}
+			}
+		}
+		if token == response.NextToken {
+			return values, nil
+		}
+		token = response.NextToken
+	}
+}
+
+// stackTags returns the tags of the stack, the project label and the given
+// ones sorted by key
+func stackTags(name string, tags map[string]string) []*cloudformation.Tag {
+	stackTags := []*cloudformation.Tag{
+		{
+			Key:   aws.String(api.ProjectLabel),
+			Value: aws.String(name),
+		},
+	}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		stackTags = append(stackTags, &cloudformation.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(tags[k]),
+		})
+	}
+	return stackTags
+}
+
 func (s sdk) GetStackClusterID(ctx context.Context, stack string) (string, error) {
 	// Note: could use DescribeStackResource but we only can detect `does not exist` case by matching string error message
 	var token *string
@@ -807,9 +897,15 @@ func (s sdk) DeleteSecret(ctx context.Context, id string, recover bool) error {
 	return err
 }
 
-func (s sdk) GetLogs(ctx context.Context, name string, consumer func(container string, service string, message string), follow bool) error {
+func (s sdk) GetLogs(ctx context.Context, name string, consumer func(container string, service string, message string), follow bool, since time.Time, until time.Time) error {
 	logGroup := fmt.Sprintf("/docker-compose/%s", name)
-	var startTime int64
+	var startTime, endTime *int64
+	if !since.IsZero() {
+		startTime = aws.Int64(since.UnixMilli())
+	}
+	if !until.IsZero() {
+		endTime = aws.Int64(until.UnixMilli())
+	}
 	for {
 		select {
 		case <-ctx.Done():
@@ -821,7 +917,8 @@ func (s sdk) GetLogs(ctx context.Context, name string, consumer func(container s
 				events, err := s.CW.FilterLogEvents(&cloudwatchlogs.FilterLogEventsInput{
 					LogGroupName: aws.String(logGroup),
 					NextToken:    token,
-					StartTime:    aws.Int64(startTime),
+					StartTime:    startTime,
+					EndTime:      endTime,
 				})
 				if err != nil {
 					return err
@@ -835,17 +932,49 @@ func (s sdk) GetLogs(ctx context.Context, name string, consumer func(container s
 				for _, event := range events.Events {
 					p := strings.Split(aws.StringValue(event.LogStreamName), "/")
 					consumer(p[1], p[2], aws.StringValue(event.Message))
-					startTime = *event.IngestionTime
+					startTime = event.IngestionTime
 				}
 			}
 		}
-		if !follow {
+		if !follow || (!until.IsZero() && time.Now().After(until)) {
 			return nil
 		}
 		time.Sleep(500 * time.Millisecond)
 	}
 }
 
+func (s sdk) RestartService(ctx context.Context, cluster string, arn string) error {
+	logrus.Debug("Restart service ", arn)
+	_, err := s.ECS.UpdateServiceWithContext(ctx, &ecs.UpdateServiceInput{
+		Cluster:            aws.String(cluster),
+		Service:            aws.String(arn),
+		ForceNewDeployment: aws.Bool(true),
+	})
+	if err != nil {
+		return err
+	}
+	return s.ECS.WaitUntilServicesStableWithContext(ctx, &ecs.DescribeServicesInput{
+		Cluster:  aws.String(cluster),
+		Services: []*string{aws.String(arn)},
+	})
+}
+
+func (s sdk) ScaleService(ctx context.Context, cluster string, arn string, replicas int) error {
+	logrus.Debugf("Scale service %s to %d", arn, replicas)
+	_, err := s.ECS.UpdateServiceWithContext(ctx, &ecs.UpdateServiceInput{
+		Cluster:      aws.String(cluster),
+		Service:      aws.String(arn),
+		DesiredCount: aws.Int64(int64(replicas)),
+	})
+	if err != nil {
+		return err
+	}
+	return s.ECS.WaitUntilServicesStableWithContext(ctx, &ecs.DescribeServicesInput{
+		Cluster:  aws.String(cluster),
+		Services: []*string{aws.String(arn)},
+	})
+}
+
 func (s sdk) DescribeService(ctx context.Context, cluster string, arn string) (api.ServiceStatus, error) {
 	services, err := s.ECS.DescribeServicesWithContext(ctx, &ecs.DescribeServicesInput{
 		Cluster:  aws.String(cluster),
@@ -890,6 +1019,63 @@ func (s sdk) DescribeService(ctx context.Context, cluster string, arn string) (a
 
 func (s sdk) DescribeServiceTasks(ctx context.Context, cluster string, project string, service string) ([]api.ContainerSummary, error) {
 	var summary []api.ContainerSummary
+	err := s.describeServiceTasks(ctx, cluster, project, service, func(t *ecs.Task) error {
+		var project string
+		var service string
+		for _, tag := range t.Tags {
+			switch aws.StringValue(tag.Key) {
+			case api.ProjectLabel:
+				project = aws.StringValue(tag.Value)
+			case api.ServiceLabel:
+				service = aws.StringValue(tag.Value)
+			}
+		}
+
+		id, err := arn.Parse(aws.StringValue(t.TaskArn))
+		if err != nil {
+			return err
+		}
+
+		summary = append(summary, api.ContainerSummary{
+			ID:      id.String(),
+			Name:    id.Resource,
+			Project: project,
+			Service: service,
+			//nolint:staticcheck // Preserving for compatibility
+			State: strings.Title(strings.ToLower(aws.StringValue(t.LastStatus))),
+		})
+		return nil
+	})
+	return summary, err
+}
+
+func (s sdk) DescribeServiceImages(ctx context.Context, cluster string, project string, service string) ([]api.ImageSummary, error) {
+	var images []api.ImageSummary
+	err := s.describeServiceTasks(ctx, cluster, project, service, func(t *ecs.Task) error {
+		id, err := arn.Parse(aws.StringValue(t.TaskArn))
+		if err != nil {
+			return err
+		}
+		for _, c := range t.Containers {
+			// skip sidecars, such as the secrets init container
+			if aws.StringValue(c.Name) != service {
+				continue
+			}
+			repository, tag := utils.SplitImageReference(aws.StringValue(c.Image))
+			images = append(images, api.ImageSummary{
+				ID:            aws.StringValue(c.ImageDigest),
+				ContainerName: id.Resource,
+				Repository:    repository,
+				Tag:           tag,
+			})
+		}
+		return nil
+	})
+	return images, err
+}
+
+// describeServiceTasks calls fn for each task of the service
+func (s sdk) describeServiceTasks(ctx context.Context, cluster string, project string, service string, fn func(t *ecs.Task) error) error {
 	familly := fmt.Sprintf("%s-%s", project, service)
 	var token *string
 	for {
@@ -901,7 +1087,7 @@ func (s sdk) DescribeServiceTasks(ctx context.Context, cluster string, project s
 			NextToken:  token,
 		})
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		if len(list.TaskArns) == 0 {
@@ -913,34 +1099,13 @@ func (s sdk) DescribeServiceTasks(ctx context.Context, cluster string, project s
 			Tasks:   list.TaskArns,
 		})
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		for _, t := range tasks.Tasks {
-			var project string
-			var service string
-			for _, tag := range t.Tags {
-				switch aws.StringValue(tag.Key) {
-				case api.ProjectLabel:
-					project = aws.StringValue(tag.Value)
-				case api.ServiceLabel:
-					service = aws.StringValue(tag.Value)
-				}
-			}
-
-			id, err := arn.Parse(aws.StringValue(t.TaskArn))
-			if err != nil {
-				return nil, err
+			if err := fn(t); err != nil {
+				return err
 			}
-
-			summary = append(summary, api.ContainerSummary{
-				ID:      id.String(),
-				Name:    id.Resource,
-				Project: project,
-				Service: service,
-				//nolint:staticcheck // Preserving for compatibility
-				State: strings.Title(strings.ToLower(aws.StringValue(t.LastStatus))),
-			})
 		}
 
 		if list.NextToken == token {
@@ -949,7 +1114,7 @@ func (s sdk) DescribeServiceTasks(ctx context.Context, cluster string, project s
 		token = list.NextToken
 	}
 
-	return summary, nil
+	return nil
 }
 
 func (s sdk) getURLWithPortMapping(ctx context.Context, targetGroupArns []string) ([]api.PortPublisher, error) {
@@ -0,0 +1,109 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/compose"
+	"github.com/docker/compose/v2/pkg/progress"
+	utils2 "github.com/docker/compose/v2/pkg/utils"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+// Start scales the services of a deployed application back to their
+// replicas, dependencies first. The services must have been created by
+// compose up.
+func (b *ecsAPIService) Start(ctx context.Context, project *types.Project, options api.StartOptions) error {
+	return progress.Run(ctx, func(ctx context.Context) error {
+		return b.start(ctx, project)
+	})
+}
+
+func (b *ecsAPIService) start(ctx context.Context, project *types.Project) error {
+	cluster, arns, err := b.serviceARNs(ctx, project.Name)
+	if err != nil {
+		return err
+	}
+	w := progress.ContextWriter(ctx)
+	return compose.InDependencyOrder(ctx, project, func(ctx context.Context, name string) error {
+		arn, ok := arns[name]
+		if !ok {
+			return nil
+		}
+		service, err := project.GetService(name)
+		if err != nil {
+			return err
+		}
+		replicas := 1
+		if service.Deploy != nil && service.Deploy.Replicas != nil {
+			replicas = int(*service.Deploy.Replicas)
+		}
+		w.Event(progress.StartingEvent(name))
+		if err := b.aws.ScaleService(ctx, cluster, arn, replicas); err != nil {
+			w.Event(progress.ErrorEvent(name))
+			return err
+		}
+		w.Event(progress.StartedEvent(name))
+		return nil
+	})
+}
+
+// Stop scales the services down to no task, dependent services first, so
+// the application can be started again without being redeployed. Tasks are
+// stopped with their services' stop_grace_period, as the timeout can't be
+// set per deployment, the timeout option being rejected.
+func (b *ecsAPIService) Stop(ctx context.Context, project *types.Project, options api.StopOptions) error {
+	if err := checkUnsupportedStopOptions(ctx, options); err != nil {
+		return err
+	}
+	return progress.Run(ctx, func(ctx context.Context) error {
+		return b.stop(ctx, project, options)
+	})
+}
+
+func checkUnsupportedStopOptions(ctx context.Context, o api.StopOptions) error {
+	return utils.CheckUnsupported(ctx, nil, o.Timeout, nil, "stop", "timeout")
+}
+
+func (b *ecsAPIService) stop(ctx context.Context, project *types.Project, options api.StopOptions) error {
+	cluster, arns, err := b.serviceARNs(ctx, project.Name)
+	if err != nil {
+		return err
+	}
+	services := options.Services
+	if len(services) == 0 {
+		services = project.ServiceNames()
+	}
+	w := progress.ContextWriter(ctx)
+	return compose.InReverseDependencyOrder(ctx, project, func(ctx context.Context, name string) error {
+		arn, ok := arns[name]
+		if !ok || !utils2.StringContains(services, name) {
+			return nil
+		}
+		w.Event(progress.StoppingEvent(name))
+		if err := b.aws.ScaleService(ctx, cluster, arn, 0); err != nil {
+			w.Event(progress.ErrorEvent(name))
+			return err
+		}
+		w.Event(progress.StoppedEvent(name))
+		return nil
+	})
+}
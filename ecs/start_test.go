@@ -0,0 +1,88 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/config"
+	"github.com/docker/compose-cli/api/context/store"
+)
+
+const startStopConfig = `
+services:
+  web:
+    image: nginx
+    depends_on:
+      - db
+    deploy:
+      replicas: 3
+  db:
+    image: postgres
+`
+
+func expectServiceARNs(m *MockAPIMockRecorder, project string) {
+	m.GetStackClusterID(gomock.Any(), project).Return("cluster", nil)
+	m.ListStackServices(gomock.Any(), project).Return([]string{"arn-web", "arn-db"}, nil)
+	for _, name := range []string{"web", "db"} {
+		m.DescribeService(gomock.Any(), "cluster", "arn-"+name).Return(api.ServiceStatus{Name: name}, nil)
+	}
+}
+
+func TestStartInDependencyOrder(t *testing.T) {
+	project := loadConfig(t, startStopConfig)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	expectServiceARNs(m.EXPECT(), project.Name)
+	gomock.InOrder(
+		m.EXPECT().ScaleService(gomock.Any(), "cluster", "arn-db", 1).Return(nil),
+		m.EXPECT().ScaleService(gomock.Any(), "cluster", "arn-web", 3).Return(nil),
+	)
+
+	backend := &ecsAPIService{aws: m}
+	err := backend.Start(context.TODO(), project, api.StartOptions{})
+	assert.NilError(t, err)
+}
+
+func TestStopSelectedServices(t *testing.T) {
+	project := loadConfig(t, startStopConfig)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	expectServiceARNs(m.EXPECT(), project.Name)
+	m.EXPECT().ScaleService(gomock.Any(), "cluster", "arn-web", 0).Return(nil)
+
+	backend := &ecsAPIService{aws: m}
+	err := backend.Stop(context.TODO(), project, api.StopOptions{Services: []string{"web"}})
+	assert.NilError(t, err)
+}
+
+func TestStopUnsupportedOptions(t *testing.T) {
+	ctx := context.WithValue(context.Background(), config.ContextTypeKey, store.EcsContextType)
+	err := checkUnsupportedStopOptions(ctx, api.StopOptions{Services: []string{"web"}})
+	assert.NilError(t, err)
+	timeout := 5 * time.Second
+	err = checkUnsupportedStopOptions(ctx, api.StopOptions{Timeout: &timeout})
+	assert.ErrorContains(t, err, `option "stop --timeout" on context type ECS`)
+}
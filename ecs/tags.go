@@ -17,6 +17,8 @@
 package ecs
 
 import (
+	"strings"
+
 	"github.com/awslabs/goformation/v4/cloudformation/tags"
 	"github.com/compose-spec/compose-go/types"
 	"github.com/docker/compose/v2/pkg/api"
@@ -56,3 +58,16 @@ func networkTags(project *types.Project, net types.NetworkConfig) []tags.Tag {
 		},
 	}
 }
+
+// maxStackTagValueLength is the maximum length of CloudFormation tag values
+const maxStackTagValueLength = 256
+
+// configFilesTags returns the stack tag recording the compose files the
+// project is deployed from, if they fit in a tag value
+func configFilesTags(project *types.Project) map[string]string {
+	value := strings.Join(project.ComposeFiles, ",")
+	if value == "" || len(value) > maxStackTagValueLength {
+		return nil
+	}
+	return map[string]string{api.ConfigFilesLabel: value}
+}
@@ -35,6 +35,9 @@ func (b *ecsAPIService) Up(ctx context.Context, project *types.Project, options
 	if err := checkUnsupportedUpOptions(ctx, options); err != nil {
 		return err
 	}
+	if utils.IsDryRun(ctx) {
+		return b.dryRunUp(ctx, project)
+	}
 	return progress.Run(ctx, func(ctx context.Context) error {
 		return b.up(ctx, project, options)
 	})
@@ -47,6 +50,7 @@ func (b *ecsAPIService) up(ctx context.Context, project *types.Project, options
 		return err
 	}
 
+	project = utils.KeepDisabledDependencies(project)
 	template, err := b.Convert(ctx, project, api.ConvertOptions{
 		Format: "yaml",
 	})
@@ -71,7 +75,7 @@ func (b *ecsAPIService) up(ctx context.Context, project *types.Project, options
 	operation := stackCreate
 	if update {
 		operation = stackUpdate
-		changeset, err := b.aws.CreateChangeSet(ctx, project.Name, b.Region, template)
+		changeset, err := b.aws.CreateChangeSet(ctx, project.Name, b.Region, template, configFilesTags(project))
 		if err != nil {
 			return err
 		}
@@ -80,11 +84,14 @@ func (b *ecsAPIService) up(ctx context.Context, project *types.Project, options
 			return err
 		}
 	} else {
-		err = b.aws.CreateStack(ctx, project.Name, b.Region, template)
+		err = b.aws.CreateStack(ctx, project.Name, b.Region, template, configFilesTags(project))
 		if err != nil {
 			return err
 		}
 	}
+	if options.Start.Wait {
+		return b.WaitStackCompletion(ctx, project.Name, operation, previousEvents...)
+	}
 	if options.Start.Attach == nil {
 		return nil
 	}
@@ -112,6 +119,7 @@ func checkUnsupportedUpOptions(ctx context.Context, o api.UpOptions) error {
 		{o.Create.Recreate, api.RecreateDiverged, "force-recreate"},
 		{o.Create.RecreateDependencies, api.RecreateDiverged, "always-recreate-deps"},
 		{len(o.Start.AttachTo), 0, "attach-dependencies"},
+		{o.Start.CascadeStop, false, "abort-on-container-exit"},
 		{len(o.Start.ExitCodeFrom), 0, "exit-code-from"},
 		{o.Create.Timeout, nil, "timeout"},
 	}
@@ -26,9 +26,18 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/docker/compose/v2/pkg/progress"
 	"github.com/iancoleman/strcase"
+
+	"github.com/docker/compose-cli/api/phase"
 )
 
-func (b *ecsAPIService) WaitStackCompletion(ctx context.Context, name string, operation int, ignored ...string) error { //nolint:gocyclo
+func (b *ecsAPIService) WaitStackCompletion(ctx context.Context, name string, operation int, ignored ...string) error {
+	end := phase.Start(ctx, phase.StackWait)
+	err := b.waitStackCompletion(ctx, name, operation, ignored...)
+	end(err)
+	return err
+}
+
+func (b *ecsAPIService) waitStackCompletion(ctx context.Context, name string, operation int, ignored ...string) error { //nolint:gocyclo
 	knownEvents := map[string]struct{}{}
 	for _, id := range ignored {
 		knownEvents[id] = struct{}{}
@@ -20,11 +20,13 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -34,6 +36,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -42,6 +45,10 @@ import (
 	"k8s.io/client-go/transport/spdy"
 )
 
+// restartedAtAnnotation is set on the pod templates of restarted deployments,
+// as kubectl does
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
 // KubeClient API to access kube objects
 type KubeClient struct {
 	client    *kubernetes.Clientset
@@ -103,12 +110,52 @@ func (kc KubeClient) GetPod(ctx context.Context, projectName, serviceName string
 	return nil, nil
 }
 
-// Exec executes a command in a container
+// Exec executes a command in a container, of the service pod at the options
+// index
 func (kc KubeClient) Exec(ctx context.Context, projectName string, opts api.RunOptions) error {
-	pod, err := kc.GetPod(ctx, projectName, opts.Service)
-	if err != nil || pod == nil {
+	pods, err := kc.GetServicePods(ctx, projectName, opts.Service)
+	if err != nil {
 		return err
 	}
+	var pod *corev1.Pod
+	if len(pods) == 0 {
+		if pod, err = kc.GetPod(ctx, projectName, opts.Service); err != nil || pod == nil {
+			return err
+		}
+	} else {
+		index := opts.Index
+		if index == 0 {
+			index = 1
+		}
+		if index > len(pods) {
+			return fmt.Errorf("service %q has no container at index %d", opts.Service, index)
+		}
+		pod = &pods[index-1]
+	}
+	return kc.exec(*pod, execCommand(opts), remotecommand.StreamOptions{
+		Stdin:  opts.Stdin,
+		Stdout: opts.Stdout,
+		Stderr: opts.Stdout,
+		Tty:    opts.Tty,
+	})
+}
+
+// execCommand returns the exec command, run with the options environment
+// variables and in their working directory, as the exec API has no such
+// settings.
+func execCommand(opts api.RunOptions) []string {
+	command := opts.Command
+	if len(opts.Environment) > 0 {
+		command = append(append([]string{"env"}, opts.Environment...), command...)
+	}
+	if opts.WorkingDir != "" {
+		command = append([]string{"sh", "-c", `cd "$0" && exec "$@"`, opts.WorkingDir}, command...)
+	}
+	return command
+}
+
+// exec runs the command in the first container of the pod
+func (kc KubeClient) exec(pod corev1.Pod, command []string, streams remotecommand.StreamOptions) error {
 	if len(pod.Spec.Containers) == 0 {
 		return fmt.Errorf("no containers running in pod %s", pod.Name)
 	}
@@ -124,15 +171,11 @@ func (kc KubeClient) Exec(ctx context.Context, projectName string, opts api.RunO
 
 	option := &corev1.PodExecOptions{
 		Container: containerName,
-		Command:   opts.Command,
-		Stdin:     true,
-		Stdout:    true,
-		Stderr:    true,
-		TTY:       opts.Tty,
-	}
-
-	if opts.Stdin == nil {
-		option.Stdin = false
+		Command:   command,
+		Stdin:     streams.Stdin != nil,
+		Stdout:    streams.Stdout != nil,
+		Stderr:    streams.Stderr != nil,
+		TTY:       streams.Tty,
 	}
 
 	scheme := runtime.NewScheme()
@@ -146,12 +189,41 @@ func (kc KubeClient) Exec(ctx context.Context, projectName string, opts api.RunO
 	if err != nil {
 		return err
 	}
-	return exec.Stream(remotecommand.StreamOptions{
-		Stdin:  opts.Stdin,
-		Stdout: opts.Stdout,
-		Stderr: opts.Stdout,
-		Tty:    opts.Tty,
+	return exec.Stream(streams)
+}
+
+// KillPod sends the signal, e.g. SIGUSR1 or 10, to the main process of the
+// first container of the pod
+func (kc KubeClient) KillPod(pod corev1.Pod, signal string) error {
+	var stderr bytes.Buffer
+	err := kc.exec(pod, killCommand(signal), remotecommand.StreamOptions{
+		Stdout: io.Discard,
+		Stderr: &stderr,
 	})
+	return execError(pod, err, stderr)
+}
+
+func killCommand(signal string) []string {
+	signal = strings.TrimPrefix(strings.ToUpper(signal), "SIG")
+	if signal == "" {
+		signal = "KILL"
+	}
+	if _, err := strconv.Atoi(signal); err == nil {
+		return []string{"kill", "-" + signal, "1"}
+	}
+	return []string{"kill", "-s", signal, "1"}
+}
+
+// execError adds the command error output to the error of a command run in
+// the pod
+func execError(pod corev1.Pod, err error, stderr bytes.Buffer) error {
+	if err == nil {
+		return nil
+	}
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return fmt.Errorf("in pod %s: %s: %w", pod.Name, msg, err)
+	}
+	return fmt.Errorf("in pod %s: %w", pod.Name, err)
 }
 
 // GetContainers get containers for a given compose project
@@ -206,20 +278,125 @@ func (kc KubeClient) GetContainers(ctx context.Context, projectName string, all
 	return result, nil
 }
 
-// GetLogs retrieves pod logs
-func (kc *KubeClient) GetLogs(ctx context.Context, projectName string, consumer api.LogConsumer, follow bool) error {
+// RestartDeployment replaces the pods of a service deployment with new ones,
+// the same way as `kubectl rollout restart`
+func (kc KubeClient) RestartDeployment(ctx context.Context, projectName, serviceName string) error {
+	deployments, err := kc.client.AppsV1().Deployments(kc.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s=%s", api.ProjectLabel, projectName, api.ServiceLabel, serviceName),
+	})
+	if err != nil {
+		return err
+	}
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`,
+		restartedAtAnnotation, time.Now().Format(time.RFC3339))
+	for _, d := range deployments.Items {
+		_, err := kc.client.AppsV1().Deployments(kc.namespace).Patch(ctx, d.Name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ScaleDeployment sets the number of replicas of the service deployment
+func (kc KubeClient) ScaleDeployment(ctx context.Context, projectName, serviceName string, replicas int) error {
+	deployments, err := kc.client.AppsV1().Deployments(kc.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s=%s", api.ProjectLabel, projectName, api.ServiceLabel, serviceName),
+	})
+	if err != nil {
+		return err
+	}
+	if len(deployments.Items) == 0 {
+		return fmt.Errorf("no deployment found for service %q: %w", serviceName, api.ErrNotFound)
+	}
+	patch := fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas)
+	for _, d := range deployments.Items {
+		_, err := kc.client.AppsV1().Deployments(kc.namespace).Patch(ctx, d.Name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteServicePods deletes the pods of the service, stopping their
+// containers within the grace period
+func (kc KubeClient) DeleteServicePods(ctx context.Context, projectName, serviceName string, gracePeriod time.Duration) error {
+	seconds := int64(gracePeriod.Seconds())
+	return kc.client.CoreV1().Pods(kc.namespace).DeleteCollection(ctx, metav1.DeleteOptions{
+		GracePeriodSeconds: &seconds,
+	}, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s=%s", api.ProjectLabel, projectName, api.ServiceLabel, serviceName),
+	})
+}
+
+// DeleteVolumeClaims deletes the persistent volume claims of the project and
+// returns their names
+func (kc KubeClient) DeleteVolumeClaims(ctx context.Context, projectName string) ([]string, error) {
+	claims, err := kc.client.CoreV1().PersistentVolumeClaims(kc.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", api.ProjectLabel, projectName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var deleted []string
+	for _, claim := range claims.Items {
+		err := kc.client.CoreV1().PersistentVolumeClaims(kc.namespace).Delete(ctx, claim.Name, metav1.DeleteOptions{})
+		if err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, claim.Name)
+	}
+	return deleted, nil
+}
+
+// GetImages retrieves the images of the project pods
+func (kc KubeClient) GetImages(ctx context.Context, projectName string, services []string) ([]api.ImageSummary, error) {
+	pods, err := kc.client.CoreV1().Pods(kc.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", api.ProjectLabel, projectName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	images := []api.ImageSummary{}
+	for _, pod := range pods.Items {
+		if len(services) > 0 && !utils.StringContains(services, pod.Labels[api.ServiceLabel]) {
+			continue
+		}
+		images = append(images, podToImageSummaries(pod)...)
+	}
+	return images, nil
+}
+
+// GetLogs retrieves pod logs, written after since and before until when set
+func (kc *KubeClient) GetLogs(ctx context.Context, projectName string, consumer api.LogConsumer, follow bool, since time.Time, until time.Time) error {
 	pods, err := kc.client.CoreV1().Pods(kc.namespace).List(ctx, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=%s", api.ProjectLabel, projectName),
 	})
 	if err != nil {
 		return err
 	}
+	logOptions := corev1.PodLogOptions{
+		Follow: follow,
+		// the API has no upper bound, filter lines on their timestamp instead
+		Timestamps: !until.IsZero(),
+	}
+	if !since.IsZero() {
+		sinceTime := metav1.NewTime(since)
+		logOptions.SinceTime = &sinceTime
+	}
 	eg, ctx := errgroup.WithContext(ctx)
 	for _, pod := range pods.Items {
 		podName := pod.Name
-		request := kc.client.CoreV1().Pods(kc.namespace).GetLogs(podName, &corev1.PodLogOptions{Follow: follow})
+		request := kc.client.CoreV1().Pods(kc.namespace).GetLogs(podName, &logOptions)
 		service := pod.Labels[api.ServiceLabel]
 		w := utils.GetWriter(func(line string) {
+			if !until.IsZero() {
+				var ok bool
+				if line, ok = untilFilter(line, until); !ok {
+					return
+				}
+			}
 			consumer.Log(podName, service, line)
 		})
 
@@ -237,6 +414,17 @@ func (kc *KubeClient) GetLogs(ctx context.Context, projectName string, consumer
 	return eg.Wait()
 }
 
+// untilFilter strips the timestamp prefixing the log line, returning false if
+// it was written after until.
+func untilFilter(line string, until time.Time) (string, bool) {
+	parts := strings.SplitN(line, " ", 2)
+	ts, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil || len(parts) < 2 {
+		return line, true
+	}
+	return parts[1], !ts.After(until)
+}
+
 // WaitForPodState blocks until pods reach desired state
 func (kc KubeClient) WaitForPodState(ctx context.Context, opts WaitForStatusOptions) error {
 	var timeout = time.Minute
@@ -21,6 +21,7 @@ package client
 
 import (
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -55,3 +56,65 @@ func TestPodToContainerSummary(t *testing.T) {
 	}
 	assert.DeepEqual(t, container, expected)
 }
+
+func TestUntilFilter(t *testing.T) {
+	until := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	line, ok := untilFilter("2022-03-01T11:59:59.123456789Z hello world", until)
+	assert.Assert(t, ok)
+	assert.Equal(t, line, "hello world")
+
+	_, ok = untilFilter("2022-03-01T12:00:01Z too late", until)
+	assert.Assert(t, !ok)
+
+	line, ok = untilFilter("no timestamp", until)
+	assert.Assert(t, ok)
+	assert.Equal(t, line, "no timestamp")
+}
+
+func TestPodToImageSummaries(t *testing.T) {
+	pod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-123"},
+		Status: v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					Image:   "nginx:1.21",
+					ImageID: "docker-pullable://nginx@sha256:4424e31f2c366108433ecca7890ad527b243361577180dfd9a5bb36e828abf47",
+				},
+				{
+					Image:   "localhost:5000/sidecar",
+					ImageID: "docker://sha256:0a3e7b8c5c6f",
+				},
+			},
+		},
+	}
+	assert.DeepEqual(t, podToImageSummaries(pod), []api.ImageSummary{
+		{
+			ID:            "sha256:4424e31f2c366108433ecca7890ad527b243361577180dfd9a5bb36e828abf47",
+			ContainerName: "web-123",
+			Repository:    "nginx",
+			Tag:           "1.21",
+		},
+		{
+			ID:            "sha256:0a3e7b8c5c6f",
+			ContainerName: "web-123",
+			Repository:    "localhost:5000/sidecar",
+		},
+	})
+}
+
+func TestKillCommand(t *testing.T) {
+	assert.DeepEqual(t, killCommand("SIGUSR1"), []string{"kill", "-s", "USR1", "1"})
+	assert.DeepEqual(t, killCommand("hup"), []string{"kill", "-s", "HUP", "1"})
+	assert.DeepEqual(t, killCommand("10"), []string{"kill", "-10", "1"})
+	assert.DeepEqual(t, killCommand(""), []string{"kill", "-s", "KILL", "1"})
+}
+
+func TestExecCommand(t *testing.T) {
+	assert.DeepEqual(t, execCommand(api.RunOptions{Command: []string{"ls", "-l"}}), []string{"ls", "-l"})
+	assert.DeepEqual(t, execCommand(api.RunOptions{
+		Command:     []string{"ls", "-l"},
+		Environment: []string{"FOO=bar"},
+		WorkingDir:  "/app",
+	}), []string{"sh", "-c", `cd "$0" && exec "$@"`, "/app", "env", "FOO=bar", "ls", "-l"})
+}
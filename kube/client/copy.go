@@ -0,0 +1,75 @@
+//go:build kube
+// +build kube
+
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+
+	"github.com/docker/compose/v2/pkg/api"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// GetServicePods retrieves the running pods of a service, sorted by name
+func (kc KubeClient) GetServicePods(ctx context.Context, projectName, serviceName string) ([]corev1.Pod, error) {
+	pods, err := kc.client.CoreV1().Pods(kc.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s=%s", api.ProjectLabel, projectName, api.ServiceLabel, serviceName),
+		FieldSelector: "status.phase=Running",
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+	return pods.Items, nil
+}
+
+// CopyToPod extracts the tar archive content into the dir directory of the pod
+func (kc KubeClient) CopyToPod(pod corev1.Pod, dir string, content io.Reader) error {
+	var stderr bytes.Buffer
+	err := kc.exec(pod, []string{"tar", "-xmf", "-", "-C", dir}, remotecommand.StreamOptions{
+		Stdin:  content,
+		Stdout: io.Discard,
+		Stderr: &stderr,
+	})
+	return execError(pod, err, stderr)
+}
+
+// CopyFromPod writes a tar archive of the file or directory at the given path
+// of the pod to w, its entries named after the path base name.
+func (kc KubeClient) CopyFromPod(pod corev1.Pod, srcPath string, followLink bool, w io.Writer) error {
+	flags := "-cf"
+	if followLink {
+		flags = "-chf"
+	}
+	var stderr bytes.Buffer
+	err := kc.exec(pod, []string{"tar", flags, "-", "-C", path.Dir(srcPath), path.Base(srcPath)}, remotecommand.StreamOptions{
+		Stdout: w,
+		Stderr: &stderr,
+	})
+	return execError(pod, err, stderr)
+}
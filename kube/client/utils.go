@@ -21,11 +21,14 @@ package client
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/docker/compose/v2/pkg/api"
 	"github.com/docker/compose/v2/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
+
+	cliutils "github.com/docker/compose-cli/utils"
 )
 
 func podToContainerSummary(pod corev1.Pod) api.ContainerSummary {
@@ -54,6 +57,26 @@ func podToContainerSummary(pod corev1.Pod) api.ContainerSummary {
 	}
 }
 
+// podToImageSummaries returns the images of the pod containers, identified by
+// their digest once pulled
+func podToImageSummaries(pod corev1.Pod) []api.ImageSummary {
+	images := []api.ImageSummary{}
+	for _, container := range pod.Status.ContainerStatuses {
+		repository, tag := cliutils.SplitImageReference(container.Image)
+		id := container.ImageID
+		if i := strings.LastIndex(id, "@"); i >= 0 {
+			id = id[i+1:]
+		}
+		images = append(images, api.ImageSummary{
+			ID:            strings.TrimPrefix(id, "docker://"),
+			ContainerName: pod.Name,
+			Repository:    repository,
+			Tag:           tag,
+		})
+	}
+	return images
+}
+
 func checkPodsState(services []string, pods []corev1.Pod, status string) (bool, map[string]string, error) {
 	servicePods := map[string]string{}
 	stateReached := true
@@ -21,13 +21,17 @@ package kube
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/compose-spec/compose-go/types"
 	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/compose"
 	"github.com/docker/compose/v2/pkg/progress"
 	utils2 "github.com/docker/compose/v2/pkg/utils"
+	"golang.org/x/sync/errgroup"
+	"k8s.io/client-go/util/exec"
 
 	apicontext "github.com/docker/compose-cli/api/context"
 	"github.com/docker/compose-cli/api/context/store"
@@ -92,6 +96,7 @@ func checkUnsupportedUpOptions(ctx context.Context, o api.UpOptions) error {
 		{o.Create.Recreate, api.RecreateDiverged, "force-recreate"},
 		{o.Create.RecreateDependencies, api.RecreateDiverged, "always-recreate-deps"},
 		{len(o.Start.AttachTo), 0, "attach-dependencies"},
+		{o.Start.CascadeStop, false, "abort-on-container-exit"},
 		{len(o.Start.ExitCodeFrom), 0, "exit-code-from"},
 		{o.Create.Timeout, nil, "timeout"},
 	}
@@ -102,6 +107,10 @@ func checkUnsupportedUpOptions(ctx context.Context, o api.UpOptions) error {
 }
 
 func (s *composeService) up(ctx context.Context, project *types.Project) error {
+	project = utils.KeepDisabledDependencies(project)
+	if err := utils.CheckProfiles(project); err != nil {
+		return err
+	}
 	w := progress.ContextWriter(ctx)
 
 	eventName := "Convert Compose file to Helm charts"
@@ -164,15 +173,16 @@ func (s *composeService) Down(ctx context.Context, projectName string, options a
 	})
 }
 
+// checkUnsupportedDownOptions accepts --remove-orphans as uninstalling the
+// release removes all the project resources, including the ones of services
+// removed from the compose file.
 func checkUnsupportedDownOptions(ctx context.Context, o api.DownOptions) error {
 	var errs error
 	checks := []struct {
 		toCheck, expected interface{}
 		option            string
 	}{
-		{o.Volumes, false, "volumes"},
 		{o.Images, "", "images"},
-		{o.RemoveOrphans, false, "remove-orphans"},
 	}
 	for _, c := range checks {
 		errs = utils.CheckUnsupported(ctx, errs, c.toCheck, c.expected, "down", c.option)
@@ -224,20 +234,23 @@ func (s *composeService) down(ctx context.Context, projectName string, options a
 	for _, e := range events {
 		w.Event(progress.NewEvent(e, progress.Done, ""))
 	}
+	if options.Volumes {
+		// volume claims are kept by helm on uninstall
+		claims, err := s.client.DeleteVolumeClaims(ctx, projectName)
+		for _, claim := range claims {
+			w.Event(progress.RemovedEvent(fmt.Sprintf("Volume %s", claim)))
+		}
+		if err != nil {
+			return err
+		}
+	}
 	w.Event(progress.NewEvent(eventName, progress.Done, ""))
 	return nil
 }
 
 // List executes the equivalent to a `docker stack ls`
 func (s *composeService) List(ctx context.Context, opts api.ListOptions) ([]api.Stack, error) {
-	if err := checkUnsupportedListOptions(ctx, opts); err != nil {
-		return nil, err
-	}
-	return s.sdk.ListReleases()
-}
-
-func checkUnsupportedListOptions(ctx context.Context, o api.ListOptions) error {
-	return utils.CheckUnsupported(ctx, nil, o.All, false, "ls", "all")
+	return s.sdk.ListReleases(opts.All)
 }
 
 // Build executes the equivalent to a `compose build`
@@ -257,27 +270,148 @@ func (s *composeService) Pull(ctx context.Context, project *types.Project, optio
 
 // Create executes the equivalent to a `compose create`
 func (s *composeService) Create(ctx context.Context, project *types.Project, opts api.CreateOptions) error {
-	return api.ErrNotImplemented
+	return utils.UnsupportedCommand(ctx, "create", `Kubernetes deployments are created running, the application can be deployed with "compose up"`)
 }
 
 // Start executes the equivalent to a `compose start`
 func (s *composeService) Start(ctx context.Context, project *types.Project, options api.StartOptions) error {
-	return api.ErrNotImplemented
+	return progress.Run(ctx, func(ctx context.Context) error {
+		return s.start(ctx, project)
+	})
+}
+
+// start scales the deployments of stopped services back to their replicas,
+// dependencies first.
+func (s *composeService) start(ctx context.Context, project *types.Project) error {
+	w := progress.ContextWriter(ctx)
+	return compose.InDependencyOrder(ctx, project, func(ctx context.Context, name string) error {
+		service, err := project.GetService(name)
+		if err != nil {
+			return err
+		}
+		if service.Deploy != nil && service.Deploy.Mode == "global" {
+			// daemon sets can't be stopped
+			return nil
+		}
+		replicas := 1
+		if service.Deploy != nil && service.Deploy.Replicas != nil {
+			replicas = int(*service.Deploy.Replicas)
+		}
+		w.Event(progress.StartingEvent(name))
+		if err := s.client.ScaleDeployment(ctx, project.Name, name, replicas); err != nil {
+			w.Event(progress.ErrorEvent(name))
+			return err
+		}
+		err = s.client.WaitForPodState(ctx, client.WaitForStatusOptions{
+			ProjectName: project.Name,
+			Services:    []string{name},
+			Status:      api.RUNNING,
+		})
+		if err != nil {
+			w.Event(progress.ErrorEvent(name))
+			return err
+		}
+		w.Event(progress.StartedEvent(name))
+		return nil
+	})
 }
 
 // Restart executes the equivalent to a `compose restart`
 func (s *composeService) Restart(ctx context.Context, project *types.Project, options api.RestartOptions) error {
-	return api.ErrNotImplemented
+	if err := checkUnsupportedRestartOptions(ctx, options); err != nil {
+		return err
+	}
+	return progress.Run(ctx, func(ctx context.Context) error {
+		return s.restart(ctx, project, options)
+	})
+}
+
+func checkUnsupportedRestartOptions(ctx context.Context, o api.RestartOptions) error {
+	return utils.CheckUnsupportedRestartTimeout(ctx, nil, o.Timeout)
+}
+
+// restart rolls out new pods for the services, dependencies first. Pods are
+// stopped with their termination grace period, other timeouts than the
+// default one being rejected.
+func (s *composeService) restart(ctx context.Context, project *types.Project, options api.RestartOptions) error {
+	services := options.Services
+	if len(services) == 0 {
+		services = project.ServiceNames()
+	}
+	w := progress.ContextWriter(ctx)
+	return compose.InDependencyOrder(ctx, project, func(ctx context.Context, service string) error {
+		if !utils2.StringContains(services, service) {
+			return nil
+		}
+		w.Event(progress.RestartingEvent(service))
+		if err := s.client.RestartDeployment(ctx, project.Name, service); err != nil {
+			w.Event(progress.ErrorEvent(service))
+			return err
+		}
+		err := s.client.WaitForPodState(ctx, client.WaitForStatusOptions{
+			ProjectName: project.Name,
+			Services:    []string{service},
+			Status:      api.RUNNING,
+		})
+		if err != nil {
+			w.Event(progress.ErrorEvent(service))
+			return err
+		}
+		w.Event(progress.StartedEvent(service))
+		return nil
+	})
 }
 
 // Stop executes the equivalent to a `compose stop`
 func (s *composeService) Stop(ctx context.Context, project *types.Project, options api.StopOptions) error {
-	return api.ErrNotImplemented
+	return progress.Run(ctx, func(ctx context.Context) error {
+		return s.stop(ctx, project, options)
+	})
 }
 
-// Copy copies a file/folder between a service container and the local filesystem
-func (s *composeService) Copy(ctx context.Context, project *types.Project, options api.CopyOptions) error {
-	return api.ErrNotImplemented
+// stop scales the deployments of the services down to no replica, dependent
+// services first. With a timeout, pods are deleted with it as grace period
+// instead of their termination grace period.
+func (s *composeService) stop(ctx context.Context, project *types.Project, options api.StopOptions) error {
+	services := options.Services
+	if len(services) == 0 {
+		services = project.ServiceNames()
+	}
+	w := progress.ContextWriter(ctx)
+	return compose.InReverseDependencyOrder(ctx, project, func(ctx context.Context, name string) error {
+		if !utils2.StringContains(services, name) {
+			return nil
+		}
+		service, err := project.GetService(name)
+		if err != nil {
+			return err
+		}
+		if service.Deploy != nil && service.Deploy.Mode == "global" {
+			return fmt.Errorf("service %q runs on every node and can't be stopped, the application can be removed with \"compose down\"", name)
+		}
+		w.Event(progress.StoppingEvent(name))
+		if err := s.client.ScaleDeployment(ctx, project.Name, name, 0); err != nil {
+			w.Event(progress.ErrorEvent(name))
+			return err
+		}
+		if options.Timeout != nil {
+			if err := s.client.DeleteServicePods(ctx, project.Name, name, *options.Timeout); err != nil {
+				w.Event(progress.ErrorEvent(name))
+				return err
+			}
+		}
+		err = s.client.WaitForPodState(ctx, client.WaitForStatusOptions{
+			ProjectName: project.Name,
+			Services:    []string{name},
+			Status:      api.REMOVING,
+		})
+		if err != nil {
+			w.Event(progress.ErrorEvent(name))
+			return err
+		}
+		w.Event(progress.StoppedEvent(name))
+		return nil
+	})
 }
 
 // Logs executes the equivalent to a `compose logs`
@@ -288,7 +422,11 @@ func (s *composeService) Logs(ctx context.Context, projectName string, consumer
 	if len(options.Services) > 0 {
 		consumer = utils.FilteredLogConsumer(consumer, options.Services)
 	}
-	return s.client.GetLogs(ctx, projectName, consumer, options.Follow)
+	since, until, err := utils.ParseLogTimeBounds(options)
+	if err != nil {
+		return err
+	}
+	return s.client.GetLogs(ctx, projectName, consumer, options.Follow, since, until)
 }
 
 func checkUnsupportedLogOptions(ctx context.Context, o api.LogOptions) error {
@@ -297,9 +435,7 @@ func checkUnsupportedLogOptions(ctx context.Context, o api.LogOptions) error {
 		toCheck, expected interface{}
 		option            string
 	}{
-		{o.Since, "", "since"},
 		{o.Timestamps, false, "timestamps"},
-		{o.Until, "", "until"},
 	}
 	for _, c := range checks {
 		errs = utils.CheckUnsupported(ctx, errs, c.toCheck, c.expected, "logs", c.option)
@@ -314,6 +450,13 @@ func (s *composeService) Ps(ctx context.Context, projectName string, options api
 
 // Convert translate compose model into backend's native format
 func (s *composeService) Convert(ctx context.Context, project *types.Project, options api.ConvertOptions) ([]byte, error) {
+	// chart templates are yaml manifests
+	if err := utils.CheckUnsupported(ctx, nil, options.Format, "yaml", "convert", "format"); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckProfiles(project); err != nil {
+		return nil, err
+	}
 	chart, err := helm.GetChartInMemory(project)
 	if err != nil {
 		return nil, err
@@ -334,8 +477,35 @@ func (s *composeService) Convert(ctx context.Context, project *types.Project, op
 	return buff, nil
 }
 
+// Kill sends the signal to the main process of the service pods
 func (s *composeService) Kill(ctx context.Context, project *types.Project, options api.KillOptions) error {
-	return api.ErrNotImplemented
+	services := options.Services
+	if len(services) == 0 {
+		services = project.ServiceNames()
+	}
+	return progress.Run(ctx, func(ctx context.Context) error {
+		w := progress.ContextWriter(ctx)
+		eg, ctx := errgroup.WithContext(ctx)
+		for _, service := range services {
+			pods, err := s.client.GetServicePods(ctx, project.Name, service)
+			if err != nil {
+				return err
+			}
+			for _, pod := range pods {
+				pod := pod
+				eg.Go(func() error {
+					w.Event(progress.KillingEvent(pod.Name))
+					if err := s.client.KillPod(pod, options.Signal); err != nil {
+						w.Event(progress.ErrorMessageEvent(pod.Name, "Error while Killing"))
+						return err
+					}
+					w.Event(progress.KilledEvent(pod.Name))
+					return nil
+				})
+			}
+		}
+		return eg.Wait()
+	})
 }
 
 // RunOneOffContainer creates a service oneoff container and starts its dependencies
@@ -352,37 +522,56 @@ func (s *composeService) Exec(ctx context.Context, project string, opts api.RunO
 	if err := checkUnsupportedExecOptions(ctx, opts); err != nil {
 		return 0, err
 	}
-	return 0, s.client.Exec(ctx, project, opts)
+	err := s.client.Exec(ctx, project, opts)
+	var exitErr exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitStatus(), nil
+	}
+	return 0, err
 }
 
 func checkUnsupportedExecOptions(ctx context.Context, o api.RunOptions) error {
 	var errs error
-	errs = utils.CheckUnsupported(ctx, errs, o.Index, 0, "exec", "index")
 	errs = utils.CheckUnsupported(ctx, errs, o.Privileged, false, "exec", "privileged")
-	errs = utils.CheckUnsupported(ctx, errs, o.WorkingDir, "", "exec", "workdir")
+	errs = utils.CheckUnsupported(ctx, errs, o.User, "", "exec", "user")
+	errs = utils.CheckUnsupported(ctx, errs, o.Detach, false, "exec", "detach")
 	return errs
 }
 
 func (s *composeService) Pause(ctx context.Context, project string, options api.PauseOptions) error {
-	return api.ErrNotImplemented
+	return utils.UnsupportedCommand(ctx, "pause", `Kubernetes pods can't be frozen, the application can be removed with "compose down"`)
 }
 
 func (s *composeService) UnPause(ctx context.Context, project string, options api.PauseOptions) error {
-	return api.ErrNotImplemented
+	return utils.UnsupportedCommand(ctx, "unpause", `Kubernetes pods can't be frozen`)
 }
 
+// Top describes the pods of the services
 func (s *composeService) Top(ctx context.Context, projectName string, services []string) ([]api.ContainerProcSummary, error) {
-	return nil, api.ErrNotImplemented
+	pods, err := s.client.GetContainers(ctx, projectName, false)
+	if err != nil {
+		return nil, err
+	}
+	return utils.ContainersTop(pods, services), nil
 }
 
+// Events reports the changes of the pods
 func (s *composeService) Events(ctx context.Context, project string, options api.EventsOptions) error {
-	return api.ErrNotImplemented
+	return utils.PollEvents(ctx, utils.EventsPollInterval, func(ctx context.Context) ([]api.ContainerSummary, error) {
+		return s.client.GetContainers(ctx, project, true)
+	}, options)
 }
 
+// Port returns the load balancer address publishing the service port
 func (s *composeService) Port(ctx context.Context, project string, service string, port int, options api.PortOptions) (string, int, error) {
-	return "", 0, api.ErrNotImplemented
+	pods, err := s.client.GetContainers(ctx, project, false)
+	if err != nil {
+		return "", 0, err
+	}
+	return utils.PublishedPort(pods, service, port, options)
 }
 
+// Images lists the images of the pods, identified by their digest
 func (s *composeService) Images(ctx context.Context, projectName string, options api.ImagesOptions) ([]api.ImageSummary, error) {
-	return nil, api.ErrNotImplemented
+	return s.client.GetImages(ctx, projectName, options.Services)
 }
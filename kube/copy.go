@@ -0,0 +1,172 @@
+//go:build kube
+// +build kube
+
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package kube
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+// Copy copies a file/folder between a service container and the local filesystem
+func (s *composeService) Copy(ctx context.Context, project *types.Project, options api.CopyOptions) error {
+	if err := utils.CheckUnsupported(ctx, nil, options.CopyUIDGID, false, "cp", "archive"); err != nil {
+		return err
+	}
+	srcService, srcPath := splitCopyPath(options.Source)
+	dstService, dstPath := splitCopyPath(options.Destination)
+	switch {
+	case srcService != "" && dstService != "":
+		return errors.New("copying between services is not supported")
+	case srcService == "" && dstService == "":
+		return errors.New("must specify at least one service source")
+	case srcService != "":
+		if options.All {
+			return errors.New("--all can only be used to copy to the service containers")
+		}
+		pods, err := s.servicePods(ctx, project.Name, srcService, options)
+		if err != nil {
+			return err
+		}
+		return s.copyFromPod(pods[0], srcPath, dstPath, options.FollowLink)
+	default:
+		pods, err := s.servicePods(ctx, project.Name, dstService, options)
+		if err != nil {
+			return err
+		}
+		for _, pod := range pods {
+			if err := s.copyToPod(pod, srcPath, dstPath, options.FollowLink); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// splitCopyPath splits a `SERVICE:PATH` copy argument, the service is empty
+// for a local path.
+func splitCopyPath(arg string) (string, string) {
+	if filepath.IsAbs(arg) || strings.HasPrefix(arg, ".") {
+		return "", arg
+	}
+	parts := strings.SplitN(arg, ":", 2)
+	if len(parts) == 1 {
+		return "", arg
+	}
+	return parts[0], parts[1]
+}
+
+func (s *composeService) servicePods(ctx context.Context, projectName, service string, options api.CopyOptions) ([]corev1.Pod, error) {
+	pods, err := s.client.GetServicePods(ctx, projectName, service)
+	if err != nil {
+		return nil, err
+	}
+	return selectPods(pods, service, options.Index, options.All)
+}
+
+// selectPods returns all the pods, or the one at the 1-based index
+func selectPods(pods []corev1.Pod, service string, index int, all bool) ([]corev1.Pod, error) {
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no container found for service %q", service)
+	}
+	if all {
+		return pods, nil
+	}
+	if index < 1 || index > len(pods) {
+		return nil, fmt.Errorf("service %q has no container at index %d", service, index)
+	}
+	return pods[index-1 : index], nil
+}
+
+// copyToPod copies the local srcPath to dstPath in the pod, into it when it
+// ends with a slash. A srcPath of "-" reads a tar archive from stdin to
+// extract into the dstPath directory.
+func (s *composeService) copyToPod(pod corev1.Pod, srcPath, dstPath string, followLink bool) error {
+	if srcPath == "-" {
+		return s.client.CopyToPod(pod, dstPath, os.Stdin)
+	}
+	if followLink {
+		resolved, err := filepath.EvalSymlinks(srcPath)
+		if err != nil {
+			return err
+		}
+		srcPath = resolved
+	}
+	dir, name := path.Dir(dstPath), path.Base(dstPath)
+	if strings.HasSuffix(dstPath, "/") {
+		dir, name = dstPath, filepath.Base(srcPath)
+	}
+	content, err := archive.TarResourceRebase(srcPath, name)
+	if err != nil {
+		return err
+	}
+	defer content.Close() // nolint errcheck
+	return s.client.CopyToPod(pod, dir, content)
+}
+
+// copyFromPod copies srcPath of the pod to the local dstPath, following the
+// `docker cp` semantics. A dstPath of "-" writes a tar archive to stdout.
+func (s *composeService) copyFromPod(pod corev1.Pod, srcPath, dstPath string, followLink bool) error {
+	if dstPath == "-" {
+		return s.client.CopyFromPod(pod, srcPath, followLink, os.Stdout)
+	}
+	r, w := io.Pipe()
+	go func() {
+		_ = w.CloseWithError(s.client.CopyFromPod(pod, srcPath, followLink, w))
+	}()
+	defer r.Close() // nolint errcheck
+	content, isDir, err := peekArchive(r)
+	if err != nil {
+		return err
+	}
+	return archive.CopyTo(content, archive.CopyInfo{
+		Path:   srcPath,
+		Exists: true,
+		IsDir:  isDir,
+	}, dstPath)
+}
+
+// peekArchive tells whether the first entry of the tar archive is a
+// directory, returning a reader of the whole archive.
+func peekArchive(r io.Reader) (io.Reader, bool, error) {
+	var header bytes.Buffer
+	hdr, err := tar.NewReader(io.TeeReader(r, &header)).Next()
+	if err == io.EOF {
+		return nil, false, errors.New("nothing to copy")
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return io.MultiReader(&header, r), hdr.Typeflag == tar.TypeDir, nil
+}
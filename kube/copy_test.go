@@ -0,0 +1,87 @@
+//go:build kube
+// +build kube
+
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package kube
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSplitCopyPath(t *testing.T) {
+	service, p := splitCopyPath("web:/etc/nginx")
+	assert.Equal(t, service, "web")
+	assert.Equal(t, p, "/etc/nginx")
+
+	service, p = splitCopyPath("./web:config")
+	assert.Equal(t, service, "")
+	assert.Equal(t, p, "./web:config")
+
+	service, p = splitCopyPath("config")
+	assert.Equal(t, service, "")
+	assert.Equal(t, p, "config")
+}
+
+func TestSelectPods(t *testing.T) {
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "web-2"}},
+	}
+	selected, err := selectPods(pods, "web", 2, false)
+	assert.NilError(t, err)
+	assert.Equal(t, len(selected), 1)
+	assert.Equal(t, selected[0].Name, "web-2")
+
+	selected, err = selectPods(pods, "web", 1, true)
+	assert.NilError(t, err)
+	assert.Equal(t, len(selected), 2)
+
+	_, err = selectPods(pods, "web", 3, false)
+	assert.Error(t, err, `service "web" has no container at index 3`)
+
+	_, err = selectPods(nil, "web", 1, false)
+	assert.Error(t, err, `no container found for service "web"`)
+}
+
+func TestPeekArchive(t *testing.T) {
+	var b bytes.Buffer
+	tw := tar.NewWriter(&b)
+	assert.NilError(t, tw.WriteHeader(&tar.Header{Name: "conf/", Typeflag: tar.TypeDir, Mode: 0o755}))
+	assert.NilError(t, tw.WriteHeader(&tar.Header{Name: "conf/app.ini", Typeflag: tar.TypeReg, Mode: 0o644, Size: 2}))
+	_, err := tw.Write([]byte("ok"))
+	assert.NilError(t, err)
+	assert.NilError(t, tw.Close())
+	archived := b.Bytes()
+
+	content, isDir, err := peekArchive(bytes.NewReader(archived))
+	assert.NilError(t, err)
+	assert.Assert(t, isDir)
+	all, err := io.ReadAll(content)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, all, archived)
+
+	_, _, err = peekArchive(bytes.NewReader(make([]byte, 1024)))
+	assert.Error(t, err, "nothing to copy")
+}
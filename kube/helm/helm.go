@@ -123,9 +123,11 @@ func (hc *Actions) Get(name string) (*release.Release, error) {
 	return actGet.Run(name)
 }
 
-// ListReleases lists chart releases
-func (hc *Actions) ListReleases() ([]api.Stack, error) {
+// ListReleases lists chart releases, the deployed and failed ones unless all
+// is set
+func (hc *Actions) ListReleases(all bool) ([]api.Stack, error) {
 	actList := action.NewList(hc.Config)
+	actList.All = all
 	releases, err := actList.Run()
 	if err != nil {
 		return nil, err
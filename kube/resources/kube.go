@@ -37,6 +37,9 @@ import (
 
 const (
 	clusterIPHeadless = "None"
+	// resourcePolicyAnnotation set to "keep" makes helm leave the resource
+	// in place when uninstalling the release
+	resourcePolicyAnnotation = "helm.sh/resource-policy"
 )
 
 // MapToKubernetesObjects maps compose project to Kubernetes objects
@@ -215,6 +218,8 @@ func mapToPVC(project *types.Project, service types.ServiceConfig, vol types.Ser
 		ObjectMeta: meta.ObjectMeta{
 			Name:   vol.Source,
 			Labels: selectorLabels(project.Name, service.Name),
+			// volumes are only removed by compose down --volumes
+			Annotations: map[string]string{resourcePolicyAnnotation: "keep"},
 		},
 		Spec: core.PersistentVolumeClaimSpec{
 			VolumeName:  vol.Source,
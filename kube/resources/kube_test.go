@@ -86,3 +86,20 @@ services:
 			Type:      core.ServiceTypeClusterIP,
 		}})
 }
+
+func TestVolumeClaimKeptOnUninstall(t *testing.T) {
+	model, err := loadYAML(`
+services:
+  db:
+    image: postgres
+    volumes:
+      - data:/var/lib/postgresql/data
+volumes:
+  data:
+`)
+	assert.NilError(t, err)
+
+	pvc := mapToPVC(model, model.Services[0], model.Services[0].Volumes[0]).(*core.PersistentVolumeClaim)
+	assert.Equal(t, pvc.Name, "data")
+	assert.DeepEqual(t, pvc.Annotations, map[string]string{resourcePolicyAnnotation: "keep"})
+}
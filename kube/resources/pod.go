@@ -77,10 +77,10 @@ func toPodTemplate(project *types.Project, serviceConfig types.ServiceConfig, la
 	if err != nil {
 		return apiv1.PodTemplateSpec{}, err
 	}
-	/*	pullPolicy, err := toImagePullPolicy(serviceConfig.Image, x-kubernetes-pull-policy)
-		if err != nil {
-			return apiv1.PodTemplateSpec{}, err
-		} */
+	pullPolicy, err := toImagePullPolicy(serviceConfig)
+	if err != nil {
+		return apiv1.PodTemplateSpec{}, err
+	}
 	tpl.ObjectMeta = metav1.ObjectMeta{
 		Labels:      labels,
 		Annotations: serviceConfig.Labels,
@@ -109,7 +109,7 @@ func toPodTemplate(project *types.Project, serviceConfig types.ServiceConfig, la
 	}
 	tpl.Spec.Containers[containerIX].Name = serviceConfig.Name
 	tpl.Spec.Containers[containerIX].Image = serviceConfig.Image
-	// FIXME tpl.Spec.Containers[containerIX].ImagePullPolicy = pullPolicy
+	tpl.Spec.Containers[containerIX].ImagePullPolicy = pullPolicy
 	tpl.Spec.Containers[containerIX].Command = serviceConfig.Entrypoint
 	tpl.Spec.Containers[containerIX].Args = serviceConfig.Command
 	tpl.Spec.Containers[containerIX].WorkingDir = serviceConfig.WorkingDir
@@ -139,6 +139,25 @@ func toPodTemplate(project *types.Project, serviceConfig types.ServiceConfig, la
 	return tpl, nil
 }
 
+// toImagePullPolicy maps the service pull_policy to the kubelet one. Images
+// are built and pushed to a registry before deploying, so the build policy
+// pulls images if they are missing from the node. Without pull_policy, the
+// kubelet default applies.
+func toImagePullPolicy(serviceConfig types.ServiceConfig) (apiv1.PullPolicy, error) {
+	switch serviceConfig.PullPolicy {
+	case "":
+		return "", nil
+	case types.PullPolicyAlways:
+		return apiv1.PullAlways, nil
+	case types.PullPolicyNever:
+		return apiv1.PullNever, nil
+	case types.PullPolicyMissing, types.PullPolicyIfNotPresent, types.PullPolicyBuild:
+		return apiv1.PullIfNotPresent, nil
+	default:
+		return "", errors.Errorf("service %q: unsupported pull_policy %q", serviceConfig.Name, serviceConfig.PullPolicy)
+	}
+}
+
 func toHostAliases(extraHosts []string) ([]apiv1.HostAlias, error) {
 	if extraHosts == nil {
 		return nil, nil
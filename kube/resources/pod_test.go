@@ -967,68 +967,32 @@ services:
 }
 */
 
-/* FIXME
 func TestToPodWithPullPolicy(t *testing.T) {
 	cases := []struct {
 		name           string
-		stack          string
+		policy         string
 		expectedPolicy apiv1.PullPolicy
-		expectedError  string
 	}{
-		{
-			name: "specific tag",
-			stack: `
-version: "3"
-services:
-  nginx:
-    image: nginx:specific
-`,
-			expectedPolicy: apiv1.PullIfNotPresent,
-		},
-		{
-			name: "latest tag",
-			stack: `
-version: "3"
-services:
-  nginx:
-    image: nginx:latest
-`,
-			expectedPolicy: apiv1.PullAlways,
-		},
-		{
-			name: "explicit policy",
-			stack: `
-version: "3"
-services:
-  nginx:
-    image: nginx:specific
-    x-kubernetes.pull-policy: Never
-`,
-			expectedPolicy: apiv1.PullNever,
-		},
-		{
-			name: "invalid policy",
-			stack: `
-version: "3"
-services:
-  nginx:
-    image: nginx:specific
-    x-kubernetes.pull-policy: Invalid
-`,
-			expectedError: `invalid pull policy "Invalid", must be "Always", "IfNotPresent" or "Never"`,
-		},
+		{name: "default"},
+		{name: "always", policy: "always", expectedPolicy: apiv1.PullAlways},
+		{name: "never", policy: "never", expectedPolicy: apiv1.PullNever},
+		{name: "missing", policy: "missing", expectedPolicy: apiv1.PullIfNotPresent},
+		{name: "build", policy: "build", expectedPolicy: apiv1.PullIfNotPresent},
 	}
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			pod, err := podTemplateWithError(c.stack)
-			if c.expectedError != "" {
-				assert.EqualError(t, err, c.expectedError)
-			} else {
-				assert.NoError(t, err)
-				assert.Equal(t, pod.Spec.Containers[0].ImagePullPolicy, c.expectedPolicy)
+			stack := `
+version: "3"
+services:
+  nginx:
+    image: nginx:specific
+`
+			if c.policy != "" {
+				stack += "    pull_policy: " + c.policy + "\n"
 			}
+			pod := podTemplate(t, stack)
+			assert.Equal(t, c.expectedPolicy, pod.Spec.Containers[0].ImagePullPolicy)
 		})
 	}
 }
-*/
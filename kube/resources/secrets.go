@@ -20,24 +20,25 @@
 package resources
 
 import (
-	"os"
 	"strings"
 
 	"github.com/compose-spec/compose-go/types"
 
 	corev1 "k8s.io/api/core/v1"
+
+	"github.com/docker/compose-cli/utils"
 )
 
 func toSecretSpecs(project *types.Project) ([]corev1.Secret, error) {
 	var secrets []corev1.Secret
 
-	for _, s := range project.Secrets {
+	for key, s := range project.Secrets {
 		if s.External.External {
 			continue
 		}
 		name := strings.ReplaceAll(s.Name, "_", "-")
-		// load secret file content
-		sensitiveData, err := os.ReadFile(s.File)
+		// load secret file or environment variable content
+		sensitiveData, err := utils.SecretContent(project, key, s)
 		if err != nil {
 			return nil, err
 		}
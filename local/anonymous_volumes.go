@@ -0,0 +1,60 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/docker/errdefs"
+)
+
+// anonymousVolumes returns the anonymous volumes mounted by the project
+// containers, the volumes which aren't declared by the compose file, created
+// for the image volumes or the service volumes without source.
+func (s composeService) anonymousVolumes(ctx context.Context, project *types.Project) ([]string, error) {
+	containers, err := s.projectContainers(ctx, project.Name, false)
+	if err != nil {
+		return nil, err
+	}
+	declared := map[string]bool{}
+	for _, volume := range project.Volumes {
+		declared[volume.Name] = true
+	}
+	var volumes []string
+	for _, c := range containers {
+		for _, m := range c.Mounts {
+			if m.Type == "volume" && !declared[m.Name] {
+				volumes = append(volumes, m.Name)
+			}
+		}
+	}
+	return volumes, nil
+}
+
+// removeRenewedVolumes removes the anonymous volumes the recreated containers
+// were mounting, which --renew-anon-volumes replaces with new ones. Volumes
+// still in use, by containers which haven't been recreated, are kept.
+func (s composeService) removeRenewedVolumes(ctx context.Context, volumes []string) error {
+	for _, volume := range volumes {
+		err := s.apiClient.VolumeRemove(ctx, volume, false)
+		if err != nil && !errdefs.IsConflict(err) && !errdefs.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/v3/assert"
+)
+
+// fakeVolumesClient lists the given containers and removes the volumes which
+// have no error set
+type fakeVolumesClient struct {
+	client.APIClient
+	containers []moby.Container
+	errors     map[string]error
+	removed    []string
+}
+
+func (c *fakeVolumesClient) ContainerList(ctx context.Context, options moby.ContainerListOptions) ([]moby.Container, error) {
+	return c.containers, nil
+}
+
+func (c *fakeVolumesClient) VolumeRemove(ctx context.Context, volumeID string, force bool) error {
+	if err := c.errors[volumeID]; err != nil {
+		return err
+	}
+	c.removed = append(c.removed, volumeID)
+	return nil
+}
+
+func TestAnonymousVolumes(t *testing.T) {
+	apiClient := &fakeVolumesClient{containers: []moby.Container{
+		{
+			Labels: map[string]string{api.ServiceLabel: "db"},
+			Mounts: []moby.MountPoint{
+				{Type: "volume", Name: "demo_data"},
+				{Type: "volume", Name: "3f0c1e"},
+				{Type: "bind", Source: "/src"},
+			},
+		},
+		{
+			Labels: map[string]string{api.ServiceLabel: "web"},
+			Mounts: []moby.MountPoint{{Type: "volume", Name: "a71d2b"}, {Type: "tmpfs"}},
+		},
+	}}
+	s := composeService{apiClient: apiClient}
+	project := &types.Project{Name: "demo", Volumes: types.Volumes{"data": {Name: "demo_data"}}}
+
+	volumes, err := s.anonymousVolumes(context.Background(), project)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, volumes, []string{"3f0c1e", "a71d2b"})
+}
+
+func TestRemoveRenewedVolumes(t *testing.T) {
+	testCases := []struct {
+		name    string
+		errors  map[string]error
+		removed []string
+		err     string
+	}{
+		{
+			name:    "removed",
+			removed: []string{"3f0c1e", "a71d2b"},
+		},
+		{
+			name: "in use or already removed",
+			errors: map[string]error{
+				"3f0c1e": errdefs.Conflict(errors.New("volume is in use")),
+				"a71d2b": errdefs.NotFound(errors.New("no such volume")),
+			},
+		},
+		{
+			name:   "failure",
+			errors: map[string]error{"3f0c1e": fmt.Errorf("engine failure")},
+			err:    "engine failure",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			apiClient := &fakeVolumesClient{errors: tc.errors}
+			s := composeService{apiClient: apiClient}
+			err := s.removeRenewedVolumes(context.Background(), []string{"3f0c1e", "a71d2b"})
+			if tc.err != "" {
+				assert.Error(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, apiClient.removed, tc.removed)
+		})
+	}
+}
@@ -43,10 +43,15 @@ type local struct {
 // NewService build a backend for "local" context, using Docker API client
 func NewService(apiClient client.APIClient) backend.Service {
 	file := cliconfig.LoadDefaultConfigFile(os.Stderr)
+	options := &engineOptions{}
 	return &local{
 		containerService: &containerService{apiClient},
 		volumeService:    &volumeService{apiClient},
-		composeService:   compose.NewComposeService(apiClient, file),
+		composeService: composeService{
+			Service:   compose.NewComposeService(engineClient{apiClient, options}, file),
+			apiClient: apiClient,
+			options:   options,
+		},
 	}
 }
 
@@ -0,0 +1,92 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"path"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/pkg/errors"
+)
+
+var (
+	propagations = map[string]bool{
+		types.PropagationPrivate:  true,
+		types.PropagationRPrivate: true,
+		types.PropagationShared:   true,
+		types.PropagationRShared:  true,
+		types.PropagationSlave:    true,
+		types.PropagationRSlave:   true,
+	}
+	consistencies = map[string]bool{
+		string(mount.ConsistencyDefault):   true,
+		string(mount.ConsistencyFull):      true,
+		string(mount.ConsistencyCached):    true,
+		string(mount.ConsistencyDelegated): true,
+	}
+)
+
+// bindOptions checks the propagation and consistency options of the volumes
+// of the services and returns, by service and target, the ones of the bind
+// mounts creating their host path. The compose library sets these mounts as
+// <source>:<target>:<mode> binds, the engine only creating the missing host
+// paths of binds, without their options.
+func (s composeService) bindOptions(ctx context.Context, project *types.Project) (map[string]map[string][]string, error) {
+	options := map[string]map[string][]string{}
+	var osType string
+	for _, service := range project.Services {
+		for _, volume := range service.Volumes {
+			var opts []string
+			if volume.Bind != nil && volume.Bind.Propagation != "" {
+				propagation := volume.Bind.Propagation
+				if volume.Type != types.VolumeTypeBind {
+					return nil, errors.Errorf("service %q: propagation %s can only be set on bind mounts, %s is a %s", service.Name, propagation, volume.Target, volume.Type)
+				}
+				if !propagations[propagation] {
+					return nil, errors.Errorf("service %q: invalid propagation %s of bind mount %s", service.Name, propagation, volume.Target)
+				}
+				if osType == "" {
+					info, err := s.apiClient.Info(ctx)
+					if err != nil {
+						return nil, err
+					}
+					osType = info.OSType
+				}
+				if osType == "windows" {
+					return nil, errors.Errorf("service %q: propagation of bind mount %s is not supported by Windows containers", service.Name, volume.Target)
+				}
+				opts = append(opts, propagation)
+			}
+			if volume.Consistency != "" {
+				if !consistencies[volume.Consistency] {
+					return nil, errors.Errorf("service %q: invalid consistency %s of mount %s", service.Name, volume.Consistency, volume.Target)
+				}
+				opts = append(opts, volume.Consistency)
+			}
+			if len(opts) == 0 || volume.Type != types.VolumeTypeBind || volume.Bind == nil || !volume.Bind.CreateHostPath {
+				continue
+			}
+			if options[service.Name] == nil {
+				options[service.Name] = map[string][]string{}
+			}
+			options[service.Name][path.Clean(volume.Target)] = opts
+		}
+	}
+	return options, nil
+}
@@ -0,0 +1,110 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"gotest.tools/v3/assert"
+)
+
+// fakeInfoClient reports the given engine OS type
+type fakeInfoClient struct {
+	client.APIClient
+	osType string
+}
+
+func (c fakeInfoClient) Info(ctx context.Context) (moby.Info, error) {
+	return moby.Info{OSType: c.osType}, nil
+}
+
+func TestBindOptions(t *testing.T) {
+	bind := func(target, propagation string, create bool) types.ServiceVolumeConfig {
+		return types.ServiceVolumeConfig{
+			Type:   types.VolumeTypeBind,
+			Source: "/src",
+			Target: target,
+			Bind:   &types.ServiceVolumeBind{Propagation: propagation, CreateHostPath: create},
+		}
+	}
+	cached := bind("/cached/", "", true)
+	cached.Consistency = "cached"
+	volume := types.ServiceVolumeConfig{Type: types.VolumeTypeVolume, Source: "data", Target: "/data", Bind: &types.ServiceVolumeBind{Propagation: "rshared"}}
+	invalidConsistency := bind("/src", "", true)
+	invalidConsistency.Consistency = "eventual"
+
+	testCases := []struct {
+		name    string
+		volumes []types.ServiceVolumeConfig
+		osType  string
+		want    map[string]map[string][]string
+		err     string
+	}{
+		{
+			name:    "created host paths",
+			volumes: []types.ServiceVolumeConfig{bind("/shared", "rshared", true), cached, bind("/existing", "rslave", false)},
+			osType:  "linux",
+			want: map[string]map[string][]string{
+				"web": {"/shared": {"rshared"}, "/cached": {"cached"}},
+			},
+		},
+		{
+			name:    "no options",
+			volumes: []types.ServiceVolumeConfig{bind("/src", "", true)},
+			want:    map[string]map[string][]string{},
+		},
+		{
+			name:    "invalid propagation",
+			volumes: []types.ServiceVolumeConfig{bind("/src", "shared-ish", true)},
+			osType:  "linux",
+			err:     `service "web": invalid propagation shared-ish of bind mount /src`,
+		},
+		{
+			name:    "propagation of a volume",
+			volumes: []types.ServiceVolumeConfig{volume},
+			err:     `service "web": propagation rshared can only be set on bind mounts, /data is a volume`,
+		},
+		{
+			name:    "windows containers",
+			volumes: []types.ServiceVolumeConfig{bind("/src", "rshared", true)},
+			osType:  "windows",
+			err:     `service "web": propagation of bind mount /src is not supported by Windows containers`,
+		},
+		{
+			name:    "invalid consistency",
+			volumes: []types.ServiceVolumeConfig{invalidConsistency},
+			err:     `service "web": invalid consistency eventual of mount /src`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := composeService{apiClient: fakeInfoClient{osType: tc.osType}}
+			project := &types.Project{Services: types.Services{{Name: "web", Volumes: tc.volumes}}}
+			options, err := s.bindOptions(context.Background(), project)
+			if tc.err != "" {
+				assert.Error(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, options, tc.want)
+		})
+	}
+}
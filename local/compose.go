@@ -0,0 +1,302 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stringid"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+// runIDLabel is the label identifying the one-off containers of a run
+const runIDLabel = "com.docker.compose.run-id"
+
+// composeService is the compose library service supporting the compose model
+// features the library lacks, see prepare. It retries pushes and pulls images
+// past failures.
+type composeService struct {
+	api.Service
+	apiClient client.APIClient
+	options   *engineOptions
+}
+
+func (s composeService) Push(ctx context.Context, project *types.Project, options api.PushOptions) error {
+	err := utils.PushWithRetry(ctx, func(ctx context.Context) error {
+		return s.Service.Push(ctx, project, options)
+	})
+	if err != nil {
+		return err
+	}
+	pushed, err := utils.PushedImages(ctx, s.apiClient, project, options)
+	if err != nil {
+		return err
+	}
+	return utils.PrintPushedImages(os.Stdout, pushed)
+}
+
+func (s composeService) Up(ctx context.Context, project *types.Project, options api.UpOptions) error {
+	if !utils.IsDryRun(ctx) {
+		return s.up(ctx, project, options)
+	}
+	operations, err := s.planUp(ctx, project, options.Create)
+	if err != nil {
+		return err
+	}
+	return utils.PrintOperations(os.Stdout, operations)
+}
+
+// Create prepares the project, see prepare, and copies the configs and secrets
+// setting the owner or the mode of their file into the containers once
+// created.
+func (s composeService) Create(ctx context.Context, project *types.Project, options api.CreateOptions) error {
+	project, files, err := s.prepare(ctx, project, prepareOptions{quietPull: options.QuietPull, copyFiles: true})
+	if err != nil {
+		return err
+	}
+	return s.create(ctx, project, options, files)
+}
+
+// prepareOptions group the options of prepare
+type prepareOptions struct {
+	quietPull bool
+	// services are the services containers are created for, all by default
+	services []string
+	// copyFiles is set when the configs and secrets setting the owner or the
+	// mode of their file are copied into the containers once created
+	copyFiles bool
+}
+
+// prepare prepares the project for the compose library to create the
+// containers of its services:
+//
+//   - checks the container names are unique, the engine can run containers
+//     for the platforms of the services and the external networks exist
+//   - pulls the images present for other platforms, creates the networks with
+//     IPAM pools and writes the secrets sourced from environment variables
+//   - labels the services with the hash of their configs, secrets and seccomp
+//     profiles content
+//   - sets the seccomp profiles and bind mount options of the containers
+//   - applies the devices, deploy resources, tmpfs modes and restart policies
+//     of the services
+//
+// It returns the prepared project and the files to copy into the containers,
+// removed from the services. In dry run, nothing is pulled, created or
+// written.
+func (s composeService) prepare(ctx context.Context, project *types.Project, options prepareOptions) (*types.Project, map[string][]copiedFile, error) {
+	dryRun := utils.IsDryRun(ctx)
+	if err := checkContainerNames(project); err != nil {
+		return nil, nil, err
+	}
+	if err := withDefaultPlatform(project); err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkPlatforms(ctx, project); err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkExternalNetworks(ctx, project, options.services...); err != nil {
+		return nil, nil, err
+	}
+	if !dryRun {
+		if err := s.pullPlatformImages(ctx, project, options.quietPull); err != nil {
+			return nil, nil, err
+		}
+		if err := s.createIPAMNetworks(ctx, project, options.services...); err != nil {
+			return nil, nil, err
+		}
+		if err := writeEnvironmentSecrets(project); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := labelContentHashes(project, contentHashKeyDir(), dryRun); err != nil {
+		return nil, nil, err
+	}
+	if dryRun {
+		if _, err := inlineSeccompProfiles(project); err != nil {
+			return nil, nil, err
+		}
+	} else if err := s.setContainerOptions(ctx, project); err != nil {
+		return nil, nil, err
+	}
+	var files map[string][]copiedFile
+	if options.copyFiles {
+		files = copiedFiles(project)
+	}
+	if err := normalizeDevices(project); err != nil {
+		return nil, nil, err
+	}
+	project, err := withDeployResources(project)
+	if err != nil {
+		return nil, nil, err
+	}
+	withTmpfsModes(project)
+	withRestartPolicies(project)
+	return project, files, nil
+}
+
+// create creates the containers, copies the configs and secrets into them and
+// removes the anonymous volumes of the containers recreated with
+// --renew-anon-volumes.
+func (s composeService) create(ctx context.Context, project *types.Project, options api.CreateOptions, files map[string][]copiedFile) error {
+	var renewed []string
+	if !options.Inherit {
+		var err error
+		if renewed, err = s.anonymousVolumes(ctx, project); err != nil {
+			return err
+		}
+	}
+	if err := s.Service.Create(ctx, project, options); err != nil {
+		return err
+	}
+	if err := s.copyFiles(ctx, project, files); err != nil {
+		return err
+	}
+	return s.removeRenewedVolumes(ctx, renewed)
+}
+
+// up prepares the project, see prepare, and creates the containers with
+// create before running up when configs or secrets have to be copied into
+// them or anonymous volumes renewed, as up starts the containers it creates.
+func (s composeService) up(ctx context.Context, project *types.Project, options api.UpOptions) error {
+	project, files, err := s.prepare(ctx, project, prepareOptions{quietPull: options.Create.QuietPull, copyFiles: true})
+	if err != nil {
+		return err
+	}
+	if len(files) > 0 || !options.Create.Inherit {
+		if err := s.create(ctx, project, options.Create, files); err != nil {
+			return err
+		}
+		// keep the containers created
+		options.Create.Recreate = api.RecreateNever
+		options.Create.RecreateDependencies = api.RecreateNever
+	}
+	return s.upWithDependencyChecks(ctx, project, func(ctx context.Context) error {
+		return s.Service.Up(ctx, project, options)
+	})
+}
+
+// RunOneOffContainer prepares the project, see prepare, and removes the
+// one-off containers run with --rm and their anonymous volumes itself, as the
+// compose library keeps their volumes and keeps the containers when
+// interrupted.
+func (s composeService) RunOneOffContainer(ctx context.Context, project *types.Project, options api.RunOptions) (int, error) {
+	// the one-off container being started once created, its configs and
+	// secrets are bind mounted
+	project, _, err := s.prepare(ctx, project, prepareOptions{quietPull: options.QuietPull, services: []string{options.Service}})
+	if err != nil {
+		return 0, err
+	}
+	if !options.AutoRemove || options.Detach {
+		return s.Service.RunOneOffContainer(ctx, project, options)
+	}
+	runID := stringid.GenerateRandomID()
+	options.Labels = options.Labels.Add(runIDLabel, runID)
+	options.AutoRemove = false
+	exitCode, err := s.Service.RunOneOffContainer(ctx, project, options)
+	if removeErr := s.removeOneOffContainer(ctx, runID, err == nil); removeErr != nil && err == nil {
+		err = removeErr
+	}
+	return exitCode, err
+}
+
+// removeOneOffContainer removes the one-off container labeled with the run id,
+// unless the run succeeded and the container still runs, detached from with
+// the escape keys. It's removed even when the context is done, the run being
+// interrupted.
+func (s composeService) removeOneOffContainer(ctx context.Context, runID string, succeeded bool) error {
+	interrupted := ctx.Err() != nil
+	ctx = context.Background()
+	containers, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", runIDLabel+"="+runID)),
+	})
+	if err != nil {
+		return err
+	}
+	for _, c := range containers {
+		if succeeded && !interrupted && c.State == "running" {
+			continue
+		}
+		err := s.apiClient.ContainerRemove(ctx, c.ID, moby.ContainerRemoveOptions{Force: true, RemoveVolumes: true})
+		if err != nil && !client.IsErrNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Ps only returns the containers matching the filters of the context, listed
+// by the engine.
+func (s composeService) Ps(ctx context.Context, projectName string, options api.PsOptions) ([]api.ContainerSummary, error) {
+	f := utils.PsFilters(ctx)
+	if f.Len() == 0 {
+		return s.Service.Ps(ctx, projectName, options)
+	}
+	f = f.Clone()
+	f.Add("label", api.ProjectLabel+"="+projectName)
+	matching, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{All: true, Filters: f})
+	if err != nil {
+		return nil, err
+	}
+	if len(matching) == 0 {
+		return nil, nil
+	}
+	containers, err := s.Service.Ps(ctx, projectName, options)
+	if err != nil {
+		return nil, err
+	}
+	ids := map[string]bool{}
+	for _, c := range matching {
+		ids[c.ID] = true
+	}
+	var filtered []api.ContainerSummary
+	for _, c := range containers {
+		if ids[c.ID] {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered, nil
+}
+
+// ProjectConfigFiles returns the compose files of the projects, as labeled on
+// their containers
+func (s composeService) ProjectConfigFiles(ctx context.Context) (map[string][]string, error) {
+	containers, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", api.ProjectLabel)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	configFiles := map[string][]string{}
+	for _, c := range containers {
+		project := c.Labels[api.ProjectLabel]
+		if _, ok := configFiles[project]; ok || c.Labels[api.ConfigFilesLabel] == "" {
+			continue
+		}
+		configFiles[project] = strings.Split(c.Labels[api.ConfigFilesLabel], ",")
+	}
+	return configFiles, nil
+}
@@ -0,0 +1,78 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"testing"
+
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"gotest.tools/v3/assert"
+)
+
+// fakeOneOffClient lists the containers of the run and records the removed ones
+type fakeOneOffClient struct {
+	client.APIClient
+	containers []moby.Container
+	removed    []string
+}
+
+func (c *fakeOneOffClient) ContainerList(ctx context.Context, options moby.ContainerListOptions) ([]moby.Container, error) {
+	var list []moby.Container
+	for _, container := range c.containers {
+		if options.Filters.MatchKVList("label", container.Labels) {
+			list = append(list, container)
+		}
+	}
+	return list, nil
+}
+
+func (c *fakeOneOffClient) ContainerRemove(ctx context.Context, id string, options moby.ContainerRemoveOptions) error {
+	if options.Force && options.RemoveVolumes {
+		c.removed = append(c.removed, id)
+	}
+	return nil
+}
+
+func TestRemoveOneOffContainer(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	testCases := []struct {
+		name      string
+		ctx       context.Context
+		state     string
+		succeeded bool
+		removed   []string
+	}{
+		{name: "exited", ctx: context.Background(), state: "exited", succeeded: true, removed: []string{"run"}},
+		{name: "failed", ctx: context.Background(), state: "running", removed: []string{"run"}},
+		{name: "detached", ctx: context.Background(), state: "running", succeeded: true},
+		{name: "interrupted", ctx: canceled, state: "running", succeeded: true, removed: []string{"run"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			apiClient := &fakeOneOffClient{containers: []moby.Container{
+				{ID: "run", State: tc.state, Labels: map[string]string{runIDLabel: "1234"}},
+				{ID: "other", State: "exited", Labels: map[string]string{runIDLabel: "5678"}},
+			}}
+			s := composeService{apiClient: apiClient}
+			assert.NilError(t, s.removeOneOffContainer(tc.ctx, "1234", tc.succeeded))
+			assert.DeepEqual(t, apiClient.removed, tc.removed)
+		})
+	}
+}
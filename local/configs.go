@@ -0,0 +1,163 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+)
+
+// defaultFileMode is the mode of config and secret files when not set by the
+// service
+const defaultFileMode = 0o444
+
+// copiedFile is a config or secret of a service copied into its containers
+type copiedFile struct {
+	// kind is config or secret
+	kind string
+	types.FileReferenceConfig
+}
+
+// copiedFiles removes from the services the configs and secrets setting the
+// owner or the mode of their target file, which the read-only bind mounts of
+// configs and secrets can't honor, and returns them by service. These files
+// are copied into the containers instead.
+func copiedFiles(project *types.Project) map[string][]copiedFile {
+	copied := map[string][]copiedFile{}
+	for i, service := range project.Services {
+		var configs []types.ServiceConfigObjConfig
+		for _, config := range service.Configs {
+			if config.UID == "" && config.GID == "" && config.Mode == nil {
+				configs = append(configs, config)
+				continue
+			}
+			copied[service.Name] = append(copied[service.Name], copiedFile{kind: "config", FileReferenceConfig: types.FileReferenceConfig(config)})
+		}
+		var secrets []types.ServiceSecretConfig
+		for _, secret := range service.Secrets {
+			if secret.UID == "" && secret.GID == "" && secret.Mode == nil {
+				secrets = append(secrets, secret)
+				continue
+			}
+			copied[service.Name] = append(copied[service.Name], copiedFile{kind: "secret", FileReferenceConfig: types.FileReferenceConfig(secret)})
+		}
+		project.Services[i].Configs = configs
+		project.Services[i].Secrets = secrets
+	}
+	return copied
+}
+
+// copyFiles copies the content of the configs and secrets into the containers
+// of their services, with the owner and mode they set.
+func (s composeService) copyFiles(ctx context.Context, project *types.Project, files map[string][]copiedFile) error {
+	if len(files) == 0 {
+		return nil
+	}
+	containers, err := s.projectContainers(ctx, project.Name, false)
+	if err != nil {
+		return err
+	}
+	for service, serviceFiles := range files {
+		archive, err := filesArchive(project, serviceFiles)
+		if err != nil {
+			return errors.Wrapf(err, "service %q", service)
+		}
+		for _, c := range containersOf(containers, service) {
+			err := s.apiClient.CopyToContainer(ctx, c.ID, "/", bytes.NewReader(archive), moby.CopyToContainerOptions{})
+			if err != nil {
+				return errors.Wrapf(err, "failed to copy configs and secrets into container %s", containerName(c))
+			}
+		}
+	}
+	return nil
+}
+
+// filesArchive returns the tar archive of the config and secret files, at
+// their target path, which defaults to /<config name> and
+// /run/secrets/<secret name> like for bind mounted configs and secrets.
+func filesArchive(project *types.Project, files []copiedFile) ([]byte, error) {
+	var b bytes.Buffer
+	w := tar.NewWriter(&b)
+	for _, f := range files {
+		var defined types.FileObjectConfig
+		baseDir := "/"
+		if f.kind == "secret" {
+			defined = types.FileObjectConfig(project.Secrets[f.Source])
+			baseDir = "/run/secrets/"
+		} else {
+			defined = types.FileObjectConfig(project.Configs[f.Source])
+		}
+		if defined.External.External {
+			return nil, errors.Errorf("unsupported external %s %s", f.kind, defined.Name)
+		}
+		content, err := os.ReadFile(defined.File)
+		if err != nil {
+			return nil, err
+		}
+		target := f.Target
+		if target == "" {
+			target = f.Source
+		}
+		if !strings.HasPrefix(target, "/") {
+			target = baseDir + target
+		}
+		header := &tar.Header{
+			Name: strings.TrimPrefix(path.Clean(target), "/"),
+			Size: int64(len(content)),
+			Mode: defaultFileMode,
+		}
+		if f.Mode != nil {
+			header.Mode = int64(*f.Mode)
+		}
+		if header.Uid, err = fileOwner(f.kind, "uid", f.UID); err != nil {
+			return nil, err
+		}
+		if header.Gid, err = fileOwner(f.kind, "gid", f.GID); err != nil {
+			return nil, err
+		}
+		if err := w.WriteHeader(header); err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(content); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func fileOwner(kind string, name string, value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.Atoi(value)
+	if err != nil || id < 0 {
+		return 0, errors.Errorf("invalid %s %s %q, a numeric id is required", kind, name, value)
+	}
+	return id, nil
+}
@@ -0,0 +1,135 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestCopiedFiles(t *testing.T) {
+	mode := uint32(0o600)
+	project := &types.Project{Services: types.Services{{
+		Name:    "web",
+		Configs: []types.ServiceConfigObjConfig{{Source: "nginx"}, {Source: "app", UID: "101"}},
+		Secrets: []types.ServiceSecretConfig{{Source: "token", Mode: &mode}, {Source: "password"}},
+	}, {
+		Name:    "db",
+		Configs: []types.ServiceConfigObjConfig{{Source: "nginx"}},
+	}}}
+
+	copied := copiedFiles(project)
+	assert.Equal(t, len(copied), 1)
+	assert.Equal(t, len(copied["web"]), 2)
+	assert.Equal(t, copied["web"][0].kind, "config")
+	assert.DeepEqual(t, copied["web"][0].FileReferenceConfig, types.FileReferenceConfig{Source: "app", UID: "101"})
+	assert.Equal(t, copied["web"][1].kind, "secret")
+	assert.DeepEqual(t, copied["web"][1].FileReferenceConfig, types.FileReferenceConfig{Source: "token", Mode: &mode})
+	assert.DeepEqual(t, project.Services[0].Configs, []types.ServiceConfigObjConfig{{Source: "nginx"}})
+	assert.DeepEqual(t, project.Services[0].Secrets, []types.ServiceSecretConfig{{Source: "password"}})
+	assert.DeepEqual(t, project.Services[1].Configs, []types.ServiceConfigObjConfig{{Source: "nginx"}})
+}
+
+func TestFilesArchive(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "content")
+	assert.NilError(t, os.WriteFile(file, []byte("content"), 0o600))
+	project := &types.Project{
+		Configs: types.Configs{"app": {File: file}},
+		Secrets: types.Secrets{
+			"token":    {File: file},
+			"external": {Name: "external", External: types.External{External: true}},
+		},
+	}
+	mode := uint32(0o640)
+
+	type entry struct {
+		Name     string
+		Mode     int64
+		UID, GID int
+	}
+	testCases := []struct {
+		name  string
+		files []copiedFile
+		want  []entry
+		err   string
+	}{
+		{
+			name: "default targets",
+			files: []copiedFile{
+				{kind: "config", FileReferenceConfig: types.FileReferenceConfig{Source: "app", UID: "101"}},
+				{kind: "secret", FileReferenceConfig: types.FileReferenceConfig{Source: "token", GID: "102"}},
+			},
+			want: []entry{
+				{Name: "app", Mode: defaultFileMode, UID: 101},
+				{Name: "run/secrets/token", Mode: defaultFileMode, GID: 102},
+			},
+		},
+		{
+			name: "relative and absolute targets",
+			files: []copiedFile{
+				{kind: "config", FileReferenceConfig: types.FileReferenceConfig{Source: "app", Target: "/etc/app/../app.conf", Mode: &mode}},
+				{kind: "secret", FileReferenceConfig: types.FileReferenceConfig{Source: "token", Target: "api/token"}},
+			},
+			want: []entry{
+				{Name: "etc/app.conf", Mode: 0o640},
+				{Name: "run/secrets/api/token", Mode: defaultFileMode},
+			},
+		},
+		{
+			name:  "invalid owner",
+			files: []copiedFile{{kind: "config", FileReferenceConfig: types.FileReferenceConfig{Source: "app", UID: "www-data"}}},
+			err:   `invalid config uid "www-data", a numeric id is required`,
+		},
+		{
+			name:  "external secret",
+			files: []copiedFile{{kind: "secret", FileReferenceConfig: types.FileReferenceConfig{Source: "external", UID: "0"}}},
+			err:   "unsupported external secret external",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			archive, err := filesArchive(project, tc.files)
+			if tc.err != "" {
+				assert.Error(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			var entries []entry
+			r := tar.NewReader(bytes.NewReader(archive))
+			for {
+				header, err := r.Next()
+				if err == io.EOF {
+					break
+				}
+				assert.NilError(t, err)
+				content, err := io.ReadAll(r)
+				assert.NilError(t, err)
+				assert.Equal(t, string(content), "content")
+				entries = append(entries, entry{Name: header.Name, Mode: header.Mode, UID: header.Uid, GID: header.Gid})
+			}
+			assert.DeepEqual(t, entries, tc.want)
+		})
+	}
+}
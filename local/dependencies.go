@@ -0,0 +1,218 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+)
+
+// dependencyCheckInterval is the interval dependencies are checked at, the
+// one the compose library waits for them with
+var dependencyCheckInterval = 500 * time.Millisecond
+
+// waitedDependency is a service depending on another one to be healthy or to
+// complete successfully
+type waitedDependency struct {
+	service    string
+	dependency string
+	condition  string
+}
+
+// upWithDependencyChecks runs up while checking the dependencies services wait
+// to be healthy or to complete successfully before being started. The compose
+// library waits for them forever when they have exited or are unhealthy, or
+// doesn't tell why they never will, so up fails as soon as one of them does.
+func (s composeService) upWithDependencyChecks(ctx context.Context, project *types.Project, up func(ctx context.Context) error) error {
+	var dependencies []waitedDependency
+	for _, service := range project.Services {
+		for name, config := range service.DependsOn {
+			switch config.Condition {
+			case types.ServiceConditionHealthy, types.ServiceConditionCompletedSuccessfully:
+				dependencies = append(dependencies, waitedDependency{service: service.Name, dependency: name, condition: config.Condition})
+			}
+		}
+	}
+	if len(dependencies) == 0 {
+		return up(ctx)
+	}
+
+	since := s.engineTime(ctx)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	failed := make(chan error, 1)
+	go func() {
+		err := s.checkDependencies(ctx, project, dependencies, since)
+		failed <- err
+		if err != nil {
+			cancel()
+		}
+	}()
+	err := up(ctx)
+	if err != nil {
+		select {
+		case dependencyErr := <-failed:
+			if dependencyErr != nil {
+				return dependencyErr
+			}
+		default:
+		}
+	}
+	return err
+}
+
+// checkDependencies returns an error as soon as a dependency fails the
+// condition of a service which isn't started yet, and nil once all services
+// are started. Only the containers started or exited since up started are
+// considered, the ones of a previous up being restarted by the library.
+func (s composeService) checkDependencies(ctx context.Context, project *types.Project, dependencies []waitedDependency, since time.Time) error {
+	ticker := time.NewTicker(dependencyCheckInterval)
+	defer ticker.Stop()
+	for len(dependencies) > 0 {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		containers, err := s.projectContainers(ctx, project.Name, false)
+		if err != nil {
+			// let up report engine errors
+			return nil
+		}
+		var pending []waitedDependency
+		for _, d := range dependencies {
+			started, err := s.startedSince(ctx, containersOf(containers, d.service), since)
+			if err != nil {
+				return nil
+			}
+			if started {
+				continue
+			}
+			for _, c := range containersOf(containers, d.dependency) {
+				container, err := s.apiClient.ContainerInspect(ctx, c.ID)
+				if err != nil {
+					return nil
+				}
+				if err := checkDependency(container, d.condition, since); err != nil {
+					return fmt.Errorf("service %q depends on service %q to %s: %w", d.service, d.dependency, conditionText(d.condition), err)
+				}
+			}
+			pending = append(pending, d)
+		}
+		dependencies = pending
+	}
+	return nil
+}
+
+// startedSince reports whether one of the containers was started since up
+// started
+func (s composeService) startedSince(ctx context.Context, containers []moby.Container, since time.Time) (bool, error) {
+	for _, c := range containers {
+		if c.State == "created" {
+			continue
+		}
+		container, err := s.apiClient.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			return false, err
+		}
+		if container.State != nil && stateTime(container.State.StartedAt).After(since) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// engineTime returns the current time of the engine, which the container
+// states are timed by, or else the local time
+func (s composeService) engineTime(ctx context.Context) time.Time {
+	info, err := s.apiClient.Info(ctx)
+	if err != nil {
+		return time.Now()
+	}
+	if t := stateTime(info.SystemTime); !t.IsZero() {
+		return t
+	}
+	return time.Now()
+}
+
+func conditionText(condition string) string {
+	if condition == types.ServiceConditionCompletedSuccessfully {
+		return "complete successfully"
+	}
+	return "be healthy"
+}
+
+// checkDependency returns an error if the dependency container will never
+// satisfy the condition
+func checkDependency(container moby.ContainerJSON, condition string, since time.Time) error {
+	if container.State == nil {
+		return nil
+	}
+	name := strings.TrimPrefix(container.Name, "/")
+	exited := (container.State.Status == "exited" || container.State.Status == "dead") &&
+		stateTime(container.State.FinishedAt).After(since) && !restarted(container)
+	if condition == types.ServiceConditionCompletedSuccessfully {
+		if exited && container.State.ExitCode != 0 {
+			return fmt.Errorf("container %s exited with code %d", name, container.State.ExitCode)
+		}
+		return nil
+	}
+	switch {
+	case healthcheckDisabled(container):
+		return fmt.Errorf("container %s has no healthcheck and will never be healthy", name)
+	case exited:
+		return fmt.Errorf("container %s exited with code %d", name, container.State.ExitCode)
+	case container.State.Running && stateTime(container.State.StartedAt).After(since) &&
+		container.State.Health != nil && container.State.Health.Status == moby.Unhealthy:
+		return fmt.Errorf("container %s is unhealthy", name)
+	}
+	return nil
+}
+
+// healthcheckDisabled reports whether the container has no healthcheck, from
+// its image or from its service
+func healthcheckDisabled(container moby.ContainerJSON) bool {
+	if container.Config == nil || container.State.Health != nil {
+		return false
+	}
+	check := container.Config.Healthcheck
+	return check == nil || len(check.Test) == 0 || check.Test[0] == "NONE"
+}
+
+// stateTime parses the start and finish times of containers, the zero time
+// when they never started or finished
+func stateTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// restarted reports whether the engine restarts the container when it exits
+func restarted(container moby.ContainerJSON) bool {
+	if container.HostConfig == nil {
+		return false
+	}
+	policy := container.HostConfig.RestartPolicy
+	return !policy.IsNone() && !(policy.IsOnFailure() && container.State.ExitCode == 0)
+}
@@ -0,0 +1,186 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/v3/assert"
+)
+
+// fakeContainersClient lists and inspects the given containers
+type fakeContainersClient struct {
+	client.APIClient
+	containers []moby.ContainerJSON
+}
+
+func (c fakeContainersClient) ContainerList(ctx context.Context, options moby.ContainerListOptions) ([]moby.Container, error) {
+	var list []moby.Container
+	for _, container := range c.containers {
+		list = append(list, moby.Container{
+			ID:     container.ID,
+			Names:  []string{container.Name},
+			State:  container.State.Status,
+			Labels: container.Config.Labels,
+		})
+	}
+	return list, nil
+}
+
+func (c fakeContainersClient) ContainerInspect(ctx context.Context, id string) (moby.ContainerJSON, error) {
+	for _, container := range c.containers {
+		if container.ID == id {
+			return container, nil
+		}
+	}
+	return moby.ContainerJSON{}, errdefs.NotFound(fmt.Errorf("no such container: %s", id))
+}
+
+func testContainer(service string, state moby.ContainerState, healthcheck *container.HealthConfig) moby.ContainerJSON {
+	return moby.ContainerJSON{
+		ContainerJSONBase: &moby.ContainerJSONBase{
+			ID:         service,
+			Name:       "/demo_" + service + "_1",
+			State:      &state,
+			HostConfig: &container.HostConfig{},
+		},
+		Config: &container.Config{
+			Labels:      map[string]string{api.ServiceLabel: service},
+			Healthcheck: healthcheck,
+		},
+	}
+}
+
+func TestCheckDependency(t *testing.T) {
+	since := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	before, after := since.Add(-time.Hour).Format(time.RFC3339Nano), since.Add(time.Second).Format(time.RFC3339Nano)
+	healthcheck := &container.HealthConfig{Test: []string{"CMD", "true"}}
+	tests := []struct {
+		name      string
+		state     moby.ContainerState
+		check     *container.HealthConfig
+		condition string
+		err       string
+	}{
+		{
+			name:      "exited before up",
+			state:     moby.ContainerState{Status: "exited", ExitCode: 137, StartedAt: before, FinishedAt: before},
+			check:     healthcheck,
+			condition: types.ServiceConditionHealthy,
+		},
+		{
+			name:      "exited during up",
+			state:     moby.ContainerState{Status: "exited", ExitCode: 1, StartedAt: after, FinishedAt: after},
+			check:     healthcheck,
+			condition: types.ServiceConditionHealthy,
+			err:       "container demo_db_1 exited with code 1",
+		},
+		{
+			name:      "unhealthy",
+			state:     moby.ContainerState{Status: "running", Running: true, StartedAt: after, Health: &moby.Health{Status: moby.Unhealthy}},
+			check:     healthcheck,
+			condition: types.ServiceConditionHealthy,
+			err:       "container demo_db_1 is unhealthy",
+		},
+		{
+			name:      "starting",
+			state:     moby.ContainerState{Status: "running", Running: true, StartedAt: after, Health: &moby.Health{Status: moby.Starting}},
+			check:     healthcheck,
+			condition: types.ServiceConditionHealthy,
+		},
+		{
+			name:      "no healthcheck",
+			state:     moby.ContainerState{Status: "running", Running: true, StartedAt: after},
+			check:     &container.HealthConfig{Test: []string{"NONE"}},
+			condition: types.ServiceConditionHealthy,
+			err:       "container demo_db_1 has no healthcheck and will never be healthy",
+		},
+		{
+			name:      "completed successfully",
+			state:     moby.ContainerState{Status: "exited", StartedAt: after, FinishedAt: after},
+			condition: types.ServiceConditionCompletedSuccessfully,
+		},
+		{
+			name:      "completed with an error",
+			state:     moby.ContainerState{Status: "exited", ExitCode: 2, StartedAt: after, FinishedAt: after},
+			condition: types.ServiceConditionCompletedSuccessfully,
+			err:       "container demo_db_1 exited with code 2",
+		},
+		{
+			name:      "completed with an error before up",
+			state:     moby.ContainerState{Status: "exited", ExitCode: 2, StartedAt: before, FinishedAt: before},
+			condition: types.ServiceConditionCompletedSuccessfully,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkDependency(testContainer("db", tt.state, tt.check), tt.condition, since)
+			if tt.err == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestCheckDependencies(t *testing.T) {
+	defer func(interval time.Duration) { dependencyCheckInterval = interval }(dependencyCheckInterval)
+	dependencyCheckInterval = time.Millisecond
+
+	since := time.Now()
+	before, after := since.Add(-time.Hour).Format(time.RFC3339Nano), since.Add(time.Second).Format(time.RFC3339Nano)
+	healthcheck := &container.HealthConfig{Test: []string{"CMD", "true"}}
+	project := &types.Project{Name: "demo"}
+	dependencies := []waitedDependency{{service: "web", dependency: "db", condition: types.ServiceConditionHealthy}}
+	check := func(containers ...moby.ContainerJSON) error {
+		s := composeService{apiClient: fakeContainersClient{containers: containers}}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		return s.checkDependencies(ctx, project, dependencies, since)
+	}
+
+	// the dependency stopped by a previous command is restarted by up
+	err := check(
+		testContainer("db", moby.ContainerState{Status: "exited", ExitCode: 137, StartedAt: before, FinishedAt: before}, healthcheck),
+		testContainer("web", moby.ContainerState{Status: "created"}, nil),
+	)
+	assert.NilError(t, err)
+
+	// the dependent stopped by a previous command isn't started yet
+	err = check(
+		testContainer("db", moby.ContainerState{Status: "exited", ExitCode: 1, StartedAt: after, FinishedAt: after}, healthcheck),
+		testContainer("web", moby.ContainerState{Status: "exited", StartedAt: before, FinishedAt: before}, nil),
+	)
+	assert.Error(t, err, `service "web" depends on service "db" to be healthy: container demo_db_1 exited with code 1`)
+
+	// the dependent has started
+	err = check(
+		testContainer("db", moby.ContainerState{Status: "exited", ExitCode: 1, StartedAt: after, FinishedAt: after}, healthcheck),
+		testContainer("web", moby.ContainerState{Status: "running", Running: true, StartedAt: after}, nil),
+	)
+	assert.NilError(t, err)
+}
@@ -0,0 +1,103 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"path"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+)
+
+// normalizeDevices checks the devices of the services are valid, parsing
+// them like the docker CLI does, and sets the <host path>:<cgroup permissions>
+// ones in the <host path>:<container path>:<cgroup permissions> form. The
+// compose library takes their permissions for the container path.
+func normalizeDevices(project *types.Project) error {
+	for i, service := range project.Services {
+		if len(service.Devices) == 0 {
+			continue
+		}
+		devices := make([]string, len(service.Devices))
+		var normalized bool
+		for j, device := range service.Devices {
+			src, dst, permissions, err := parseDevice(device)
+			if err != nil {
+				return errors.Wrapf(err, "service %q", service.Name)
+			}
+			devices[j] = device
+			if arr := strings.Split(device, ":"); len(arr) == 2 && validDeviceMode(arr[1]) {
+				devices[j] = strings.Join([]string{src, dst, permissions}, ":")
+				normalized = true
+			}
+		}
+		if normalized {
+			project.Services[i].Devices = devices
+		}
+	}
+	return nil
+}
+
+// parseDevice returns the host path, container path and cgroup permissions of
+// a device
+func parseDevice(device string) (string, string, string, error) {
+	var src, dst string
+	permissions := "rwm"
+	arr := strings.Split(device, ":")
+	switch len(arr) {
+	case 3:
+		if !validDeviceMode(arr[2]) {
+			return "", "", "", errors.Errorf("invalid device cgroup permissions %q in %s", arr[2], device)
+		}
+		permissions = arr[2]
+		dst = arr[1]
+	case 2:
+		if validDeviceMode(arr[1]) {
+			permissions = arr[1]
+		} else {
+			dst = arr[1]
+		}
+	case 1:
+	default:
+		return "", "", "", errors.Errorf("invalid device specification: %s", device)
+	}
+	src = arr[0]
+	if dst == "" {
+		dst = src
+	}
+	if src == "" || !path.IsAbs(dst) {
+		return "", "", "", errors.Errorf("invalid device specification: %s, the container path must be absolute", device)
+	}
+	return src, dst, permissions, nil
+}
+
+// validDeviceMode reports whether the mode is a combination of the r, w and m
+// cgroup permissions
+func validDeviceMode(mode string) bool {
+	legal := map[rune]bool{'r': true, 'w': true, 'm': true}
+	if mode == "" {
+		return false
+	}
+	for _, c := range mode {
+		if !legal[c] {
+			return false
+		}
+		legal[c] = false
+	}
+	return true
+}
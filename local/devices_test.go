@@ -0,0 +1,56 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestNormalizeDevices(t *testing.T) {
+	testCases := []struct {
+		devices []string
+		want    []string
+		err     string
+	}{
+		{devices: []string{"/dev/fuse"}, want: []string{"/dev/fuse"}},
+		{devices: []string{"/dev/sda:/dev/xvda"}, want: []string{"/dev/sda:/dev/xvda"}},
+		{devices: []string{"/dev/sda:/dev/xvda:r"}, want: []string{"/dev/sda:/dev/xvda:r"}},
+		{devices: []string{"/dev/sda:rw"}, want: []string{"/dev/sda:/dev/sda:rw"}},
+		{devices: []string{"/dev/fuse", "/dev/sda:rwm"}, want: []string{"/dev/fuse", "/dev/sda:/dev/sda:rwm"}},
+		{devices: []string{"/dev/sda:/dev/xvda:rx"}, err: `invalid device cgroup permissions "rx"`},
+		{devices: []string{"/dev/sda:/dev/xvda:rr"}, err: `invalid device cgroup permissions "rr"`},
+		{devices: []string{"/dev/sda:xvda"}, err: "the container path must be absolute"},
+		{devices: []string{":/dev/xvda"}, err: "the container path must be absolute"},
+		{devices: []string{"/dev/sda:/dev/xvda:r:m"}, err: "invalid device specification: /dev/sda:/dev/xvda:r:m"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.devices[len(tc.devices)-1], func(t *testing.T) {
+			project := &types.Project{Services: types.Services{{Name: "web", Devices: tc.devices}}}
+			err := normalizeDevices(project)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, `service "web"`)
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, project.Services[0].Devices, tc.want)
+		})
+	}
+}
@@ -0,0 +1,306 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/compose"
+	utils2 "github.com/docker/compose/v2/pkg/utils"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+
+	"github.com/docker/compose-cli/api/build"
+	"github.com/docker/compose-cli/utils"
+)
+
+// Down removes the project resources, and the files of its secrets sourced
+// from environment variables.
+func (s composeService) Down(ctx context.Context, projectName string, options api.DownOptions) error {
+	if !utils.IsDryRun(ctx) {
+		if err := s.Service.Down(ctx, projectName, options); err != nil {
+			return err
+		}
+		return removeEnvironmentSecrets(projectName)
+	}
+	operations, err := s.planDown(ctx, projectName, options)
+	if err != nil {
+		return err
+	}
+	return utils.PrintOperations(os.Stdout, operations)
+}
+
+func (s composeService) Build(ctx context.Context, project *types.Project, options api.BuildOptions) error {
+	if err := withDefaultPlatform(project); err != nil {
+		return err
+	}
+	if !utils.IsDryRun(ctx) {
+		return s.Service.Build(ctx, project, options)
+	}
+	services, err := project.GetServices(options.Services...)
+	if err != nil {
+		return err
+	}
+	var operations []utils.Operation
+	for _, service := range services {
+		if service.Build != nil {
+			operations = append(operations, utils.Operation{Action: "build", Resource: "image", Name: build.ImageName(project, service)})
+		}
+	}
+	return utils.PrintOperations(os.Stdout, operations)
+}
+
+func (s composeService) Pull(ctx context.Context, project *types.Project, options api.PullOptions) error {
+	if err := withDefaultPlatform(project); err != nil {
+		return err
+	}
+	if !utils.IsDryRun(ctx) {
+		return s.pull(ctx, project, options)
+	}
+	var operations []utils.Operation
+	for _, service := range project.Services {
+		if service.Image != "" && service.PullPolicy != types.PullPolicyBuild {
+			operations = append(operations, utils.Operation{Action: "pull", Resource: "image", Name: service.Image})
+		}
+	}
+	return utils.PrintOperations(os.Stdout, operations)
+}
+
+// planUp returns the operations compose up would perform, comparing the
+// project model with the engine resources
+func (s composeService) planUp(ctx context.Context, project *types.Project, options api.CreateOptions) ([]utils.Operation, error) {
+	// containers are created like with up, without the configs and secrets
+	// copied into them
+	project, _, err := s.prepare(ctx, project, prepareOptions{copyFiles: true})
+	if err != nil {
+		return nil, err
+	}
+	var operations []utils.Operation
+	for _, name := range project.NetworkNames() {
+		network := project.Networks[name]
+		if network.External.External {
+			continue
+		}
+		_, err := s.apiClient.NetworkInspect(ctx, network.Name, moby.NetworkInspectOptions{})
+		if client.IsErrNotFound(err) {
+			operations = append(operations, utils.Operation{Action: "create", Resource: "network", Name: network.Name})
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range project.VolumeNames() {
+		volume := project.Volumes[name]
+		if volume.External.External {
+			continue
+		}
+		_, err := s.apiClient.VolumeInspect(ctx, volume.Name)
+		if client.IsErrNotFound(err) {
+			operations = append(operations, utils.Operation{Action: "create", Resource: "volume", Name: volume.Name})
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	imageOperations, err := s.planImages(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	operations = append(operations, imageOperations...)
+
+	containers, err := s.projectContainers(ctx, project.Name, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, service := range project.Services {
+		hash, err := compose.ServiceHash(service)
+		if err != nil {
+			return nil, err
+		}
+		recreate := options.Recreate
+		if !utils2.StringContains(options.Services, service.Name) && len(options.Services) > 0 {
+			recreate = options.RecreateDependencies
+		}
+		replicas := 1
+		if service.Deploy != nil && service.Deploy.Replicas != nil {
+			replicas = int(*service.Deploy.Replicas)
+		}
+		existing := containersOf(containers, service.Name)
+		for i, c := range existing {
+			name := containerName(c)
+			diverged := c.Labels[api.ConfigHashLabel] != hash
+			switch {
+			case i >= replicas:
+				operations = append(operations, utils.Operation{Action: "remove", Resource: "container", Name: name})
+			case recreate == api.RecreateForce || (recreate == api.RecreateDiverged && diverged):
+				operations = append(operations, utils.Operation{Action: "recreate", Resource: "container", Name: name})
+			case c.State != api.RUNNING:
+				operations = append(operations, utils.Operation{Action: "start", Resource: "container", Name: name})
+			}
+		}
+		for n := len(existing) + 1; n <= replicas; n++ {
+			name := strings.Join([]string{project.Name, service.Name, strconv.Itoa(n)}, compose.Separator)
+			operations = append(operations, utils.Operation{Action: "create", Resource: "container", Name: name})
+		}
+	}
+	if options.RemoveOrphans {
+		for _, c := range orphanContainers(project, containers) {
+			operations = append(operations, utils.Operation{Action: "remove", Resource: "container", Name: containerName(c)})
+		}
+	}
+	return operations, nil
+}
+
+// planImages returns the images compose up would build or pull, according to
+// the service pull policies and the images available to the engine
+func (s composeService) planImages(ctx context.Context, project *types.Project) ([]utils.Operation, error) {
+	var operations []utils.Operation
+	for _, service := range project.Services {
+		image := build.ImageName(project, service)
+		_, _, err := s.apiClient.ImageInspectWithRaw(ctx, image)
+		missing := client.IsErrNotFound(err)
+		if err != nil && !missing {
+			return nil, err
+		}
+		switch {
+		case service.Build != nil && (missing || service.PullPolicy == types.PullPolicyBuild):
+			operations = append(operations, utils.Operation{Action: "build", Resource: "image", Name: image})
+		case service.Build == nil && service.PullPolicy == types.PullPolicyAlways,
+			service.Build == nil && missing && service.PullPolicy != types.PullPolicyNever:
+			operations = append(operations, utils.Operation{Action: "pull", Resource: "image", Name: image})
+		}
+	}
+	return operations, nil
+}
+
+// planDown returns the resources compose down would remove
+func (s composeService) planDown(ctx context.Context, projectName string, options api.DownOptions) ([]utils.Operation, error) {
+	var operations []utils.Operation
+	containers, err := s.projectContainers(ctx, projectName, true)
+	if err != nil {
+		return nil, err
+	}
+	var orphans []moby.Container
+	if options.Project != nil && !options.RemoveOrphans {
+		orphans = orphanContainers(options.Project, containers)
+	}
+	for _, c := range containers {
+		if !containsContainer(orphans, c) {
+			operations = append(operations, utils.Operation{Action: "remove", Resource: "container", Name: containerName(c)})
+		}
+	}
+
+	projectFilter := filters.NewArgs(filters.Arg("label", api.ProjectLabel+"="+projectName))
+	networks, err := s.apiClient.NetworkList(ctx, moby.NetworkListOptions{Filters: projectFilter})
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range networks {
+		operations = append(operations, utils.Operation{Action: "remove", Resource: "network", Name: n.Name})
+	}
+	if options.Volumes {
+		volumes, err := s.apiClient.VolumeList(ctx, projectFilter)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range volumes.Volumes {
+			operations = append(operations, utils.Operation{Action: "remove", Resource: "volume", Name: v.Name})
+		}
+	}
+	if options.Images != "" && options.Project != nil {
+		for _, service := range options.Project.Services {
+			if options.Images == "local" && service.Image != "" {
+				continue
+			}
+			image := build.ImageName(options.Project, service)
+			if _, _, err := s.apiClient.ImageInspectWithRaw(ctx, image); err == nil {
+				operations = append(operations, utils.Operation{Action: "remove", Resource: "image", Name: image})
+			}
+		}
+	}
+	return operations, nil
+}
+
+// projectContainers returns the containers of the project, sorted by service
+// and container number
+func (s composeService) projectContainers(ctx context.Context, projectName string, oneOff bool) ([]moby.Container, error) {
+	f := filters.NewArgs(filters.Arg("label", api.ProjectLabel+"="+projectName))
+	if !oneOff {
+		f.Add("label", api.OneoffLabel+"=False")
+	}
+	containers, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{All: true, Filters: f})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(containers, func(i, j int) bool {
+		si, sj := containers[i].Labels[api.ServiceLabel], containers[j].Labels[api.ServiceLabel]
+		if si != sj {
+			return si < sj
+		}
+		ni, _ := strconv.Atoi(containers[i].Labels[api.ContainerNumberLabel])
+		nj, _ := strconv.Atoi(containers[j].Labels[api.ContainerNumberLabel])
+		return ni < nj
+	})
+	return containers, nil
+}
+
+func containersOf(containers []moby.Container, service string) []moby.Container {
+	var result []moby.Container
+	for _, c := range containers {
+		if c.Labels[api.ServiceLabel] == service {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// orphanContainers returns the containers of services not in the project
+func orphanContainers(project *types.Project, containers []moby.Container) []moby.Container {
+	var services []string
+	for _, service := range project.AllServices() {
+		services = append(services, service.Name)
+	}
+	var orphans []moby.Container
+	for _, c := range containers {
+		if !utils2.StringContains(services, c.Labels[api.ServiceLabel]) {
+			orphans = append(orphans, c)
+		}
+	}
+	return orphans
+}
+
+func containsContainer(containers []moby.Container, container moby.Container) bool {
+	for _, c := range containers {
+		if c.ID == container.ID {
+			return true
+		}
+	}
+	return false
+}
+
+func containerName(c moby.Container) string {
+	if len(c.Names) == 0 {
+		return c.ID
+	}
+	return strings.TrimPrefix(c.Names[0], "/")
+}
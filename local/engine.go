@@ -0,0 +1,124 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// containerOptions are the options of the containers of a service the
+// compose library doesn't set properly, added by the engineClient.
+type containerOptions struct {
+	// seccomp is the security option setting the inlined seccomp profile
+	seccomp string
+	// binds are the options of the bind mounts by target
+	binds map[string][]string
+}
+
+// engineOptions are the containerOptions of the services by project
+type engineOptions struct {
+	mu       sync.Mutex
+	projects map[string]map[string]containerOptions
+}
+
+func (o *engineOptions) set(project string, services map[string]containerOptions) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.projects == nil {
+		o.projects = map[string]map[string]containerOptions{}
+	}
+	o.projects[project] = services
+}
+
+func (o *engineOptions) get(project, service string) (containerOptions, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	options, ok := o.projects[project][service]
+	return options, ok
+}
+
+// engineClient is the engine client of the compose library, adding the
+// containerOptions of the services to the containers it creates.
+type engineClient struct {
+	client.APIClient
+	options *engineOptions
+}
+
+func (c engineClient) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *specs.Platform, containerName string) (container.ContainerCreateCreatedBody, error) {
+	if config != nil && hostConfig != nil {
+		if options, ok := c.options.get(config.Labels[api.ProjectLabel], config.Labels[api.ServiceLabel]); ok {
+			if options.seccomp != "" {
+				hostConfig.SecurityOpt = append(append([]string{}, hostConfig.SecurityOpt...), options.seccomp)
+			}
+			if len(options.binds) > 0 {
+				hostConfig.Binds = withBindOptions(hostConfig.Binds, options.binds)
+			}
+		}
+	}
+	return c.APIClient.ContainerCreate(ctx, config, hostConfig, networkingConfig, platform, containerName)
+}
+
+// withBindOptions appends the options of the bind mounts to the
+// <source>:<target>:<mode> binds the compose library sets
+func withBindOptions(binds []string, options map[string][]string) []string {
+	updated := make([]string, len(binds))
+	for i, bind := range binds {
+		updated[i] = bind
+		j := strings.LastIndex(bind, ":")
+		if j < 0 {
+			continue
+		}
+		for target, opts := range options {
+			if strings.HasSuffix(bind[:j], ":"+target) {
+				updated[i] = bind + "," + strings.Join(opts, ",")
+				break
+			}
+		}
+	}
+	return updated
+}
+
+// setContainerOptions sets the containerOptions the engineClient adds to the
+// containers of the project services.
+func (s composeService) setContainerOptions(ctx context.Context, project *types.Project) error {
+	profiles, err := inlineSeccompProfiles(project)
+	if err != nil {
+		return err
+	}
+	binds, err := s.bindOptions(ctx, project)
+	if err != nil {
+		return err
+	}
+	services := map[string]containerOptions{}
+	for _, service := range project.Services {
+		options := containerOptions{seccomp: profiles[service.Name], binds: binds[service.Name]}
+		if options.seccomp != "" || len(options.binds) > 0 {
+			services[service.Name] = options
+		}
+	}
+	s.options.set(project.Name, services)
+	return nil
+}
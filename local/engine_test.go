@@ -0,0 +1,90 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+)
+
+// fakeCreateClient records the host config of the created container
+type fakeCreateClient struct {
+	client.APIClient
+	hostConfig *container.HostConfig
+}
+
+func (c *fakeCreateClient) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *specs.Platform, containerName string) (container.ContainerCreateCreatedBody, error) {
+	c.hostConfig = hostConfig
+	return container.ContainerCreateCreatedBody{ID: containerName}, nil
+}
+
+func TestWithBindOptions(t *testing.T) {
+	options := map[string][]string{
+		"/shared": {"rshared"},
+		"/cache":  {"rslave", "cached"},
+	}
+	testCases := []struct {
+		bind string
+		want string
+	}{
+		{bind: "/src:/shared:rw", want: "/src:/shared:rw,rshared"},
+		{bind: "/src:/cache:ro", want: "/src:/cache:ro,rslave,cached"},
+		{bind: `C:\src:/shared:rw`, want: `C:\src:/shared:rw,rshared`},
+		{bind: "/src:/shared/sub:rw", want: "/src:/shared/sub:rw"},
+		{bind: "/src:/other/shared:rw", want: "/src:/other/shared:rw"},
+		{bind: "/src:/data:rw", want: "/src:/data:rw"},
+		{bind: "volume", want: "volume"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.bind, func(t *testing.T) {
+			assert.DeepEqual(t, withBindOptions([]string{tc.bind}, options), []string{tc.want})
+		})
+	}
+}
+
+func TestEngineClientContainerCreate(t *testing.T) {
+	options := &engineOptions{}
+	options.set("demo", map[string]containerOptions{
+		"web": {seccomp: "seccomp={}", binds: map[string][]string{"/shared": {"rshared"}}},
+	})
+	fake := &fakeCreateClient{}
+	engine := engineClient{APIClient: fake, options: options}
+
+	securityOpt := []string{"no-new-privileges"}
+	hostConfig := &container.HostConfig{SecurityOpt: securityOpt, Binds: []string{"/src:/shared:rw"}}
+	config := &container.Config{Labels: map[string]string{api.ProjectLabel: "demo", api.ServiceLabel: "web"}}
+	_, err := engine.ContainerCreate(context.Background(), config, hostConfig, nil, nil, "demo_web_1")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, fake.hostConfig.SecurityOpt, []string{"no-new-privileges", "seccomp={}"})
+	assert.DeepEqual(t, fake.hostConfig.Binds, []string{"/src:/shared:rw,rshared"})
+	assert.DeepEqual(t, securityOpt, []string{"no-new-privileges"})
+
+	// containers of other services are created as is
+	hostConfig = &container.HostConfig{Binds: []string{"/src:/shared:rw"}}
+	config = &container.Config{Labels: map[string]string{api.ProjectLabel: "demo", api.ServiceLabel: "db"}}
+	_, err = engine.ContainerCreate(context.Background(), config, hostConfig, nil, nil, "demo_db_1")
+	assert.NilError(t, err)
+	assert.Assert(t, fake.hostConfig.SecurityOpt == nil)
+	assert.DeepEqual(t, fake.hostConfig.Binds, []string{"/src:/shared:rw"})
+}
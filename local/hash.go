@@ -0,0 +1,159 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/config"
+	"github.com/docker/compose-cli/utils"
+)
+
+// contentHashLabel is the label set to the digest of the content of the
+// configs and secrets of a service
+const contentHashLabel = "com.docker.compose.content-hash"
+
+// contentHashKeySize is the size of the keys of the secret content digests
+const contentHashKeySize = 32
+
+// labelContentHashes sets the contentHashLabel of the services mounting
+// configs or secrets files, or setting a seccomp profile file. The label being
+// part of the config hash the compose library compares containers with,
+// containers are recreated when the content of these files changes, which the
+// engine wouldn't update in running containers. Files which can't be read are
+// left for the engine to report.
+//
+// The digests of services mounting secrets are HMACs keyed by a random key of
+// the project, stored in keyDir, so that secrets can't be guessed from the
+// labels of their containers. On dry run, missing keys are generated but not
+// stored.
+func labelContentHashes(project *types.Project, keyDir string, dryRun bool) error {
+	for i, service := range project.Services {
+		var contents []hashedContent
+		for _, config := range service.Configs {
+			definedConfig := project.Configs[config.Source]
+			if definedConfig.File == "" {
+				continue
+			}
+			content, err := os.ReadFile(definedConfig.File)
+			if err != nil {
+				continue
+			}
+			contents = append(contents, hashedContent{kind: "config", name: config.Source, content: content})
+		}
+		var secret bool
+		for _, s := range service.Secrets {
+			definedSecret := project.Secrets[s.Source]
+			if _, ok := utils.SecretEnvironment(definedSecret); !ok && definedSecret.File == "" {
+				continue
+			}
+			content, err := utils.SecretContent(project, s.Source, definedSecret)
+			if err != nil {
+				continue
+			}
+			contents = append(contents, hashedContent{kind: "secret", name: s.Source, content: content})
+			secret = true
+		}
+		for _, opt := range service.SecurityOpt {
+			file, ok := seccompProfileFile(opt)
+			if !ok {
+				continue
+			}
+			content, err := os.ReadFile(project.RelativePath(file))
+			if err != nil {
+				continue
+			}
+			contents = append(contents, hashedContent{kind: "seccomp", name: file, content: content})
+		}
+		if len(contents) == 0 {
+			continue
+		}
+		h := sha256.New()
+		if secret {
+			key, err := contentHashKey(keyDir, project.Name, dryRun)
+			if err != nil {
+				return err
+			}
+			h = hmac.New(sha256.New, key)
+		}
+		for _, c := range contents {
+			c.writeTo(h)
+		}
+		if service.Labels == nil {
+			service.Labels = types.Labels{}
+		}
+		service.Labels[contentHashLabel] = hex.EncodeToString(h.Sum(nil))
+		project.Services[i] = service
+	}
+	return nil
+}
+
+type hashedContent struct {
+	kind    string
+	name    string
+	content []byte
+}
+
+func (c hashedContent) writeTo(w io.Writer) {
+	for _, b := range [][]byte{[]byte(c.kind), []byte(c.name), c.content} {
+		_, _ = w.Write(b)
+		_, _ = w.Write([]byte{0})
+	}
+}
+
+// contentHashKey returns the content digest key of the project, generated
+// when missing and stored unless on dry run. Keys are only readable by the
+// user.
+func contentHashKey(dir string, project string, dryRun bool) ([]byte, error) {
+	file := filepath.Join(dir, project+".key")
+	key, err := os.ReadFile(file)
+	if err == nil && len(key) == contentHashKeySize {
+		return key, nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "failed to read the content hash key")
+	}
+	key = make([]byte, contentHashKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return key, nil
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(file, key, 0o600); err != nil {
+		return nil, errors.Wrap(err, "failed to write the content hash key")
+	}
+	return key, nil
+}
+
+// contentHashKeyDir returns the directory of the content digest keys, in the
+// docker config directory
+func contentHashKeyDir() string {
+	return filepath.Join(config.Dir(), "compose", "content-hash-keys")
+}
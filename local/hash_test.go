@@ -0,0 +1,127 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/compose"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+func TestLabelContentHashes(t *testing.T) {
+	dir := t.TempDir()
+	keyDir := filepath.Join(dir, "keys")
+	configFile := filepath.Join(dir, "nginx.conf")
+	assert.NilError(t, os.WriteFile(configFile, []byte("worker_processes 1;"), 0o644))
+	newProject := func() *types.Project {
+		return &types.Project{
+			Name:        "demo",
+			WorkingDir:  dir,
+			Environment: map[string]string{"TOKEN": "s3cr3t"},
+			Configs:     types.Configs{"nginx": types.ConfigObjConfig{File: configFile}},
+			Secrets: types.Secrets{
+				"token": types.SecretConfig{Extensions: map[string]interface{}{utils.SecretEnvironmentExtension: "TOKEN"}},
+			},
+			Services: types.Services{
+				{Name: "web", Image: "nginx", Configs: []types.ServiceConfigObjConfig{{Source: "nginx"}}},
+				{Name: "api", Image: "api", Secrets: []types.ServiceSecretConfig{{Source: "token"}}},
+				{Name: "db", Image: "mysql"},
+			},
+		}
+	}
+	labels := func(project *types.Project) map[string]string {
+		assert.NilError(t, labelContentHashes(project, keyDir, false))
+		hashes := map[string]string{}
+		for _, service := range project.Services {
+			if hash, ok := service.Labels[contentHashLabel]; ok {
+				hashes[service.Name] = hash
+			}
+		}
+		return hashes
+	}
+
+	project := newProject()
+	first := labels(project)
+	assert.Equal(t, len(first), 2)
+	assert.DeepEqual(t, labels(newProject()), first)
+	web, err := project.GetService("web")
+	assert.NilError(t, err)
+	webHash, err := compose.ServiceHash(web)
+	assert.NilError(t, err)
+
+	// the secret digest is keyed
+	plain := sha256.New()
+	hashedContent{kind: "secret", name: "token", content: []byte("s3cr3t")}.writeTo(plain)
+	assert.Assert(t, first["api"] != hex.EncodeToString(plain.Sum(nil)))
+	info, err := os.Stat(filepath.Join(keyDir, "demo.key"))
+	assert.NilError(t, err)
+	assert.Equal(t, info.Mode().Perm(), os.FileMode(0o600))
+
+	// services are recreated when the content changes
+	assert.NilError(t, os.WriteFile(configFile, []byte("worker_processes 2;"), 0o644))
+	project = newProject()
+	project.Environment["TOKEN"] = "changed"
+	changed := labels(project)
+	assert.Assert(t, changed["web"] != first["web"])
+	assert.Assert(t, changed["api"] != first["api"])
+	web, err = project.GetService("web")
+	assert.NilError(t, err)
+	changedHash, err := compose.ServiceHash(web)
+	assert.NilError(t, err)
+	assert.Assert(t, changedHash != webHash)
+
+	// other projects have their own key
+	project = newProject()
+	project.Name = "other"
+	project.Environment["TOKEN"] = "changed"
+	other := labels(project)
+	assert.Equal(t, other["web"], changed["web"])
+	assert.Assert(t, other["api"] != changed["api"])
+}
+
+func TestLabelContentHashesDryRun(t *testing.T) {
+	keyDir := filepath.Join(t.TempDir(), "keys")
+	project := &types.Project{
+		Name:        "demo",
+		Environment: map[string]string{"TOKEN": "s3cr3t"},
+		Secrets: types.Secrets{
+			"token": types.SecretConfig{Extensions: map[string]interface{}{utils.SecretEnvironmentExtension: "TOKEN"}},
+		},
+		Services: types.Services{
+			{Name: "api", Image: "api", Secrets: []types.ServiceSecretConfig{{Source: "token"}}},
+		},
+	}
+	assert.NilError(t, labelContentHashes(project, keyDir, true))
+	assert.Assert(t, project.Services[0].Labels[contentHashLabel] != "")
+	_, err := os.Stat(keyDir)
+	assert.Assert(t, os.IsNotExist(err))
+
+	// an existing key is used
+	key, err := contentHashKey(keyDir, "demo", false)
+	assert.NilError(t, err)
+	dryRunKey, err := contentHashKey(keyDir, "demo", true)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, dryRunKey, key)
+}
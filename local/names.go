@@ -0,0 +1,46 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+)
+
+// checkContainerNames returns an error when a service setting its container
+// name is scaled or shares it with another service. The compose library only
+// reports scaled services once their images are pulled and built, and the
+// engine fails creating the second container with the same name.
+func checkContainerNames(project *types.Project) error {
+	services := map[string]string{}
+	for _, service := range project.Services {
+		name := service.ContainerName
+		if name == "" {
+			continue
+		}
+		if service.Deploy != nil && service.Deploy.Replicas != nil && *service.Deploy.Replicas > 1 {
+			return errors.Errorf("service %q can't be scaled to %d containers as it sets the container name %q, docker requires each container to have a unique name",
+				service.Name, *service.Deploy.Replicas, name)
+		}
+		if other, ok := services[name]; ok {
+			return errors.Errorf("services %q and %q set the same container name %q, docker requires each container to have a unique name",
+				other, service.Name, name)
+		}
+		services[name] = service.Name
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckContainerNames(t *testing.T) {
+	replicas := func(n uint64) *types.DeployConfig {
+		return &types.DeployConfig{Replicas: &n}
+	}
+	testCases := []struct {
+		name     string
+		services types.Services
+		err      string
+	}{
+		{
+			name: "unique names",
+			services: types.Services{
+				{Name: "web", ContainerName: "my-web"},
+				{Name: "db", ContainerName: "my-db", Deploy: replicas(1)},
+				{Name: "worker", Deploy: replicas(3)},
+				{Name: "cache"},
+			},
+		},
+		{
+			name:     "scaled service",
+			services: types.Services{{Name: "web", ContainerName: "my-web", Deploy: replicas(2)}},
+			err:      `service "web" can't be scaled to 2 containers as it sets the container name "my-web"`,
+		},
+		{
+			name: "shared name",
+			services: types.Services{
+				{Name: "web", ContainerName: "app"},
+				{Name: "db"},
+				{Name: "api", ContainerName: "app"},
+			},
+			err: `services "web" and "api" set the same container name "app"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkContainerNames(&types.Project{Services: tc.services})
+			if tc.err == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+// checkExternalNetworks returns an error naming the first external network
+// that doesn't exist among the ones the services, all of them by default, and
+// their dependencies are attached to. The compose library only checks them
+// once the images are pulled and built, the containers being attached to the
+// existing networks with their declared aliases.
+func (s composeService) checkExternalNetworks(ctx context.Context, project *types.Project, serviceNames ...string) error {
+	services := map[string][]string{}
+	err := project.WithServices(serviceNames, func(service types.ServiceConfig) error {
+		for name := range service.Networks {
+			if project.Networks[name].External.External {
+				services[name] = append(services[name], service.Name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	networks := make([]string, 0, len(services))
+	for name := range services {
+		networks = append(networks, name)
+	}
+	sort.Strings(networks)
+	for _, name := range networks {
+		network := project.Networks[name]
+		_, err := s.apiClient.NetworkInspect(ctx, network.Name, moby.NetworkInspectOptions{})
+		if errdefs.IsNotFound(err) {
+			sort.Strings(services[name])
+			return errors.Errorf("network %s declared as external for services %s could not be found, create it with \"docker network create %s\"",
+				network.Name, strings.Join(services[name], ", "), network.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createIPAMNetworks creates the networks configuring IPAM pools that don't
+// exist yet, with the labels the compose library sets, before the library
+// creates the others. Only the networks the services, all of them by default,
+// and their dependencies are attached to are created. The library only sets
+// the subnet of the pools, dropping their ip range, gateway and auxiliary
+// addresses the static addresses of the services may depend on.
+func (s composeService) createIPAMNetworks(ctx context.Context, project *types.Project, serviceNames ...string) error {
+	attached := map[string]bool{}
+	err := project.WithServices(serviceNames, func(service types.ServiceConfig) error {
+		for name := range service.Networks {
+			attached[name] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for key, n := range project.Networks {
+		if !attached[key] || n.External.External || len(n.Ipam.Config) == 0 {
+			continue
+		}
+		_, err := s.apiClient.NetworkInspect(ctx, n.Name, moby.NetworkInspectOptions{})
+		if err == nil {
+			continue
+		}
+		if !errdefs.IsNotFound(err) {
+			return err
+		}
+		ipam := &network.IPAM{Driver: n.Ipam.Driver}
+		for _, pool := range n.Ipam.Config {
+			ipam.Config = append(ipam.Config, network.IPAMConfig{
+				Subnet:     pool.Subnet,
+				IPRange:    pool.IPRange,
+				Gateway:    pool.Gateway,
+				AuxAddress: pool.AuxiliaryAddresses,
+			})
+		}
+		labels := types.Labels{}
+		for k, v := range n.Labels {
+			labels[k] = v
+		}
+		labels = labels.Add(api.NetworkLabel, key).
+			Add(api.ProjectLabel, project.Name).
+			Add(api.VersionLabel, api.ComposeVersion)
+
+		eventName := "Network " + n.Name
+		w := progress.ContextWriter(ctx)
+		w.Event(progress.CreatingEvent(eventName))
+		_, err = s.apiClient.NetworkCreate(ctx, n.Name, moby.NetworkCreate{
+			Labels:     labels,
+			Driver:     n.Driver,
+			Options:    n.DriverOpts,
+			Internal:   n.Internal,
+			Attachable: n.Attachable,
+			IPAM:       ipam,
+			EnableIPv6: n.EnableIPv6,
+		})
+		if err != nil {
+			w.Event(progress.ErrorEvent(eventName))
+			return errors.Wrapf(err, "failed to create network %s", n.Name)
+		}
+		w.Event(progress.CreatedEvent(eventName))
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/v3/assert"
+)
+
+// fakeNetworksClient inspects the existing networks and records the created ones
+type fakeNetworksClient struct {
+	client.APIClient
+	existing map[string]bool
+	created  []string
+}
+
+func (c *fakeNetworksClient) NetworkInspect(ctx context.Context, name string, options moby.NetworkInspectOptions) (moby.NetworkResource, error) {
+	if c.existing[name] {
+		return moby.NetworkResource{Name: name}, nil
+	}
+	return moby.NetworkResource{}, errdefs.NotFound(fmt.Errorf("network %s not found", name))
+}
+
+func (c *fakeNetworksClient) NetworkCreate(ctx context.Context, name string, options moby.NetworkCreate) (moby.NetworkCreateResponse, error) {
+	c.created = append(c.created, name)
+	return moby.NetworkCreateResponse{ID: name}, nil
+}
+
+func testNetworksProject() *types.Project {
+	ipam := types.IPAMConfig{Config: []*types.IPAMPool{{Subnet: "172.28.0.0/16", Gateway: "172.28.0.1"}}}
+	return &types.Project{
+		Name: "demo",
+		Services: types.Services{
+			{Name: "web", Networks: map[string]*types.ServiceNetworkConfig{"front": nil}, DependsOn: types.DependsOnConfig{"db": {}}},
+			{Name: "db", Networks: map[string]*types.ServiceNetworkConfig{"back": nil, "shared": nil}},
+			{Name: "worker", Networks: map[string]*types.ServiceNetworkConfig{"jobs": nil, "outside": nil}},
+		},
+		Networks: types.Networks{
+			"front":   {Name: "demo_front", Ipam: ipam},
+			"back":    {Name: "demo_back", Ipam: ipam},
+			"shared":  {Name: "shared", External: types.External{External: true}, Ipam: ipam},
+			"jobs":    {Name: "demo_jobs", Ipam: ipam},
+			"outside": {Name: "outside", External: types.External{External: true}},
+		},
+	}
+}
+
+func TestCreateIPAMNetworks(t *testing.T) {
+	testCases := []struct {
+		name     string
+		services []string
+		existing map[string]bool
+		created  []string
+	}{
+		{
+			name:    "all services",
+			created: []string{"demo_back", "demo_front", "demo_jobs"},
+		},
+		{
+			name:     "selected service and dependencies",
+			services: []string{"web"},
+			created:  []string{"demo_back", "demo_front"},
+		},
+		{
+			name:     "existing network",
+			services: []string{"web"},
+			existing: map[string]bool{"demo_front": true},
+			created:  []string{"demo_back"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			apiClient := &fakeNetworksClient{existing: tc.existing}
+			s := composeService{apiClient: apiClient}
+			err := s.createIPAMNetworks(context.Background(), testNetworksProject(), tc.services...)
+			assert.NilError(t, err)
+			sort.Strings(apiClient.created)
+			assert.DeepEqual(t, apiClient.created, tc.created)
+		})
+	}
+}
+
+func TestCheckExternalNetworks(t *testing.T) {
+	apiClient := &fakeNetworksClient{existing: map[string]bool{"shared": true}}
+	s := composeService{apiClient: apiClient}
+
+	assert.NilError(t, s.checkExternalNetworks(context.Background(), testNetworksProject(), "web", "db"))
+
+	err := s.checkExternalNetworks(context.Background(), testNetworksProject())
+	assert.Error(t, err, `network outside declared as external for services worker could not be found, create it with "docker network create outside"`)
+
+	// the networks of the dependencies are checked too
+	s = composeService{apiClient: &fakeNetworksClient{existing: map[string]bool{}}}
+	err = s.checkExternalNetworks(context.Background(), testNetworksProject(), "web")
+	assert.Error(t, err, `network shared declared as external for services db could not be found, create it with "docker network create shared"`)
+}
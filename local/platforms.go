@@ -0,0 +1,133 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/client"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultPlatformVariable sets the platform of the services not setting
+	// one
+	defaultPlatformVariable = "DOCKER_DEFAULT_PLATFORM"
+	// platformAPIVersion is the first engine API version creating containers
+	// for a platform
+	platformAPIVersion = "1.41"
+)
+
+// withDefaultPlatform sets the platform of the services not setting one to
+// DOCKER_DEFAULT_PLATFORM and normalizes the platforms of the services. The
+// variable is removed from the project environment, the compose library
+// building the images of the services for both platforms otherwise.
+func withDefaultPlatform(project *types.Project) error {
+	defaultPlatform := project.Environment[defaultPlatformVariable]
+	for i, service := range project.Services {
+		if service.Platform == "" {
+			service.Platform = defaultPlatform
+		}
+		if service.Platform == "" {
+			continue
+		}
+		p, err := platforms.Parse(service.Platform)
+		if err != nil {
+			return errors.Wrapf(err, "service %q: invalid platform %q", service.Name, service.Platform)
+		}
+		project.Services[i].Platform = platforms.Format(platforms.Normalize(p))
+	}
+	delete(project.Environment, defaultPlatformVariable)
+	return nil
+}
+
+// checkPlatforms checks the engine can run containers for the platforms of
+// the services: the containers must run the operating system of the engine,
+// other architectures being emulated, and the engine API must support setting
+// the platform of containers.
+func (s composeService) checkPlatforms(ctx context.Context, project *types.Project) error {
+	var osType string
+	for _, service := range project.Services {
+		if service.Platform == "" {
+			continue
+		}
+		if osType == "" {
+			info, err := s.apiClient.Info(ctx)
+			if err != nil {
+				return err
+			}
+			osType = info.OSType
+			if version := s.apiClient.ClientVersion(); versions.LessThan(version, platformAPIVersion) {
+				return errors.Errorf("service %q: setting the platform of containers requires engine API %s, the engine supports %s", service.Name, platformAPIVersion, version)
+			}
+		}
+		p, err := platforms.Parse(service.Platform)
+		if err != nil {
+			return err
+		}
+		if p.OS != osType {
+			return errors.Errorf("service %q: platform %s is not supported by the engine, which runs %s containers", service.Name, service.Platform, osType)
+		}
+	}
+	return nil
+}
+
+// pullPlatformImages pulls the images of the services setting a platform
+// which are present for another platform. The compose library only pulls
+// missing images, the engine then failing to create the containers.
+func (s composeService) pullPlatformImages(ctx context.Context, project *types.Project, quiet bool) error {
+	var pulled types.Services
+	for _, service := range project.Services {
+		switch {
+		case service.Platform == "" || service.Image == "" || service.Build != nil:
+			continue
+		case service.PullPolicy == types.PullPolicyAlways:
+			// pulled by the compose library
+			continue
+		}
+		image, _, err := s.apiClient.ImageInspectWithRaw(ctx, service.Image)
+		if client.IsErrNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		p, err := platforms.Parse(service.Platform)
+		if err != nil {
+			return err
+		}
+		imagePlatform := specs.Platform{OS: image.Os, Architecture: image.Architecture, Variant: image.Variant}
+		if platforms.Only(p).Match(imagePlatform) {
+			continue
+		}
+		if service.PullPolicy == types.PullPolicyNever {
+			return errors.Errorf("service %q: image %s is present for platform %s, not %s, and the pull policy is never", service.Name, service.Image, platforms.Format(imagePlatform), service.Platform)
+		}
+		pulled = append(pulled, service)
+	}
+	if len(pulled) == 0 {
+		return nil
+	}
+	pullProject := *project
+	pullProject.Services = pulled
+	return s.Service.Pull(ctx, &pullProject, api.PullOptions{Quiet: quiet})
+}
@@ -0,0 +1,79 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/progress"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+// pull pulls the images of the services concurrently, continuing past the
+// failures the compose library stops at. The services built locally, with
+// the build pull policy, are skipped. Once all images are pulled, the failures
+// are summarized, returned as an error unless ignored or all of them are
+// services which can be built instead.
+func (s composeService) pull(ctx context.Context, project *types.Project, options api.PullOptions) error {
+	var (
+		mu       sync.Mutex
+		failures []utils.PullFailure
+	)
+	pullAll := func(ctx context.Context) error {
+		w := progress.ContextWriter(ctx)
+		var wg sync.WaitGroup
+		for _, service := range project.Services {
+			if service.Image == "" || service.PullPolicy == types.PullPolicyBuild {
+				w.Event(progress.Event{ID: service.Name, Status: progress.Done, Text: "Skipped"})
+				continue
+			}
+			wg.Add(1)
+			go func(service types.ServiceConfig) {
+				defer wg.Done()
+				build := service.Build != nil
+				// the failures of services with a build section are reported
+				// once all images are pulled
+				service.Build = nil
+				pullProject := *project
+				pullProject.Services = types.Services{service}
+				// the progress is reported by the writer of the context
+				err := s.Service.Pull(ctx, &pullProject, api.PullOptions{Quiet: true})
+				if err != nil {
+					mu.Lock()
+					failures = append(failures, utils.PullFailure{Service: service.Name, Build: build, Err: err})
+					mu.Unlock()
+				}
+			}(service)
+		}
+		wg.Wait()
+		return nil
+	}
+	if options.Quiet {
+		_ = pullAll(ctx)
+	} else if err := progress.Run(ctx, pullAll); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return utils.ReportPullFailures(os.Stderr, failures, options.IgnoreFailures)
+}
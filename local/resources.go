@@ -0,0 +1,84 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"strconv"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/cli/opts"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+// withDeployResources returns a copy of the project with the deploy resources
+// of the services set to the container resources the compose library creates
+// the containers with, so that local runs get the limits they have once
+// deployed. The compose library only applies the memory limit, the cpus limit
+// being parsed as an integer number of nano CPUs, so the copy sets it as such.
+func withDeployResources(project *types.Project) (*types.Project, error) {
+	copied := *project
+	copied.Services = make(types.Services, len(project.Services))
+	for i, service := range project.Services {
+		copied.Services[i] = service
+		if service.Deploy == nil {
+			continue
+		}
+		deploy := *service.Deploy
+		service.Deploy = &deploy
+		if deploy.Resources.Limits != nil {
+			limits := *deploy.Resources.Limits
+			deploy.Resources.Limits = &limits
+			if limits.NanoCPUs != "" {
+				nanoCPUs, err := opts.ParseCPUs(limits.NanoCPUs)
+				if err != nil {
+					return nil, errors.Wrapf(err, "service %q: invalid cpus limit %q", service.Name, limits.NanoCPUs)
+				}
+				limits.NanoCPUs = strconv.FormatInt(nanoCPUs, 10)
+			}
+			if pids, ok := utils.ResourcePids(&limits); ok && service.PidsLimit == 0 {
+				service.PidsLimit = pids
+			}
+		}
+		if deploy.Resources.Reservations != nil {
+			reservations := *deploy.Resources.Reservations
+			deploy.Resources.Reservations = &reservations
+			devices := reservations.Devices
+			reservations.Devices = nil
+			for _, device := range devices {
+				// devices reserved without count nor ids are all reserved
+				if device.Count == 0 && len(device.IDs) == 0 {
+					device.Count = -1
+				}
+				reservations.Devices = append(reservations.Devices, device)
+			}
+			if reservations.MemoryBytes != 0 && service.MemReservation == 0 {
+				service.MemReservation = reservations.MemoryBytes
+			}
+			if reservations.NanoCPUs != "" {
+				logrus.Warnf("service %q: the engine can't reserve cpus, the cpus reservation is ignored", service.Name)
+			}
+			if _, ok := utils.ResourcePids(&reservations); ok {
+				logrus.Warnf("service %q: the engine can't reserve pids, the pids reservation is ignored", service.Name)
+			}
+		}
+		copied.Services[i] = service
+	}
+	return &copied, nil
+}
@@ -0,0 +1,51 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"github.com/compose-spec/compose-go/types"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/api/containers"
+	"github.com/docker/compose-cli/local/moby"
+)
+
+// withRestartPolicies sets the conditions of the deploy restart policies of
+// the services to the engine restart policies they stand for, the compose
+// library passing the any and none conditions to the engine, which rejects
+// them. It must be called on a project copied by withDeployResources.
+func withRestartPolicies(project *types.Project) {
+	for i, service := range project.Services {
+		if service.Deploy == nil || service.Deploy.RestartPolicy == nil {
+			continue
+		}
+		policy := *service.Deploy.RestartPolicy
+		condition := policy.Condition
+		if condition == "" {
+			condition = containers.RestartPolicyAny
+		}
+		policy.Condition = moby.ToRestartPolicy(condition).Name
+		if policy.MaxAttempts != nil && condition != containers.RestartPolicyOnFailure {
+			logrus.Warnf("service %q: the engine only limits the restart attempts of the on-failure restart policy, max_attempts is ignored", service.Name)
+			policy.MaxAttempts = nil
+		}
+		if policy.Delay != nil || policy.Window != nil {
+			logrus.Warnf("service %q: the engine can't delay restarts, the restart policy delay and window are ignored", service.Name)
+		}
+		project.Services[i].Deploy.RestartPolicy = &policy
+	}
+}
@@ -0,0 +1,73 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+)
+
+// inlineSeccompProfiles removes the security options setting a seccomp
+// profile file from the services, returning them by service with the content
+// of the file inlined, as the engine expects. The compose library inlines the
+// profile files in the security options of the services themselves, failing
+// to create the second container of a scaled service, whose option is then
+// inlined already.
+func inlineSeccompProfiles(project *types.Project) (map[string]string, error) {
+	profiles := map[string]string{}
+	for i, service := range project.Services {
+		var opts []string
+		for _, opt := range service.SecurityOpt {
+			file, ok := seccompProfileFile(opt)
+			if !ok {
+				opts = append(opts, opt)
+				continue
+			}
+			content, err := os.ReadFile(project.RelativePath(file))
+			if err != nil {
+				return nil, errors.Errorf("opening seccomp profile (%s) failed: %v", file, err)
+			}
+			var b bytes.Buffer
+			if err := json.Compact(&b, content); err != nil {
+				return nil, errors.Errorf("compacting json for seccomp profile (%s) failed: %v", file, err)
+			}
+			profiles[service.Name] = "seccomp=" + b.String()
+		}
+		if _, ok := profiles[service.Name]; ok {
+			project.Services[i].SecurityOpt = opts
+		}
+	}
+	return profiles, nil
+}
+
+// seccompProfileFile returns the seccomp profile file set by a security
+// option, in the seccomp=<file> or legacy seccomp:<file> forms.
+func seccompProfileFile(opt string) (string, bool) {
+	con := strings.SplitN(opt, "=", 2)
+	if len(con) == 1 {
+		con = strings.SplitN(opt, ":", 2)
+	}
+	if len(con) != 2 || con[0] != "seccomp" || con[1] == "unconfined" || con[1] == "" {
+		return "", false
+	}
+	return con[1], true
+}
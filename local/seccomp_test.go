@@ -0,0 +1,72 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestSeccompProfileFile(t *testing.T) {
+	testCases := []struct {
+		opt  string
+		file string
+		ok   bool
+	}{
+		{opt: "seccomp=profile.json", file: "profile.json", ok: true},
+		{opt: "seccomp:/etc/profile.json", file: "/etc/profile.json", ok: true},
+		{opt: "seccomp=unconfined"},
+		{opt: "seccomp="},
+		{opt: "apparmor=unconfined"},
+		{opt: "no-new-privileges"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.opt, func(t *testing.T) {
+			file, ok := seccompProfileFile(tc.opt)
+			assert.Equal(t, ok, tc.ok)
+			assert.Equal(t, file, tc.file)
+		})
+	}
+}
+
+func TestInlineSeccompProfiles(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "profile.json"), []byte("{\n  \"defaultAction\": \"SCMP_ACT_ERRNO\"\n}\n"), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "invalid.json"), []byte("{"), 0o600))
+
+	project := &types.Project{WorkingDir: dir, Services: types.Services{
+		{Name: "web", SecurityOpt: []string{"no-new-privileges", "seccomp=profile.json"}},
+		{Name: "db", SecurityOpt: []string{"seccomp=unconfined"}},
+	}}
+	profiles, err := inlineSeccompProfiles(project)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, profiles, map[string]string{"web": `seccomp={"defaultAction":"SCMP_ACT_ERRNO"}`})
+	assert.DeepEqual(t, project.Services[0].SecurityOpt, []string{"no-new-privileges"})
+	assert.DeepEqual(t, project.Services[1].SecurityOpt, []string{"seccomp=unconfined"})
+
+	project = &types.Project{WorkingDir: dir, Services: types.Services{{Name: "web", SecurityOpt: []string{"seccomp=missing.json"}}}}
+	_, err = inlineSeccompProfiles(project)
+	assert.ErrorContains(t, err, "opening seccomp profile (missing.json) failed")
+
+	project = &types.Project{WorkingDir: dir, Services: types.Services{{Name: "web", SecurityOpt: []string{"seccomp=invalid.json"}}}}
+	_, err = inlineSeccompProfiles(project)
+	assert.ErrorContains(t, err, "compacting json for seccomp profile (invalid.json) failed")
+}
@@ -0,0 +1,118 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+// sharedMemoryDir is the tmpfs mount shared by the users on Linux
+var sharedMemoryDir = "/dev/shm"
+
+// writeEnvironmentSecrets writes the secrets sourced from environment
+// variables to files in a private runtime directory, which the engine bind
+// mounts like file secrets, see secretsRuntimeDir. The files are removed by
+// compose down.
+func writeEnvironmentSecrets(project *types.Project) error {
+	var dir string
+	secrets := types.Secrets{}
+	for name, secret := range project.Secrets {
+		secrets[name] = secret
+	}
+	for name, secret := range project.Secrets {
+		if _, ok := utils.SecretEnvironment(secret); !ok {
+			continue
+		}
+		content, err := utils.SecretContent(project, name, secret)
+		if err != nil {
+			return err
+		}
+		if dir == "" {
+			base, err := secretsRuntimeDir()
+			if err != nil {
+				return errors.Wrapf(err, "secret %q", name)
+			}
+			if err := privateDir(base); err != nil {
+				return err
+			}
+			dir = filepath.Join(base, project.Name, "secrets")
+			if err := os.MkdirAll(dir, 0o700); err != nil {
+				return err
+			}
+		}
+		file := filepath.Join(dir, name)
+		// the file is read-only once written
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err := os.WriteFile(file, content, 0o444); err != nil {
+			return err
+		}
+		secret.File = file
+		secrets[name] = secret
+	}
+	project.Secrets = secrets
+	return nil
+}
+
+// removeEnvironmentSecrets removes the files of the secrets sourced from
+// environment variables written for the project
+func removeEnvironmentSecrets(projectName string) error {
+	base, err := secretsRuntimeDir()
+	if err != nil {
+		return nil
+	}
+	return os.RemoveAll(filepath.Join(base, projectName))
+}
+
+// secretsRuntimeDir returns the directory the secrets sourced from environment
+// variables are written to, in the user runtime directory set by
+// XDG_RUNTIME_DIR or else in /dev/shm, both tmpfs mounts on Linux, so that
+// secret values are not persisted to disk. Elsewhere, as on macOS, they are
+// written to the temporary directory until compose down.
+func secretsRuntimeDir() (string, error) {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "docker-compose"), nil
+	}
+	if info, err := os.Stat(sharedMemoryDir); err == nil && info.IsDir() {
+		return filepath.Join(sharedMemoryDir, fmt.Sprintf("docker-compose-%d", os.Getuid())), nil
+	}
+	return temporarySecretsDir()
+}
+
+// privateDir creates the directory when missing and checks it is only
+// accessible by the user, as another user may have created it.
+func privateDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() || info.Mode().Perm()&0o077 != 0 || !ownedByUser(info) {
+		return errors.Errorf("directory %s must be owned by the user and only accessible by them", dir)
+	}
+	return nil
+}
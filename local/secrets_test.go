@@ -0,0 +1,113 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+func TestWriteEnvironmentSecrets(t *testing.T) {
+	runtimeDir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+	project := &types.Project{
+		Name:        "demo",
+		Environment: map[string]string{"TOKEN": "s3cr3t"},
+		Secrets: types.Secrets{
+			"token": types.SecretConfig{Extensions: map[string]interface{}{utils.SecretEnvironmentExtension: "TOKEN"}},
+			"key":   types.SecretConfig{File: "/src/key.pem"},
+		},
+	}
+
+	secrets := project.Secrets
+	assert.NilError(t, writeEnvironmentSecrets(project))
+	file := filepath.Join(runtimeDir, "docker-compose", "demo", "secrets", "token")
+	assert.Equal(t, project.Secrets["token"].File, file)
+	// the original secrets are left as is
+	assert.Equal(t, secrets["token"].File, "")
+	assert.Equal(t, project.Secrets["key"].File, "/src/key.pem")
+	content, err := os.ReadFile(file)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "s3cr3t")
+	info, err := os.Stat(filepath.Join(runtimeDir, "docker-compose"))
+	assert.NilError(t, err)
+	assert.Equal(t, info.Mode().Perm(), os.FileMode(0o700))
+
+	// secrets are written again with their new value
+	project.Environment["TOKEN"] = "changed"
+	assert.NilError(t, writeEnvironmentSecrets(project))
+	content, err = os.ReadFile(file)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "changed")
+
+	assert.NilError(t, removeEnvironmentSecrets("demo"))
+	_, err = os.Stat(filepath.Join(runtimeDir, "docker-compose", "demo"))
+	assert.Assert(t, os.IsNotExist(err))
+}
+
+func TestWriteEnvironmentSecretsSharedDir(t *testing.T) {
+	runtimeDir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+	assert.NilError(t, os.Mkdir(filepath.Join(runtimeDir, "docker-compose"), 0o777))
+	assert.NilError(t, os.Chmod(filepath.Join(runtimeDir, "docker-compose"), 0o777))
+	project := &types.Project{
+		Name:        "demo",
+		Environment: map[string]string{"TOKEN": "s3cr3t"},
+		Secrets: types.Secrets{
+			"token": types.SecretConfig{Extensions: map[string]interface{}{utils.SecretEnvironmentExtension: "TOKEN"}},
+		},
+	}
+	err := writeEnvironmentSecrets(project)
+	assert.Error(t, err, "directory "+filepath.Join(runtimeDir, "docker-compose")+" must be owned by the user and only accessible by them")
+}
+
+func TestWriteEnvironmentSecretsTemporaryDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("environment secrets aren't supported on Windows")
+	}
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	t.Setenv("TMPDIR", tmpDir)
+	defer func(dir string) { sharedMemoryDir = dir }(sharedMemoryDir)
+	sharedMemoryDir = filepath.Join(tmpDir, "missing")
+	project := &types.Project{
+		Name:        "demo",
+		Environment: map[string]string{"TOKEN": "s3cr3t"},
+		Secrets: types.Secrets{
+			"token": types.SecretConfig{Extensions: map[string]interface{}{utils.SecretEnvironmentExtension: "TOKEN"}},
+		},
+	}
+
+	assert.NilError(t, writeEnvironmentSecrets(project))
+	base := filepath.Join(tmpDir, fmt.Sprintf("docker-compose-%d", os.Getuid()))
+	assert.Equal(t, project.Secrets["token"].File, filepath.Join(base, "demo", "secrets", "token"))
+	info, err := os.Stat(base)
+	assert.NilError(t, err)
+	assert.Equal(t, info.Mode().Perm(), os.FileMode(0o700))
+
+	assert.NilError(t, removeEnvironmentSecrets("demo"))
+	_, err = os.Stat(filepath.Join(base, "demo"))
+	assert.Assert(t, os.IsNotExist(err))
+}
@@ -0,0 +1,35 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// ownedByUser reports whether the file is owned by the user. Environment
+// secrets are not supported on Windows, there being no runtime directory.
+func ownedByUser(info os.FileInfo) bool {
+	return false
+}
+
+// temporarySecretsDir returns an error, as the ownership of the temporary
+// directory can't be checked.
+func temporarySecretsDir() (string, error) {
+	return "", errors.New("secrets sourced from environment variables are not supported on Windows, use file secrets instead")
+}
@@ -0,0 +1,57 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+// withTmpfsModes replaces the tmpfs volumes setting the mode of the mount
+// with tmpfs mounts of the services, with matching options. The compose
+// library only sets the size of tmpfs volumes, the mounts it creates from
+// them having no mode option.
+func withTmpfsModes(project *types.Project) {
+	for i, service := range project.Services {
+		var volumes []types.ServiceVolumeConfig
+		var replaced bool
+		for _, volume := range service.Volumes {
+			mode, ok := utils.TmpfsMode(volume.Tmpfs)
+			if volume.Type != types.VolumeTypeTmpfs || !ok {
+				volumes = append(volumes, volume)
+				continue
+			}
+			options := []string{fmt.Sprintf("mode=%o", mode)}
+			if volume.Tmpfs.Size > 0 {
+				options = append(options, fmt.Sprintf("size=%d", volume.Tmpfs.Size))
+			}
+			if volume.ReadOnly {
+				options = append(options, "ro")
+			}
+			service.Tmpfs = append(append(types.StringList{}, service.Tmpfs...), volume.Target+":"+strings.Join(options, ","))
+			replaced = true
+		}
+		if replaced {
+			service.Volumes = volumes
+			project.Services[i] = service
+		}
+	}
+}
@@ -0,0 +1,75 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+func TestWithTmpfsModes(t *testing.T) {
+	tmpfs := func(target string, mode interface{}, size types.UnitBytes, readOnly bool) types.ServiceVolumeConfig {
+		volume := types.ServiceVolumeConfig{Type: types.VolumeTypeTmpfs, Target: target, ReadOnly: readOnly, Tmpfs: &types.ServiceVolumeTmpfs{Size: size}}
+		if mode != nil {
+			volume.Tmpfs.Extensions = map[string]interface{}{utils.TmpfsModeExtension: mode}
+		}
+		return volume
+	}
+	data := types.ServiceVolumeConfig{Type: types.VolumeTypeVolume, Source: "data", Target: "/data"}
+
+	testCases := []struct {
+		name    string
+		service types.ServiceConfig
+		volumes []types.ServiceVolumeConfig
+		tmpfs   types.StringList
+	}{
+		{
+			name:    "octal integer",
+			service: types.ServiceConfig{Volumes: []types.ServiceVolumeConfig{tmpfs("/tmp", 0o1777, 0, false), data}},
+			volumes: []types.ServiceVolumeConfig{data},
+			tmpfs:   types.StringList{"/tmp:mode=1777"},
+		},
+		{
+			name:    "octal string with size and read only",
+			service: types.ServiceConfig{Tmpfs: types.StringList{"/run"}, Volumes: []types.ServiceVolumeConfig{tmpfs("/cache", "700", 1024, true)}},
+			tmpfs:   types.StringList{"/run", "/cache:mode=700,size=1024,ro"},
+		},
+		{
+			name:    "no mode",
+			service: types.ServiceConfig{Volumes: []types.ServiceVolumeConfig{tmpfs("/tmp", nil, 1024, false), data}},
+			volumes: []types.ServiceVolumeConfig{tmpfs("/tmp", nil, 1024, false), data},
+		},
+		{
+			name:    "invalid mode",
+			service: types.ServiceConfig{Volumes: []types.ServiceVolumeConfig{tmpfs("/tmp", "rwx", 0, false)}},
+			volumes: []types.ServiceVolumeConfig{tmpfs("/tmp", "rwx", 0, false)},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.service.Name = "web"
+			project := &types.Project{Services: types.Services{tc.service}}
+			withTmpfsModes(project)
+			assert.DeepEqual(t, project.Services[0].Volumes, tc.volumes)
+			assert.DeepEqual(t, project.Services[0].Tmpfs, tc.tmpfs)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+
+	"github.com/compose-spec/compose-go/types"
+)
+
+const (
+	// BuildPlatformsExtension is the build extension the platforms attribute
+	// of build sections is moved to when resolving compose files, as the
+	// compose loader rejects it. It lists the platforms to build the service
+	// image for.
+	BuildPlatformsExtension = "x-platforms"
+	// BuildCacheToExtension is the build extension the cache_to attribute of
+	// build sections is moved to when resolving compose files, as the compose
+	// loader rejects it. It lists the exports of the build cache, in the
+	// format of the --cache-to flag of buildx.
+	BuildCacheToExtension = "x-cache-to"
+	// DockerfileInlineExtension is the build extension the dockerfile_inline
+	// attribute of build sections is moved to when resolving compose files,
+	// as the compose loader rejects it. It holds the content of the
+	// Dockerfile.
+	DockerfileInlineExtension = "x-dockerfile-inline"
+	// BuildSecretsExtension is the build extension the secrets attribute of
+	// build sections is moved to when resolving compose files, as the compose
+	// loader rejects it. It lists the secrets of the project available to
+	// the build, by name or with the source and target attributes of service
+	// secrets.
+	BuildSecretsExtension = "x-secrets"
+)
+
+// movedBuildAttributes are the attributes of build sections moved to
+// extensions, by extension
+var movedBuildAttributes = map[string]string{
+	BuildPlatformsExtension:   "platforms",
+	BuildCacheToExtension:     "cache_to",
+	DockerfileInlineExtension: "dockerfile_inline",
+	BuildSecretsExtension:     "secrets",
+}
+
+// BuildPlatforms returns the platforms the build section lists, if any.
+func BuildPlatforms(build *types.BuildConfig) ([]string, error) {
+	return buildExtensionList(build, BuildPlatformsExtension)
+}
+
+// BuildCacheTo returns the build cache exports the build section lists, if
+// any.
+func BuildCacheTo(build *types.BuildConfig) ([]string, error) {
+	return buildExtensionList(build, BuildCacheToExtension)
+}
+
+// DockerfileInline returns the content of the Dockerfile the build section
+// embeds, if any.
+func DockerfileInline(build *types.BuildConfig) (string, bool) {
+	content, ok := buildExtension(build, DockerfileInlineExtension).(string)
+	return content, ok
+}
+
+// BuildSecrets returns the secrets the build section lists, if any.
+func BuildSecrets(build *types.BuildConfig) ([]types.ServiceSecretConfig, error) {
+	ext := buildExtension(build, BuildSecretsExtension)
+	if ext == nil {
+		return nil, nil
+	}
+	list, ok := ext.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid build secrets %v, must be a list", ext)
+	}
+	secrets := make([]types.ServiceSecretConfig, 0, len(list))
+	for _, s := range list {
+		switch v := s.(type) {
+		case string:
+			secrets = append(secrets, types.ServiceSecretConfig{Source: v})
+		case map[string]interface{}:
+			source, _ := v["source"].(string)
+			target, _ := v["target"].(string)
+			if source == "" {
+				return nil, fmt.Errorf("invalid build secret %v, the source is required", v)
+			}
+			secrets = append(secrets, types.ServiceSecretConfig{Source: source, Target: target})
+		default:
+			return nil, fmt.Errorf("invalid build secret %v, must be a name or a mapping", s)
+		}
+	}
+	return secrets, nil
+}
+
+func buildExtension(build *types.BuildConfig, extension string) interface{} {
+	if build == nil {
+		return nil
+	}
+	ext, ok := build.Extensions[extension]
+	if !ok {
+		// compose-go keeps the build extensions in a nested map
+		if nested, isMap := build.Extensions["extensions"].(map[string]interface{}); isMap {
+			ext = nested[extension]
+		}
+	}
+	return ext
+}
+
+func buildExtensionList(build *types.BuildConfig, extension string) ([]string, error) {
+	ext := buildExtension(build, extension)
+	name := movedBuildAttributes[extension]
+	switch v := ext.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, value := range v {
+			s, isString := value.(string)
+			if !isString {
+				return nil, fmt.Errorf("invalid build %s entry %v, must be a string", name, value)
+			}
+			values = append(values, s)
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("invalid build %s %v, must be a list", name, ext)
+}
+
+// moveBuildAttributes moves the build attributes of the services of the
+// compose model to their extensions, see movedBuildAttributes, and reports
+// whether any build section sets one. Setting both dockerfile and
+// dockerfile_inline is an error.
+func moveBuildAttributes(dict map[string]interface{}) (bool, error) {
+	services, _ := dict["services"].(map[string]interface{})
+	var moved bool
+	for name, s := range services {
+		service, _ := s.(map[string]interface{})
+		build, ok := service["build"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		_, dockerfile := build["dockerfile"]
+		if _, inline := build["dockerfile_inline"]; inline && dockerfile {
+			return false, fmt.Errorf("service %q: build dockerfile and dockerfile_inline can't both be set", name)
+		}
+		for extension, attribute := range movedBuildAttributes {
+			if value, ok := build[attribute]; ok {
+				build[extension] = value
+				delete(build, attribute)
+				moved = true
+			}
+		}
+	}
+	return moved, nil
+}
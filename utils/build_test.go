@@ -0,0 +1,106 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/loader"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestBuildAttributes(t *testing.T) {
+	dir := t.TempDir()
+	compose := filepath.Join(dir, "compose.yaml")
+	writeFile(t, compose, `
+services:
+  app:
+    build:
+      context: .
+      platforms:
+        - linux/amd64
+        - linux/arm64
+      cache_to:
+        - type=local,dest=/tmp/cache
+      secrets:
+        - token
+        - source: key
+          target: api_key
+  worker:
+    build:
+      context: .
+      target: dev
+      dockerfile_inline: |
+        FROM alpine
+        RUN apk add curl
+secrets:
+  token:
+    environment: TOKEN
+  key:
+    file: ./key.pem
+`)
+
+	b, err := ResolveComposeFile(compose, nil, false)
+	assert.NilError(t, err)
+	project, err := loader.Load(types.ConfigDetails{
+		WorkingDir:  dir,
+		ConfigFiles: []types.ConfigFile{{Filename: compose, Content: b}},
+	})
+	assert.NilError(t, err)
+
+	app, err := project.GetService("app")
+	assert.NilError(t, err)
+	platforms, err := BuildPlatforms(app.Build)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, platforms, []string{"linux/amd64", "linux/arm64"})
+	cacheTo, err := BuildCacheTo(app.Build)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cacheTo, []string{"type=local,dest=/tmp/cache"})
+	secrets, err := BuildSecrets(app.Build)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, secrets, []types.ServiceSecretConfig{{Source: "token"}, {Source: "key", Target: "api_key"}})
+	assert.Assert(t, app.Secrets == nil)
+
+	_, ok := DockerfileInline(app.Build)
+	assert.Assert(t, !ok)
+
+	worker, err := project.GetService("worker")
+	assert.NilError(t, err)
+	platforms, err = BuildPlatforms(worker.Build)
+	assert.NilError(t, err)
+	assert.Equal(t, len(platforms), 0)
+	secrets, err = BuildSecrets(worker.Build)
+	assert.NilError(t, err)
+	assert.Equal(t, len(secrets), 0)
+	assert.Equal(t, worker.Build.Target, "dev")
+	dockerfile, ok := DockerfileInline(worker.Build)
+	assert.Assert(t, ok)
+	assert.Equal(t, dockerfile, "FROM alpine\nRUN apk add curl\n")
+
+	writeFile(t, compose, `
+services:
+  app:
+    build:
+      context: .
+      dockerfile: Dockerfile.dev
+      dockerfile_inline: FROM alpine
+`)
+	_, err = ResolveComposeFile(compose, nil, false)
+	assert.ErrorContains(t, err, `service "app": build dockerfile and dockerfile_inline can't both be set`)
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/docker/compose/v2/pkg/api"
 	"github.com/hashicorp/go-multierror"
@@ -38,3 +39,23 @@ func CheckUnsupported(ctx context.Context, errs error, toCheck, expectedValue in
 	}
 	return errs
 }
+
+// DefaultRestartTimeout is the timeout the compose library restart command
+// always sets, when --timeout isn't given.
+const DefaultRestartTimeout = 10 * time.Second
+
+// CheckUnsupportedRestartTimeout adds an error to errs when the restart
+// timeout differs from the DefaultRestartTimeout, for backends which stop
+// containers with their own grace period.
+func CheckUnsupportedRestartTimeout(ctx context.Context, errs error, timeout *time.Duration) error {
+	set := timeout != nil && *timeout != DefaultRestartTimeout
+	return CheckUnsupported(ctx, errs, set, false, "restart", "timeout")
+}
+
+// UnsupportedCommand returns an error telling the command isn't supported by
+// the backend of the context type, followed by a hint at an alternative.
+func UnsupportedCommand(ctx context.Context, commandName, hint string) error {
+	ctype := ctx.Value(config.ContextTypeKey).(string)
+	return errors.Wrapf(api.ErrNotImplemented, "%q is not supported on context type %s, %s",
+		commandName, strings.ToUpper(ctype), hint)
+}
@@ -0,0 +1,35 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/config"
+)
+
+func TestUnsupportedCommand(t *testing.T) {
+	ctx := context.WithValue(context.Background(), config.ContextTypeKey, "ecs")
+	err := UnsupportedCommand(ctx, "pause", "ECS tasks can't be frozen")
+	assert.Assert(t, errors.Is(err, api.ErrNotImplemented))
+	assert.Error(t, err, `"pause" is not supported on context type ECS, ECS tasks can't be frozen: not implemented`)
+}
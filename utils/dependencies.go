@@ -0,0 +1,66 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"github.com/compose-spec/compose-go/types"
+)
+
+// DisabledDependencies returns the dependencies of the project services that
+// have been disabled by up --no-deps, services disabled by profiles aside
+func DisabledDependencies(project *types.Project) []string {
+	var names []string
+	for _, d := range project.DisabledServices {
+		if len(d.Profiles) > 0 {
+			continue
+		}
+		for _, s := range project.Services {
+			if _, ok := s.DependsOn[d.Name]; ok {
+				names = append(names, d.Name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// KeepDisabledDependencies returns a copy of the project with the
+// dependencies disabled by up --no-deps added back, for backends deploying the
+// whole project at once: left out, they would be removed, while their
+// unchanged definitions leave them untouched. The project itself isn't
+// modified.
+func KeepDisabledDependencies(project *types.Project) *types.Project {
+	kept := *project
+	kept.Services = append(types.Services{}, project.Services...)
+	kept.DisabledServices = append(types.Services{}, project.DisabledServices...)
+	for names := DisabledDependencies(&kept); len(names) > 0; names = DisabledDependencies(&kept) {
+		restore := map[string]bool{}
+		for _, name := range names {
+			restore[name] = true
+		}
+		var disabled types.Services
+		for _, s := range kept.DisabledServices {
+			if restore[s.Name] {
+				kept.Services = append(kept.Services, s)
+			} else {
+				disabled = append(disabled, s)
+			}
+		}
+		kept.DisabledServices = disabled
+	}
+	return &kept
+}
@@ -0,0 +1,49 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestKeepDisabledDependencies(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			{Name: "web", DependsOn: types.DependsOnConfig{"api": {}}},
+		},
+		DisabledServices: types.Services{
+			{Name: "api", DependsOn: types.DependsOnConfig{"db": {}}},
+			{Name: "db"},
+			{Name: "debug", Profiles: []string{"debug"}},
+			{Name: "worker"},
+		},
+	}
+	assert.DeepEqual(t, DisabledDependencies(project), []string{"api"})
+	assert.NilError(t, CheckProfiles(project))
+
+	kept := KeepDisabledDependencies(project)
+	assert.DeepEqual(t, kept.ServiceNames(), []string{"api", "db", "web"})
+	assert.Equal(t, len(kept.DisabledServices), 2)
+	assert.Equal(t, len(DisabledDependencies(kept)), 0)
+
+	// the caller's project is left as is
+	assert.DeepEqual(t, project.ServiceNames(), []string{"web"})
+	assert.Equal(t, len(project.DisabledServices), 4)
+}
@@ -0,0 +1,59 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+type dryRunKey struct{}
+
+// WithDryRun returns a context telling backends to print the operations of
+// mutating commands instead of performing them
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// IsDryRun tells whether the operations must only be printed
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// Operation is an operation a command would perform on a resource
+type Operation struct {
+	Action   string
+	Resource string
+	Name     string
+}
+
+// PrintOperations prints the operations, one per line, e.g.
+// "Would create container demo-web-1"
+func PrintOperations(out io.Writer, operations []Operation) error {
+	if len(operations) == 0 {
+		_, err := fmt.Fprintln(out, "Nothing to do")
+		return err
+	}
+	for _, op := range operations {
+		if _, err := fmt.Fprintf(out, "Would %s %s %s\n", op.Action, op.Resource, op.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	assert.Assert(t, !IsDryRun(context.Background()))
+	assert.Assert(t, IsDryRun(WithDryRun(context.Background())))
+}
+
+func TestPrintOperations(t *testing.T) {
+	var out bytes.Buffer
+	assert.NilError(t, PrintOperations(&out, []Operation{
+		{Action: "pull", Resource: "image", Name: "nginx"},
+		{Action: "create", Resource: "container", Name: "demo-web-1"},
+	}))
+	assert.Equal(t, out.String(), "Would pull image nginx\nWould create container demo-web-1\n")
+
+	out.Reset()
+	assert.NilError(t, PrintOperations(&out, nil))
+	assert.Equal(t, out.String(), "Nothing to do\n")
+}
@@ -0,0 +1,138 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/utils"
+)
+
+// EventsPollInterval is how often PollEvents lists the project containers
+const EventsPollInterval = 2 * time.Second
+
+// PollEvents streams the lifecycle events of the project containers to the
+// options consumer, for backends without an events API: it lists them with
+// ps every interval and reports the changes as create, start, die,
+// health_status and destroy events, until the context is done.
+func PollEvents(ctx context.Context, interval time.Duration, ps func(context.Context) ([]api.ContainerSummary, error), options api.EventsOptions) error {
+	containers, err := ps(ctx)
+	if err != nil {
+		return err
+	}
+	previous := containersByID(containers, options.Services)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			containers, err := ps(ctx)
+			if err != nil {
+				return err
+			}
+			current := containersByID(containers, options.Services)
+			for _, event := range containerEvents(previous, current, now) {
+				if err := options.Consumer(event); err != nil {
+					return err
+				}
+			}
+			previous = current
+		}
+	}
+}
+
+func containersByID(containers []api.ContainerSummary, services []string) map[string]api.ContainerSummary {
+	byID := map[string]api.ContainerSummary{}
+	for _, c := range containers {
+		if len(services) == 0 || utils.StringContains(services, c.Service) {
+			byID[c.ID] = c
+		}
+	}
+	return byID
+}
+
+// containerEvents returns the events turning the previous containers into
+// the current ones, sorted by container name.
+func containerEvents(previous, current map[string]api.ContainerSummary, now time.Time) []api.Event {
+	all := map[string]api.ContainerSummary{}
+	for _, containers := range []map[string]api.ContainerSummary{previous, current} {
+		for id, c := range containers {
+			all[id] = c
+		}
+	}
+	ids := make([]string, 0, len(all))
+	for id := range all {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if all[ids[i]].Name != all[ids[j]].Name {
+			return all[ids[i]].Name < all[ids[j]].Name
+		}
+		return ids[i] < ids[j]
+	})
+
+	var events []api.Event
+	for _, id := range ids {
+		before, existed := previous[id]
+		after, exists := current[id]
+		event := func(c api.ContainerSummary, status string, attributes map[string]string) {
+			if attributes == nil {
+				attributes = map[string]string{}
+			}
+			attributes["name"] = c.Name
+			events = append(events, api.Event{
+				Timestamp:  now,
+				Service:    c.Service,
+				Container:  c.ID,
+				Status:     status,
+				Attributes: attributes,
+			})
+		}
+		switch {
+		case !existed:
+			event(after, "create", nil)
+			if isRunning(after) {
+				event(after, "start", nil)
+			}
+		case !exists:
+			if isRunning(before) {
+				event(before, "die", nil)
+			}
+			event(before, "destroy", nil)
+			continue
+		case !isRunning(before) && isRunning(after):
+			event(after, "start", nil)
+		case isRunning(before) && !isRunning(after):
+			event(after, "die", map[string]string{"exitCode": strconv.Itoa(after.ExitCode)})
+		}
+		if exists && after.Health != "" && after.Health != before.Health {
+			event(after, "health_status: "+after.Health, nil)
+		}
+	}
+	return events
+}
+
+func isRunning(c api.ContainerSummary) bool {
+	return strings.EqualFold(c.State, "running")
+}
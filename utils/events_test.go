@@ -0,0 +1,83 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"gotest.tools/v3/assert"
+)
+
+func TestContainerEvents(t *testing.T) {
+	now := time.Now()
+	previous := map[string]api.ContainerSummary{
+		"1": {ID: "1", Name: "demo-web-1", Service: "web", State: "Running", Health: "starting"},
+		"2": {ID: "2", Name: "demo-db-1", Service: "db", State: "Running"},
+		"3": {ID: "3", Name: "demo-worker-1", Service: "worker", State: "Running"},
+	}
+	current := map[string]api.ContainerSummary{
+		"1": {ID: "1", Name: "demo-web-1", Service: "web", State: "Running", Health: "healthy"},
+		"2": {ID: "2", Name: "demo-db-1", Service: "db", State: "Stopped", ExitCode: 137},
+		"4": {ID: "4", Name: "demo-worker-2", Service: "worker", State: "Running"},
+	}
+	var statuses []string
+	for _, event := range containerEvents(previous, current, now) {
+		statuses = append(statuses, event.Attributes["name"]+" "+event.Status)
+		assert.Equal(t, event.Timestamp, now)
+	}
+	assert.DeepEqual(t, statuses, []string{
+		"demo-db-1 die",
+		"demo-web-1 health_status: healthy",
+		"demo-worker-1 die",
+		"demo-worker-1 destroy",
+		"demo-worker-2 create",
+		"demo-worker-2 start",
+	})
+}
+
+func TestPollEvents(t *testing.T) {
+	listings := [][]api.ContainerSummary{
+		{{ID: "1", Name: "demo-web-1", Service: "web", State: "Created"}},
+		{{ID: "1", Name: "demo-web-1", Service: "web", State: "Running"}, {ID: "2", Name: "demo-db-1", Service: "db", State: "Running"}},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ps := func(context.Context) ([]api.ContainerSummary, error) {
+		listing := listings[0]
+		if len(listings) > 1 {
+			listings = listings[1:]
+		}
+		return listing, nil
+	}
+
+	var events []api.Event
+	err := PollEvents(ctx, time.Millisecond, ps, api.EventsOptions{
+		Services: []string{"web"},
+		Consumer: func(event api.Event) error {
+			events = append(events, event)
+			cancel()
+			return nil
+		},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(events), 1)
+	assert.Equal(t, events[0].Container, "1")
+	assert.Equal(t, events[0].Status, "start")
+}
@@ -0,0 +1,43 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"github.com/compose-spec/compose-go/types"
+)
+
+// NvidiaRuntime is the runtime services set to access the GPUs of the host
+// before device reservations were supported
+const NvidiaRuntime = "nvidia"
+
+// GPUReservation returns the device reservation of the service requesting
+// GPUs. Services using the NvidiaRuntime reserve all the GPUs, a count of -1.
+func GPUReservation(service types.ServiceConfig) (types.DeviceRequest, bool) {
+	if service.Deploy != nil && service.Deploy.Resources.Reservations != nil {
+		for _, device := range service.Deploy.Resources.Reservations.Devices {
+			for _, c := range device.Capabilities {
+				if c == "gpu" {
+					return device, true
+				}
+			}
+		}
+	}
+	if service.Runtime == NvidiaRuntime {
+		return types.DeviceRequest{Capabilities: []string{"gpu"}, Driver: NvidiaRuntime, Count: -1}, true
+	}
+	return types.DeviceRequest{}, false
+}
@@ -0,0 +1,37 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSplitImageReference(t *testing.T) {
+	for _, c := range []struct{ image, repository, tag string }{
+		{"nginx", "nginx", ""},
+		{"nginx:1.21", "nginx", "1.21"},
+		{"localhost:5000/app", "localhost:5000/app", ""},
+		{"localhost:5000/app:v2", "localhost:5000/app", "v2"},
+		{"nginx:1.21@sha256:4424e31f2c366108433ecca7890ad527b243361577180dfd9a5bb36e828abf47", "nginx", "1.21"},
+	} {
+		repository, tag := SplitImageReference(c.image)
+		assert.Equal(t, repository, c.repository, c.image)
+		assert.Equal(t, tag, c.tag, c.image)
+	}
+}
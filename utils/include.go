@@ -0,0 +1,321 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	interp "github.com/compose-spec/compose-go/interpolation"
+	"github.com/compose-spec/compose-go/loader"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/joho/godotenv"
+	"github.com/pkg/errors"
+	"github.com/sanathkr/go-yaml"
+)
+
+// includeKey is the top-level element of compose files importing the
+// resources of other compose files
+const includeKey = "include"
+
+// includedSections are the top-level sections imported from included compose
+// files
+var includedSections = []string{"services", "networks", "volumes", "secrets", "configs"}
+
+// include is an entry of the include element
+type include struct {
+	paths            []string
+	projectDirectory string
+	envFiles         []string
+}
+
+// extensionPass moves the attributes of the compose model the compose library
+// loader rejects to extensions, reporting whether it moved any
+type extensionPass func(dict map[string]interface{}) (bool, error)
+
+// extensionPasses are the extension passes of ResolveComposeFile
+var extensionPasses = []extensionPass{
+	infallible(moveSecretsEnvironment),
+	infallible(moveResourcePids),
+	infallible(moveTmpfsMode),
+	moveBuildAttributes,
+}
+
+func infallible(move func(dict map[string]interface{}) bool) extensionPass {
+	return func(dict map[string]interface{}) (bool, error) {
+		return move(dict), nil
+	}
+}
+
+// ResolveComposeFile returns the content of the compose file resolved for the
+// compose library loader, or nil if there's nothing to resolve:
+//
+//   - the include element is replaced by the resources of the compose files it
+//     lists
+//   - the ${VAR:+alt} and ${VAR+alt} alternative values are substituted
+//   - the attributes the loader rejects are moved to extensions, see
+//     extensionPasses
+//
+// With strict, substituting variables which are not set is an error.
+//
+// Each included compose file is loaded as a project of its own: relative
+// paths are resolved against its project directory, the directory of its
+// first file unless set by project_directory, and variables are interpolated
+// with the given environment, falling back to the variables of its env files,
+// <project directory>/.env by default. Including a resource already defined
+// by the compose file or by another included compose file is an error.
+func ResolveComposeFile(configFile string, environment map[string]string, strict bool) ([]byte, error) {
+	dict, ok, err := resolveComposeFile(configFile, environment, strict, nil)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return yaml.Marshal(dict)
+}
+
+func resolveComposeFile(configFile string, environment map[string]string, strict bool, stack []string) (map[string]interface{}, bool, error) {
+	configFile, err := filepath.Abs(configFile)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, f := range stack {
+		if f == configFile {
+			return nil, false, errors.Errorf("include cycle: %s", strings.Join(append(stack, configFile), " -> "))
+		}
+	}
+	stack = append(stack, configFile)
+
+	b, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, false, err
+	}
+	dict, err := loader.ParseYAML(b)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to parse %s", configFile)
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := environment[name]
+		return value, ok
+	}
+	if strict {
+		if unset := UnsetVariables(dict, lookup); len(unset) > 0 {
+			return nil, false, errors.Errorf("%s: variables not set: %s", configFile, strings.Join(unset, ", "))
+		}
+	}
+	resolved := InterpolateAlternatives(dict, lookup)
+	for _, pass := range extensionPasses {
+		moved, err := pass(dict)
+		if err != nil {
+			return nil, false, errors.Wrap(err, configFile)
+		}
+		resolved = moved || resolved
+	}
+	if _, ok := dict[includeKey]; !ok {
+		return dict, resolved, nil
+	}
+	interpolated, err := interp.Interpolate(map[string]interface{}{includeKey: dict[includeKey]}, interp.Options{LookupValue: lookup})
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "%s: invalid include", configFile)
+	}
+	value := interpolated[includeKey]
+	delete(dict, includeKey)
+
+	includes, err := parseIncludes(value)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "%s: invalid include", configFile)
+	}
+	importedFrom := map[string]string{}
+	for _, inc := range includes {
+		imported, err := loadInclude(filepath.Dir(configFile), inc, environment, strict, stack)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, section := range includedSections {
+			resources, _ := imported[section].(map[string]interface{})
+			if len(resources) == 0 {
+				continue
+			}
+			target, _ := dict[section].(map[string]interface{})
+			if target == nil {
+				target = map[string]interface{}{}
+				dict[section] = target
+			}
+			for name, resource := range resources {
+				key := section + "." + name
+				if from, ok := importedFrom[key]; ok {
+					return nil, false, errors.Errorf("%s: %s %q is defined by both %s and %s", configFile, section, name, from, inc.paths[0])
+				}
+				if _, ok := target[name]; ok {
+					return nil, false, errors.Errorf("%s: %s %q imported from %s conflicts with an existing definition", configFile, section, name, inc.paths[0])
+				}
+				importedFrom[key] = inc.paths[0]
+				target[name] = resource
+			}
+		}
+	}
+	return dict, true, nil
+}
+
+// loadInclude returns the sections of the compose model defined by the files
+// of the include entry, with their resources fully resolved.
+func loadInclude(dir string, inc include, environment map[string]string, strict bool, stack []string) (map[string]interface{}, error) {
+	for i, p := range inc.paths {
+		inc.paths[i] = absPath(dir, p)
+	}
+	projectDir := filepath.Dir(inc.paths[0])
+	if inc.projectDirectory != "" {
+		projectDir = absPath(dir, inc.projectDirectory)
+	}
+
+	envFiles := inc.envFiles
+	if len(envFiles) == 0 {
+		if _, err := os.Stat(filepath.Join(projectDir, ".env")); err == nil {
+			envFiles = []string{filepath.Join(projectDir, ".env")}
+		}
+	}
+	env := map[string]string{}
+	for _, f := range envFiles {
+		vars, err := godotenv.Read(absPath(dir, f))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read env file of included %s", inc.paths[0])
+		}
+		for k, v := range vars {
+			env[k] = v
+		}
+	}
+	for k, v := range environment {
+		env[k] = v
+	}
+
+	var configFiles []types.ConfigFile
+	for _, p := range inc.paths {
+		dict, _, err := resolveComposeFile(p, env, strict, stack)
+		if err != nil {
+			return nil, err
+		}
+		// the content is required for the loader to interpolate variables
+		content, err := yaml.Marshal(dict)
+		if err != nil {
+			return nil, err
+		}
+		configFiles = append(configFiles, types.ConfigFile{Filename: p, Content: content})
+	}
+	project, err := loader.Load(types.ConfigDetails{
+		WorkingDir:  projectDir,
+		ConfigFiles: configFiles,
+		Environment: env,
+	}, func(options *loader.Options) {
+		options.SkipNormalization = true
+		options.ResolvePaths = true
+	}, loader.WithDiscardEnvFiles)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load included %s", inc.paths[0])
+	}
+	for i, s := range project.Services {
+		if s.Build == nil {
+			continue
+		}
+		// build contexts are only resolved by normalization, which is skipped
+		// not to inject the default network of the included project
+		if context := absPath(projectDir, s.Build.Context); fileExists(context) {
+			project.Services[i].Build.Context = context
+		}
+	}
+
+	b, err := MarshalProject(project, "yaml")
+	if err != nil {
+		return nil, err
+	}
+	return loader.ParseYAML(b)
+}
+
+// parseIncludes parses the entries of the include element, either paths or
+// mappings with path, project_directory and env_file attributes.
+func parseIncludes(value interface{}) ([]include, error) {
+	entries, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.New("include must be a list")
+	}
+	var includes []include
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case string:
+			includes = append(includes, include{paths: []string{e}})
+		case map[string]interface{}:
+			var inc include
+			for k, v := range e {
+				var err error
+				switch k {
+				case "path":
+					inc.paths, err = stringOrList(k, v)
+				case "env_file":
+					inc.envFiles, err = stringOrList(k, v)
+				case "project_directory":
+					s, ok := v.(string)
+					if !ok {
+						err = fmt.Errorf("%s must be a string", k)
+					}
+					inc.projectDirectory = s
+				default:
+					err = fmt.Errorf("unsupported attribute %s", k)
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+			if len(inc.paths) == 0 {
+				return nil, errors.New("path is required")
+			}
+			includes = append(includes, inc)
+		default:
+			return nil, errors.New("include entries must be paths or mappings")
+		}
+	}
+	return includes, nil
+}
+
+func stringOrList(name string, value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		var list []string
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s must be a string or a list of strings", name)
+			}
+			list = append(list, s)
+		}
+		return list, nil
+	default:
+		return nil, fmt.Errorf("%s must be a string or a list of strings", name)
+	}
+}
+
+func absPath(dir string, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
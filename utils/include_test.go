@@ -0,0 +1,119 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/loader"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	assert.NilError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	assert.NilError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestResolveComposeFileIncludes(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "compose.yaml"), `
+include:
+  - db/compose.yaml
+  - path: api/compose.yaml
+    env_file: api.env
+services:
+  web:
+    image: nginx
+    depends_on: [api, db]
+`)
+	writeFile(t, filepath.Join(dir, "db", "compose.yaml"), `
+services:
+  db:
+    image: mysql:${MYSQL_VERSION}
+    command: echo $$HOME
+    volumes:
+      - ./data:/var/lib/mysql
+`)
+	writeFile(t, filepath.Join(dir, "db", ".env"), "MYSQL_VERSION=8\n")
+	writeFile(t, filepath.Join(dir, "api", "compose.yaml"), `
+services:
+  api:
+    image: ${API_IMAGE}
+    build: .
+`)
+	writeFile(t, filepath.Join(dir, "api.env"), "API_IMAGE=demo/api\n")
+
+	b, err := ResolveComposeFile(filepath.Join(dir, "compose.yaml"), map[string]string{}, false)
+	assert.NilError(t, err)
+	project, err := loader.Load(types.ConfigDetails{
+		WorkingDir:  dir,
+		ConfigFiles: []types.ConfigFile{{Filename: "compose.yaml", Content: b}},
+		Environment: map[string]string{},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, project.ServiceNames(), []string{"api", "db", "web"})
+
+	db, err := project.GetService("db")
+	assert.NilError(t, err)
+	assert.Equal(t, db.Image, "mysql:8")
+	assert.DeepEqual(t, []string(db.Command), []string{"echo", "$HOME"})
+	assert.Equal(t, db.Volumes[0].Source, filepath.Join(dir, "db", "data"))
+
+	api, err := project.GetService("api")
+	assert.NilError(t, err)
+	assert.Equal(t, api.Image, "demo/api")
+	assert.Equal(t, api.Build.Context, filepath.Join(dir, "api"))
+}
+
+func TestResolveComposeFileUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "compose.yaml"), "services:\n  web:\n    image: nginx\n")
+	b, err := ResolveComposeFile(filepath.Join(dir, "compose.yaml"), nil, false)
+	assert.NilError(t, err)
+	assert.Assert(t, b == nil)
+}
+
+func TestResolveComposeFileIncludeConflicts(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "compose.yaml"), `
+include:
+  - db.yaml
+services:
+  db:
+    image: postgres
+`)
+	writeFile(t, filepath.Join(dir, "db.yaml"), "services:\n  db:\n    image: mysql\n")
+	_, err := ResolveComposeFile(filepath.Join(dir, "compose.yaml"), nil, false)
+	assert.ErrorContains(t, err, `services "db" imported from `+filepath.Join(dir, "db.yaml")+` conflicts with an existing definition`)
+
+	writeFile(t, filepath.Join(dir, "compose.yaml"), "include:\n  - db.yaml\n  - other.yaml\n")
+	writeFile(t, filepath.Join(dir, "other.yaml"), "services:\n  db:\n    image: mariadb\n")
+	_, err = ResolveComposeFile(filepath.Join(dir, "compose.yaml"), nil, false)
+	assert.ErrorContains(t, err, `services "db" is defined by both `+filepath.Join(dir, "db.yaml")+` and `+filepath.Join(dir, "other.yaml"))
+}
+
+func TestResolveComposeFileIncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.yaml"), "include:\n  - b.yaml\n")
+	writeFile(t, filepath.Join(dir, "b.yaml"), "include:\n  - a.yaml\n")
+	_, err := ResolveComposeFile(filepath.Join(dir, "a.yaml"), nil, false)
+	a, b := filepath.Join(dir, "a.yaml"), filepath.Join(dir, "b.yaml")
+	assert.ErrorContains(t, err, "include cycle: "+a+" -> "+b+" -> "+a)
+}
@@ -0,0 +1,121 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/compose-spec/compose-go/template"
+)
+
+var (
+	// alternativePattern matches the ${VAR:+alt} and ${VAR+alt} substitutions,
+	// which the compose loader has no support for, and escaped dollar signs.
+	// The alternative value may hold braced variables itself.
+	alternativePattern = regexp.MustCompile(`\$(?:\$|\{([_a-zA-Z][_a-zA-Z0-9]*)(:?)\+((?:[^{}]|\{[^{}]*\})*)\})`)
+	// variablePattern matches variable substitutions, with the modifier of
+	// braced ones, and escaped dollar signs
+	variablePattern = regexp.MustCompile(`\$(?:\$|([_a-zA-Z][_a-zA-Z0-9]*)|\{([_a-zA-Z][_a-zA-Z0-9]*)([^}]*)\})`)
+)
+
+// InterpolateAlternatives substitutes the alternative values of the compose
+// model values: ${VAR:+alt} is replaced by alt when VAR is set and not empty,
+// ${VAR+alt} when VAR is set, and by an empty string otherwise. The other
+// substitutions are left to the compose loader. It reports whether any value
+// was substituted.
+func InterpolateAlternatives(dict map[string]interface{}, lookup template.Mapping) bool {
+	var substituted bool
+	for k, v := range dict {
+		var ok bool
+		dict[k], ok = interpolateAlternatives(v, lookup)
+		substituted = substituted || ok
+	}
+	return substituted
+}
+
+func interpolateAlternatives(value interface{}, lookup template.Mapping) (interface{}, bool) {
+	switch v := value.(type) {
+	case string:
+		var substituted bool
+		s := alternativePattern.ReplaceAllStringFunc(v, func(match string) string {
+			groups := alternativePattern.FindStringSubmatch(match)
+			if groups[1] == "" {
+				return match
+			}
+			substituted = true
+			value, ok := lookup(groups[1])
+			if !ok || groups[2] == ":" && value == "" {
+				return ""
+			}
+			return groups[3]
+		})
+		return s, substituted
+	case map[string]interface{}:
+		return v, InterpolateAlternatives(v, lookup)
+	case []interface{}:
+		var substituted bool
+		for i, item := range v {
+			var ok bool
+			v[i], ok = interpolateAlternatives(item, lookup)
+			substituted = substituted || ok
+		}
+		return v, substituted
+	default:
+		return value, false
+	}
+}
+
+// UnsetVariables returns the names of the variables substituted in the compose
+// model values which are not set. Variables with a default or an alternative
+// value, or for which an error message is set, are not reported.
+func UnsetVariables(dict map[string]interface{}, lookup template.Mapping) []string {
+	unset := map[string]bool{}
+	collectUnsetVariables(dict, lookup, unset)
+	var names []string
+	for name := range unset {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func collectUnsetVariables(value interface{}, lookup template.Mapping, unset map[string]bool) {
+	switch v := value.(type) {
+	case string:
+		for _, groups := range variablePattern.FindAllStringSubmatch(v, -1) {
+			name, modifier := groups[1], groups[3]
+			if name == "" {
+				name = groups[2]
+			}
+			if name == "" || modifier != "" {
+				continue
+			}
+			if _, ok := lookup(name); !ok {
+				unset[name] = true
+			}
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			collectUnsetVariables(item, lookup, unset)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectUnsetVariables(item, lookup, unset)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func lookupIn(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+func TestInterpolateAlternatives(t *testing.T) {
+	lookup := lookupIn(map[string]string{"DEBUG": "1", "EMPTY": ""})
+	dict := map[string]interface{}{
+		"services": map[string]interface{}{
+			"app": map[string]interface{}{
+				"command": []interface{}{
+					"run",
+					"${DEBUG:+--debug}",
+					"${EMPTY:+--empty}",
+					"${EMPTY+--set}",
+					"${UNSET+--unset}",
+					"$${DEBUG:+escaped}",
+					"${DEBUG:+--level=${LEVEL:-info}}",
+				},
+				"image": "app:${TAG:-latest}",
+			},
+		},
+	}
+	assert.Assert(t, InterpolateAlternatives(dict, lookup))
+	app := dict["services"].(map[string]interface{})["app"].(map[string]interface{})
+	assert.DeepEqual(t, app["command"], []interface{}{
+		"run", "--debug", "", "--set", "", "$${DEBUG:+escaped}", "--level=${LEVEL:-info}",
+	})
+	assert.Equal(t, app["image"], "app:${TAG:-latest}")
+
+	assert.Assert(t, !InterpolateAlternatives(map[string]interface{}{"image": "app:$TAG"}, lookup))
+}
+
+func TestUnsetVariables(t *testing.T) {
+	lookup := lookupIn(map[string]string{"TAG": "1.0", "EMPTY": ""})
+	dict := map[string]interface{}{
+		"services": map[string]interface{}{
+			"app": map[string]interface{}{
+				"image":       "app:$TAG",
+				"command":     []interface{}{"$COMMAND", "${ARG}", "$${ESCAPED}"},
+				"environment": map[string]interface{}{"EMPTY": "${EMPTY}", "MODE": "${MODE:-prod}", "DEBUG": "${DEBUG:+1}"},
+				"user":        "${USER_ID:?user id is required}",
+			},
+		},
+	}
+	assert.DeepEqual(t, UnsetVariables(dict, lookup), []string{"ARG", "COMMAND"})
+}
+
+func TestResolveComposeFileStrict(t *testing.T) {
+	dir := t.TempDir()
+	compose := filepath.Join(dir, "compose.yaml")
+	writeFile(t, compose, "services:\n  app:\n    image: app:${TAG}\n    command: run ${DEBUG:+--debug}\n")
+
+	_, err := ResolveComposeFile(compose, map[string]string{}, true)
+	assert.Error(t, err, compose+": variables not set: TAG")
+
+	b, err := ResolveComposeFile(compose, map[string]string{"TAG": "1.0", "DEBUG": "1"}, true)
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), "services:\n  app:\n    command: run --debug\n    image: app:${TAG}\n")
+}
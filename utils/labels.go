@@ -0,0 +1,126 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sanathkr/go-yaml"
+)
+
+// labeledSections are the top-level sections whose resources have their
+// labels merged across compose files
+var labeledSections = []string{"networks", "volumes"}
+
+// ResolveComposeFiles resolves the compose files like ResolveComposeFile and
+// returns their content in order, nil for the files which don't have to be
+// replaced and for the standard input, "-". The compose loader replacing the
+// networks and volumes of a compose file by their definitions in the next
+// ones, their labels are merged instead, later files overriding the values of
+// earlier ones, like for the labels of services.
+func ResolveComposeFiles(configFiles []string, environment map[string]string, strict bool) ([][]byte, error) {
+	contents := make([][]byte, len(configFiles))
+	labels := map[string]map[string]interface{}{}
+	for i, configFile := range configFiles {
+		if configFile == "-" {
+			continue
+		}
+		dict, resolved, err := resolveComposeFile(configFile, environment, strict, nil)
+		if err != nil {
+			return nil, err
+		}
+		if merged := mergeResourceLabels(dict, labels); !resolved && !merged {
+			continue
+		}
+		if contents[i], err = yaml.Marshal(dict); err != nil {
+			return nil, err
+		}
+	}
+	return contents, nil
+}
+
+// mergeResourceLabels sets the labels of the networks and volumes of the
+// compose file to the labels defined for them by the previous files, keyed by
+// section and name, overridden by their own labels, and records them for the
+// next files. It reports whether any resource of the compose file has been
+// changed.
+func mergeResourceLabels(dict map[string]interface{}, labels map[string]map[string]interface{}) bool {
+	changed := false
+	for _, section := range labeledSections {
+		resources, _ := dict[section].(map[string]interface{})
+		for name, r := range resources {
+			resource, ok := r.(map[string]interface{})
+			if r != nil && !ok {
+				// let the loader report invalid definitions
+				continue
+			}
+			if _, external := resource["external"]; external {
+				delete(labels, section+"."+name)
+				continue
+			}
+			own, ok := labelsMap(resource["labels"])
+			if !ok {
+				continue
+			}
+			key := section + "." + name
+			if previous := labels[key]; len(previous) > 0 {
+				merged := map[string]interface{}{}
+				for k, v := range previous {
+					merged[k] = v
+				}
+				for k, v := range own {
+					merged[k] = v
+				}
+				if resource == nil {
+					resource = map[string]interface{}{}
+					resources[name] = resource
+				}
+				resource["labels"] = merged
+				own = merged
+				changed = true
+			}
+			labels[key] = own
+		}
+	}
+	return changed
+}
+
+// labelsMap returns the labels of a resource set as a mapping or as a list of
+// key=value strings, false if they are invalid.
+func labelsMap(value interface{}) (map[string]interface{}, bool) {
+	labels := map[string]interface{}{}
+	switch value := value.(type) {
+	case nil:
+	case map[string]interface{}:
+		for k, v := range value {
+			labels[k] = v
+		}
+	case []interface{}:
+		for _, item := range value {
+			kv := strings.SplitN(fmt.Sprint(item), "=", 2)
+			if len(kv) == 1 {
+				labels[kv[0]] = ""
+			} else {
+				labels[kv[0]] = kv[1]
+			}
+		}
+	default:
+		return nil, false
+	}
+	return labels, true
+}
@@ -0,0 +1,84 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/loader"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestMergeResourceLabels(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "compose.yaml")
+	writeFile(t, base, `
+services:
+  app:
+    image: app
+    labels: [tier=front, team=web]
+networks:
+  front:
+    labels: [tier=front, team=web]
+volumes:
+  data:
+    labels:
+      tier: back
+  logs:
+    external: true
+`)
+	override := filepath.Join(dir, "compose.override.yaml")
+	writeFile(t, override, `
+services:
+  app:
+    labels:
+      team: ops
+networks:
+  front:
+    labels:
+      team: ops
+volumes:
+  data:
+`)
+	files := []string{base, override}
+	contents, err := ResolveComposeFiles(append(files, "-"), nil, false)
+	assert.NilError(t, err)
+	assert.Equal(t, len(contents), 3)
+	assert.Assert(t, contents[0] == nil)
+	assert.Assert(t, contents[1] != nil)
+	assert.Assert(t, contents[2] == nil)
+
+	content, err := os.ReadFile(base)
+	assert.NilError(t, err)
+	project, err := loader.Load(types.ConfigDetails{
+		WorkingDir: dir,
+		ConfigFiles: []types.ConfigFile{
+			{Filename: base, Content: content},
+			{Filename: override, Content: contents[1]},
+		},
+	})
+	assert.NilError(t, err)
+	app, err := project.GetService("app")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, app.Labels, types.Labels{"tier": "front", "team": "ops"})
+	assert.DeepEqual(t, project.Networks["front"].Labels, types.Labels{"tier": "front", "team": "ops"})
+	assert.DeepEqual(t, project.Volumes["data"].Labels, types.Labels{"tier": "back"})
+	assert.Assert(t, project.Volumes["logs"].External.External)
+}
@@ -17,9 +17,51 @@
 package utils
 
 import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/docker/compose/v2/pkg/api"
+	timetypes "github.com/docker/docker/api/types/time"
 )
 
+// ParseLogTime parses a `logs --since` or `--until` value, either a timestamp
+// (e.g. RFC3339 or Unix) or a duration relative to now (e.g. 42m). An empty
+// value returns the zero time, i.e. no bound.
+func ParseLogTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	ts, err := timetypes.GetTimestamp(value, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid log time %q: %w", value, err)
+	}
+	sec, nsec, err := timetypes.ParseTimestamps(ts, 0)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid log time %q: %w", value, err)
+	}
+	return time.Unix(sec, nsec), nil
+}
+
+// ParseLogTimeBounds parses the `logs --since` and `--until` values.
+func ParseLogTimeBounds(options api.LogOptions) (since time.Time, until time.Time, err error) {
+	now := time.Now()
+	if since, err = ParseLogTime(options.Since, now); err != nil {
+		return
+	}
+	if until, err = ParseLogTime(options.Until, now); err != nil {
+		return
+	}
+	if !since.IsZero() && !until.IsZero() && until.Before(since) {
+		err = fmt.Errorf("logs --until %q is before --since %q", options.Until, options.Since)
+	}
+	return
+}
+
 // FilteredLogConsumer filters logs for given services
 func FilteredLogConsumer(consumer api.LogConsumer, services []string) api.LogConsumer {
 	if len(services) == 0 {
@@ -57,3 +99,92 @@ func (a *allowListLogConsumer) Register(name string) {
 		a.delegate.Register(name)
 	}
 }
+
+// logColors are the ANSI codes of the colors of the log prefixes
+var logColors = []string{"36", "33", "32", "35", "34", "36;1", "33;1", "32;1", "35;1", "34;1"}
+
+// replicaSuffix is the replica number ending container names
+var replicaSuffix = regexp.MustCompile(`[-_][0-9]+$`)
+
+// NewLogConsumer returns a log consumer writing the logs of the containers to
+// w, prefixed with the container names unless prefix is false. With color,
+// the prefix of each service has a color of its own, derived from the name of
+// the container without its replica number so that services keep their color
+// from a run to the other.
+func NewLogConsumer(w io.Writer, color, prefix bool) api.LogConsumer {
+	return &logConsumer{
+		writer:     w,
+		color:      color,
+		prefix:     prefix,
+		presenters: map[string]*logPresenter{},
+	}
+}
+
+type logConsumer struct {
+	mu         sync.Mutex
+	writer     io.Writer
+	color      bool
+	prefix     bool
+	presenters map[string]*logPresenter
+	width      int
+}
+
+type logPresenter struct {
+	name   string
+	color  string
+	prefix string
+}
+
+func (l *logConsumer) Register(container string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.register(container)
+}
+
+func (l *logConsumer) Log(container, service, message string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	p := l.presenter(container)
+	for _, line := range strings.Split(message, "\n") {
+		if l.prefix {
+			fmt.Fprintf(l.writer, "%s %s\n", p.prefix, line) // nolint:errcheck
+		} else {
+			fmt.Fprintln(l.writer, line) // nolint:errcheck
+		}
+	}
+}
+
+func (l *logConsumer) Status(container, message string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	p := l.presenter(container)
+	fmt.Fprintln(l.writer, l.colored(p.color, fmt.Sprintf("%s %s", container, message))) // nolint:errcheck
+}
+
+func (l *logConsumer) presenter(container string) *logPresenter {
+	if p, ok := l.presenters[container]; ok {
+		return p
+	}
+	return l.register(container)
+}
+
+func (l *logConsumer) register(container string) *logPresenter {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(replicaSuffix.ReplaceAllString(container, "")))
+	p := &logPresenter{name: container, color: logColors[h.Sum32()%uint32(len(logColors))]}
+	l.presenters[container] = p
+	if len(container)+1 > l.width {
+		l.width = len(container) + 1
+	}
+	for _, p := range l.presenters {
+		p.prefix = l.colored(p.color, fmt.Sprintf("%-*s |", l.width, p.name))
+	}
+	return p
+}
+
+func (l *logConsumer) colored(color, s string) string {
+	if !l.color {
+		return s
+	}
+	return "\033[" + color + "m" + s + "\033[0m"
+}
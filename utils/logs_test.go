@@ -0,0 +1,82 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"gotest.tools/v3/assert"
+)
+
+func TestParseLogTime(t *testing.T) {
+	now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	ts, err := ParseLogTime("", now)
+	assert.NilError(t, err)
+	assert.Assert(t, ts.IsZero())
+
+	ts, err = ParseLogTime("42m", now)
+	assert.NilError(t, err)
+	assert.Assert(t, ts.Equal(now.Add(-42*time.Minute)))
+
+	ts, err = ParseLogTime("2022-02-28T10:30:00Z", now)
+	assert.NilError(t, err)
+	assert.Assert(t, ts.Equal(time.Date(2022, 2, 28, 10, 30, 0, 0, time.UTC)))
+
+	ts, err = ParseLogTime("1646136000", now)
+	assert.NilError(t, err)
+	assert.Assert(t, ts.Equal(now))
+
+	_, err = ParseLogTime("yesterday", now)
+	assert.ErrorContains(t, err, `invalid log time "yesterday"`)
+}
+
+func TestParseLogTimeBounds(t *testing.T) {
+	since, until, err := ParseLogTimeBounds(api.LogOptions{Since: "2h", Until: "1h"})
+	assert.NilError(t, err)
+	assert.Equal(t, until.Sub(since), time.Hour)
+
+	_, _, err = ParseLogTimeBounds(api.LogOptions{Since: "1h", Until: "2h"})
+	assert.ErrorContains(t, err, "is before --since")
+}
+
+func TestLogConsumer(t *testing.T) {
+	var b bytes.Buffer
+	consumer := NewLogConsumer(&b, false, true)
+	consumer.Register("demo-web-1")
+	consumer.Register("demo-db-1")
+	consumer.Log("demo-web-1", "web", "hello\nworld")
+	consumer.Status("demo-db-1", "exited with code 0")
+	assert.Equal(t, b.String(), "demo-web-1  | hello\ndemo-web-1  | world\ndemo-db-1 exited with code 0\n")
+
+	b.Reset()
+	consumer = NewLogConsumer(&b, false, false)
+	consumer.Log("demo-web-1", "web", "hello")
+	assert.Equal(t, b.String(), "hello\n")
+
+	colors := func(container string) string {
+		var b bytes.Buffer
+		consumer := NewLogConsumer(&b, true, true)
+		consumer.Log(container, "", "")
+		return b.String()[:bytes.IndexByte(b.Bytes(), 'm')+1]
+	}
+	assert.Equal(t, colors("demo-web-1"), colors("demo-web-2"))
+	assert.Equal(t, colors("demo_web_1"), colors("demo_web_3"))
+}
@@ -0,0 +1,91 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+// PublishedPort returns the host address and port publishing the target port
+// of the service container at the options index, from the backend's ps
+// containers, e.g. a load balancer DNS name or a container group FQDN.
+func PublishedPort(containers []api.ContainerSummary, service string, port int, options api.PortOptions) (string, int, error) {
+	var candidates []api.ContainerSummary
+	for _, c := range containers {
+		if c.Service == service {
+			candidates = append(candidates, c)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Name < candidates[j].Name
+	})
+	index := options.Index
+	if index == 0 {
+		index = 1
+	}
+	if index < 1 || index > len(candidates) {
+		return "", 0, fmt.Errorf("no container found for %s_%d", service, index)
+	}
+	protocol := options.Protocol
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	for _, p := range candidates[index-1].Publishers {
+		if p.TargetPort != port || !sameProtocol(p.Protocol, protocol) {
+			continue
+		}
+		host, published := splitPublisherURL(p.URL)
+		if p.PublishedPort != 0 {
+			published = p.PublishedPort
+		}
+		return host, published, nil
+	}
+	return "", 0, fmt.Errorf("no port %d/%s published for service %q", port, protocol, service)
+}
+
+// sameProtocol tells whether a publisher protocol matches the transport
+// protocol, HTTP(S) load balancers publishing TCP ports.
+func sameProtocol(published, protocol string) bool {
+	published = strings.ToLower(published)
+	switch published {
+	case "", "http", "https":
+		published = "tcp"
+	}
+	return published == strings.ToLower(protocol)
+}
+
+// splitPublisherURL returns the host and port of a publisher URL, e.g.
+// `host:80` or `host:80->80/tcp`.
+func splitPublisherURL(url string) (string, int) {
+	if i := strings.Index(url, "->"); i >= 0 {
+		url = url[:i]
+	}
+	i := strings.LastIndex(url, ":")
+	if i < 0 {
+		return url, 0
+	}
+	port, err := strconv.Atoi(url[i+1:])
+	if err != nil {
+		return url, 0
+	}
+	return url[:i], port
+}
@@ -0,0 +1,57 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"gotest.tools/v3/assert"
+)
+
+func TestPublishedPort(t *testing.T) {
+	containers := []api.ContainerSummary{
+		{Name: "web-2", Service: "web", Publishers: api.PortPublishers{
+			{URL: "lb.eu-west-3.elb.amazonaws.com:80", TargetPort: 80, PublishedPort: 80, Protocol: "http"},
+		}},
+		{Name: "web-1", Service: "web", Publishers: api.PortPublishers{
+			{URL: "demo.westeurope.azurecontainer.io:8080->8080/tcp", TargetPort: 8080, PublishedPort: 8080, Protocol: "TCP"},
+			{URL: "10.0.0.1:5353", TargetPort: 53, Protocol: "UDP"},
+		}},
+	}
+
+	host, port, err := PublishedPort(containers, "web", 8080, api.PortOptions{Protocol: "tcp", Index: 1})
+	assert.NilError(t, err)
+	assert.Equal(t, host, "demo.westeurope.azurecontainer.io")
+	assert.Equal(t, port, 8080)
+
+	host, port, err = PublishedPort(containers, "web", 53, api.PortOptions{Protocol: "udp"})
+	assert.NilError(t, err)
+	assert.Equal(t, host, "10.0.0.1")
+	assert.Equal(t, port, 5353)
+
+	host, port, err = PublishedPort(containers, "web", 80, api.PortOptions{Index: 2})
+	assert.NilError(t, err)
+	assert.Equal(t, host, "lb.eu-west-3.elb.amazonaws.com")
+	assert.Equal(t, port, 80)
+
+	_, _, err = PublishedPort(containers, "web", 80, api.PortOptions{Index: 3})
+	assert.Error(t, err, "no container found for web_3")
+
+	_, _, err = PublishedPort(containers, "web", 443, api.PortOptions{})
+	assert.Error(t, err, `no port 443/tcp published for service "web"`)
+}
@@ -0,0 +1,47 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+)
+
+// CheckProfiles checks that the enabled services of the project don't depend
+// on services disabled by the active profiles
+func CheckProfiles(project *types.Project) error {
+	disabled := map[string]types.ServiceConfig{}
+	for _, s := range project.DisabledServices {
+		// services without profiles are disabled by up --no-deps
+		if len(s.Profiles) > 0 {
+			disabled[s.Name] = s
+		}
+	}
+	for _, s := range project.Services {
+		for dependency := range s.DependsOn {
+			d, ok := disabled[dependency]
+			if !ok {
+				continue
+			}
+			return fmt.Errorf("service %q depends on service %q, which is only enabled by profiles %s: use --profile or COMPOSE_PROFILES to enable it",
+				s.Name, d.Name, strings.Join(d.Profiles, ", "))
+		}
+	}
+	return nil
+}
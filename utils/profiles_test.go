@@ -0,0 +1,45 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckProfiles(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			{Name: "web", DependsOn: types.DependsOnConfig{"db": {Condition: types.ServiceConditionStarted}}},
+			{Name: "db"},
+			{Name: "migrate", Profiles: []string{"tools"}, DependsOn: types.DependsOnConfig{"db": {}}},
+		},
+	}
+	project.ApplyProfiles(nil)
+	assert.NilError(t, CheckProfiles(project))
+
+	project.Services[1].Profiles = []string{"debug", "db"}
+	project.Services = append(project.Services, project.DisabledServices...)
+	project.ApplyProfiles(nil)
+	assert.Error(t, CheckProfiles(project), `service "web" depends on service "db", which is only enabled by profiles debug, db: use --profile or COMPOSE_PROFILES to enable it`)
+
+	project.Services = append(project.Services, project.DisabledServices...)
+	project.ApplyProfiles([]string{"db"})
+	assert.NilError(t, CheckProfiles(project))
+}
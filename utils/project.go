@@ -0,0 +1,48 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/sanathkr/go-yaml"
+)
+
+// MarshalProject encodes the project model as yaml or json, escaping dollar
+// signs so that the output can be loaded again without interpolation
+func MarshalProject(project *types.Project, format string) ([]byte, error) {
+	var (
+		b   []byte
+		err error
+	)
+	switch format {
+	case "json":
+		b, err = json.MarshalIndent(project, "", "  ")
+		b = append(b, '\n')
+	case "yaml":
+		b, err = yaml.Marshal(project)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bytes.ReplaceAll(b, []byte{'$'}, []byte{'$', '$'}), nil
+}
@@ -0,0 +1,61 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"strings"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/pkg/errors"
+)
+
+type psFiltersKey struct{}
+
+// WithPsFilters returns a context telling backends to only list the
+// containers matching the filters
+func WithPsFilters(ctx context.Context, f filters.Args) context.Context {
+	return context.WithValue(ctx, psFiltersKey{}, f)
+}
+
+// PsFilters returns the filters the listed containers must match
+func PsFilters(ctx context.Context) filters.Args {
+	f, ok := ctx.Value(psFiltersKey{}).(filters.Args)
+	if !ok {
+		return filters.NewArgs()
+	}
+	return f
+}
+
+// ParsePsFilters parses the `compose ps --filter` values, in the form
+// label=KEY[=VALUE], name=NAME or status=STATUS
+func ParsePsFilters(values []string) (filters.Args, error) {
+	f := filters.NewArgs()
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 {
+			return f, errors.Errorf("invalid filter %q, arguments to --filter should be in form KEY=VAL", value)
+		}
+		switch parts[0] {
+		case "label", "name", "status":
+			f.Add(parts[0], parts[1])
+		default:
+			return f, errors.Errorf("invalid filter %q, supported filters are label, name and status", value)
+		}
+	}
+	return f, nil
+}
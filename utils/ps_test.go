@@ -0,0 +1,41 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParsePsFilters(t *testing.T) {
+	f, err := ParsePsFilters([]string{"label=tier=front", "name=web", "status=running", "status=exited"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, f.Get("label"), []string{"tier=front"})
+	assert.DeepEqual(t, f.Get("name"), []string{"web"})
+	assert.Assert(t, f.ExactMatch("status", "exited"))
+
+	_, err = ParsePsFilters([]string{"running"})
+	assert.ErrorContains(t, err, "should be in form KEY=VAL")
+	_, err = ParsePsFilters([]string{"source=image"})
+	assert.ErrorContains(t, err, "supported filters are label, name and status")
+
+	ctx := context.Background()
+	assert.Equal(t, PsFilters(ctx).Len(), 0)
+	assert.Equal(t, PsFilters(WithPsFilters(ctx, f)).Len(), 3)
+}
@@ -0,0 +1,66 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PullFailure is a service whose image failed to be pulled
+type PullFailure struct {
+	Service string
+	// Build is set when the service image can be built instead
+	Build bool
+	Err   error
+}
+
+// ReportPullFailures writes the warnings of the services which must be built
+// and of the ignored pull failures, and returns an error summarizing the
+// other failures.
+func ReportPullFailures(w io.Writer, failures []PullFailure, ignore bool) error {
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].Service < failures[j].Service
+	})
+	var (
+		mustBuild []string
+		failed    []string
+	)
+	for _, f := range failures {
+		if f.Build {
+			mustBuild = append(mustBuild, f.Service)
+			continue
+		}
+		failed = append(failed, fmt.Sprintf("service %q: %v", f.Service, f.Err))
+	}
+	if len(mustBuild) > 0 {
+		_, _ = fmt.Fprintf(w, "WARNING: Some service image(s) must be built from source by running:\n    docker compose build %s\n", strings.Join(mustBuild, " "))
+	}
+	switch {
+	case len(failed) == 0:
+		return nil
+	case ignore:
+		_, _ = fmt.Fprintf(w, "WARNING: Some service image(s) failed to be pulled:\n    %s\n", strings.Join(failed, "\n    "))
+		return nil
+	default:
+		return errors.Errorf("failed to pull %d service image(s):\n    %s", len(failed), strings.Join(failed, "\n    "))
+	}
+}
@@ -0,0 +1,59 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestReportPullFailures(t *testing.T) {
+	failures := []PullFailure{
+		{Service: "web", Build: true, Err: errors.New("pull access denied for demo_web")},
+		{Service: "db", Err: errors.New("manifest for mysql:99 not found")},
+		{Service: "cache", Err: errors.New("manifest for redis:99 not found")},
+	}
+
+	var out bytes.Buffer
+	err := ReportPullFailures(&out, failures, false)
+	assert.Error(t, err, `failed to pull 2 service image(s):
+    service "cache": manifest for redis:99 not found
+    service "db": manifest for mysql:99 not found`)
+	assert.Equal(t, out.String(), `WARNING: Some service image(s) must be built from source by running:
+    docker compose build web
+`)
+
+	out.Reset()
+	err = ReportPullFailures(&out, failures, true)
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), `WARNING: Some service image(s) must be built from source by running:
+    docker compose build web
+WARNING: Some service image(s) failed to be pulled:
+    service "cache": manifest for redis:99 not found
+    service "db": manifest for mysql:99 not found
+`)
+
+	out.Reset()
+	err = ReportPullFailures(&out, []PullFailure{{Service: "web", Build: true, Err: errors.New("pull access denied for demo_web")}}, false)
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), `WARNING: Some service image(s) must be built from source by running:
+    docker compose build web
+`)
+}
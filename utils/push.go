@@ -0,0 +1,136 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/distribution/distribution/v3/reference"
+	"github.com/docker/compose/v2/cmd/formatter"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// PushAttempts is the number of times compose push is attempted before
+	// failing, as registries may fail transiently under concurrent pushes.
+	PushAttempts = 3
+	// PushRetryDelay is the delay before the first retry of compose push,
+	// doubled on each retry
+	PushRetryDelay = 2 * time.Second
+)
+
+// PushedImage is the digest of a service image pushed to its registry
+type PushedImage struct {
+	Service string
+	Image   string
+	Digest  string
+}
+
+// PushWithRetry runs push until it succeeds, for PushAttempts times at most.
+// Pushing again is cheap as registries skip the layers they already have.
+func PushWithRetry(ctx context.Context, push func(ctx context.Context) error) error {
+	delay := PushRetryDelay
+	var err error
+	for attempt := 1; attempt <= PushAttempts; attempt++ {
+		if err = push(ctx); err == nil || ctx.Err() != nil {
+			return err
+		}
+		if attempt == PushAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+	return errors.Wrapf(err, "failed to push after %d attempts", PushAttempts)
+}
+
+// PushedImages returns the registry digests of the images compose push
+// pushes, i.e. the ones of the services with both an image and a build
+// section. With --ignore-push-failures, the images without a digest, which
+// may have failed to be pushed, are skipped.
+func PushedImages(ctx context.Context, apiClient client.ImageAPIClient, project *types.Project, options api.PushOptions) ([]PushedImage, error) {
+	var pushed []PushedImage
+	for _, service := range project.Services {
+		if service.Build == nil || service.Image == "" {
+			continue
+		}
+		digest, err := imageDigest(ctx, apiClient, service.Image)
+		if err != nil {
+			if options.IgnoreFailures {
+				logrus.Warnf("service %q: %v", service.Name, err)
+				continue
+			}
+			return nil, err
+		}
+		pushed = append(pushed, PushedImage{
+			Service: service.Name,
+			Image:   service.Image,
+			Digest:  digest,
+		})
+	}
+	return pushed, nil
+}
+
+func imageDigest(ctx context.Context, apiClient client.ImageAPIClient, image string) (string, error) {
+	inspect, _, err := apiClient.ImageInspectWithRaw(ctx, image)
+	if err != nil {
+		return "", err
+	}
+	return repoDigest(image, inspect.RepoDigests)
+}
+
+// PrintPushedImages prints the pushed image digests as a table
+func PrintPushedImages(out io.Writer, images []PushedImage) error {
+	if len(images) == 0 {
+		return nil
+	}
+	return formatter.PrintPrettySection(out, func(w io.Writer) {
+		for _, image := range images {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", image.Service, image.Image, image.Digest)
+		}
+	}, "SERVICE", "IMAGE", "DIGEST")
+}
+
+// repoDigest returns the digest of the image in the repository it is pushed
+// to, among the ones the engine knows of.
+func repoDigest(image string, repoDigests []string) (string, error) {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", err
+	}
+	for _, repoDigest := range repoDigests {
+		digested, err := reference.ParseNormalizedNamed(repoDigest)
+		if err != nil {
+			continue
+		}
+		if d, ok := digested.(reference.Digested); ok && digested.Name() == ref.Name() {
+			return d.Digest().String(), nil
+		}
+	}
+	return "", fmt.Errorf("no digest found for image %s in repository %s", image, reference.FamiliarName(ref))
+}
@@ -0,0 +1,110 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/compose/v2/pkg/api"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"gotest.tools/v3/assert"
+)
+
+const testDigest = "sha256:4424e31f2c366108433ecca7890ad527b243361577180dfd9a5bb36e828abf47"
+
+func TestPushWithRetry(t *testing.T) {
+	defer func(delay time.Duration) { PushRetryDelay = delay }(PushRetryDelay)
+	PushRetryDelay = time.Millisecond
+
+	attempts := 0
+	err := PushWithRetry(context.Background(), func(ctx context.Context) error {
+		attempts++
+		if attempts < 2 {
+			return errors.New("received unexpected HTTP status: 502 Bad Gateway")
+		}
+		return nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, attempts, 2)
+
+	attempts = 0
+	err = PushWithRetry(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return errors.New("denied: requested access to the resource is denied")
+	})
+	assert.Error(t, err, "failed to push after 3 attempts: denied: requested access to the resource is denied")
+	assert.Equal(t, attempts, PushAttempts)
+}
+
+func TestRepoDigest(t *testing.T) {
+	digest, err := repoDigest("demo/app:1.0", []string{
+		"registry.example.com/demo/app@" + testDigest,
+		"demo/app@" + testDigest,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, digest, testDigest)
+
+	digest, err = repoDigest("registry.example.com/demo/app", []string{"registry.example.com/demo/app@" + testDigest})
+	assert.NilError(t, err)
+	assert.Equal(t, digest, testDigest)
+
+	_, err = repoDigest("demo/app", []string{"registry.example.com/demo/app@" + testDigest})
+	assert.Error(t, err, "no digest found for image demo/app in repository demo/app")
+}
+
+type fakeImageClient struct {
+	client.ImageAPIClient
+	repoDigests map[string][]string
+}
+
+func (c fakeImageClient) ImageInspectWithRaw(_ context.Context, image string) (moby.ImageInspect, []byte, error) {
+	return moby.ImageInspect{RepoDigests: c.repoDigests[image]}, nil, nil
+}
+
+func TestPushedImages(t *testing.T) {
+	apiClient := fakeImageClient{repoDigests: map[string][]string{
+		"demo/app": {"demo/app@" + testDigest},
+	}}
+	project := &types.Project{
+		Services: types.Services{
+			{Name: "app", Image: "demo/app", Build: &types.BuildConfig{Context: "."}},
+			{Name: "failed", Image: "demo/failed", Build: &types.BuildConfig{Context: "."}},
+			{Name: "db", Image: "postgres"},
+		},
+	}
+
+	_, err := PushedImages(context.Background(), apiClient, project, api.PushOptions{})
+	assert.Error(t, err, "no digest found for image demo/failed in repository demo/failed")
+
+	pushed, err := PushedImages(context.Background(), apiClient, project, api.PushOptions{IgnoreFailures: true})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, pushed, []PushedImage{{Service: "app", Image: "demo/app", Digest: testDigest}})
+}
+
+func TestPrintPushedImages(t *testing.T) {
+	var out bytes.Buffer
+	err := PrintPushedImages(&out, []PushedImage{{Service: "app", Image: "demo/app:1.0", Digest: testDigest}})
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), "SERVICE             IMAGE               DIGEST\n"+
+		"app                 demo/app:1.0        "+testDigest+"\n")
+}
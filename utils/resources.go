@@ -0,0 +1,74 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"strconv"
+
+	"github.com/compose-spec/compose-go/types"
+)
+
+// ResourcePidsExtension is the extension the pids attribute of the deploy
+// resources is moved to when resolving compose files, as the compose loader
+// rejects it. It holds the maximum number of processes of the containers.
+const ResourcePidsExtension = "x-pids"
+
+// ResourcePids returns the number of processes the resource sets, if any
+func ResourcePids(resource *types.Resource) (int64, bool) {
+	if resource == nil {
+		return 0, false
+	}
+	switch pids := resource.Extensions[ResourcePidsExtension].(type) {
+	case int:
+		return int64(pids), true
+	case int64:
+		return pids, true
+	case uint64:
+		return int64(pids), true
+	case float64:
+		return int64(pids), true
+	case string:
+		n, err := strconv.ParseInt(pids, 10, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// moveResourcePids moves the pids attribute of the deploy resources of the
+// services of the compose model to the ResourcePidsExtension and reports
+// whether any service sets one.
+func moveResourcePids(dict map[string]interface{}) bool {
+	services, _ := dict["services"].(map[string]interface{})
+	var moved bool
+	for _, s := range services {
+		service, _ := s.(map[string]interface{})
+		deploy, _ := service["deploy"].(map[string]interface{})
+		resources, _ := deploy["resources"].(map[string]interface{})
+		for _, key := range []string{"limits", "reservations"} {
+			resource, ok := resources[key].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if pids, ok := resource["pids"]; ok {
+				resource[ResourcePidsExtension] = pids
+				delete(resource, "pids")
+				moved = true
+			}
+		}
+	}
+	return moved
+}
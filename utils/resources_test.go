@@ -0,0 +1,72 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/loader"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestResourcePids(t *testing.T) {
+	dir := t.TempDir()
+	compose := filepath.Join(dir, "compose.yaml")
+	writeFile(t, compose, `
+services:
+  app:
+    image: app
+    deploy:
+      resources:
+        limits:
+          cpus: "0.5"
+          pids: 100
+  worker:
+    image: worker
+    deploy:
+      resources:
+        limits:
+          pids: ${PIDS}
+`)
+
+	b, err := ResolveComposeFile(compose, nil, false)
+	assert.NilError(t, err)
+	project, err := loader.Load(types.ConfigDetails{
+		WorkingDir:  dir,
+		ConfigFiles: []types.ConfigFile{{Filename: compose, Content: b}},
+		Environment: map[string]string{"PIDS": "20"},
+	})
+	assert.NilError(t, err)
+
+	app, err := project.GetService("app")
+	assert.NilError(t, err)
+	pids, ok := ResourcePids(app.Deploy.Resources.Limits)
+	assert.Assert(t, ok)
+	assert.Equal(t, pids, int64(100))
+	assert.Equal(t, app.Deploy.Resources.Limits.NanoCPUs, "0.5")
+
+	worker, err := project.GetService("worker")
+	assert.NilError(t, err)
+	pids, ok = ResourcePids(worker.Deploy.Resources.Limits)
+	assert.Assert(t, ok)
+	assert.Equal(t, pids, int64(20))
+
+	_, ok = ResourcePids(worker.Deploy.Resources.Reservations)
+	assert.Assert(t, !ok)
+}
@@ -0,0 +1,71 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"os"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+)
+
+// SecretEnvironmentExtension is the extension the environment attribute of
+// secrets is moved to when resolving compose files, as the compose loader
+// rejects it. It holds the name of the variable the secret is sourced from.
+const SecretEnvironmentExtension = "x-environment"
+
+// SecretEnvironment returns the name of the environment variable the secret
+// is sourced from, if any
+func SecretEnvironment(secret types.SecretConfig) (string, bool) {
+	variable, ok := secret.Extensions[SecretEnvironmentExtension].(string)
+	return variable, ok && variable != ""
+}
+
+// SecretContent returns the content of the secret, the value of the project
+// environment variable it is sourced from, or the content of its file
+// otherwise.
+func SecretContent(project *types.Project, name string, secret types.SecretConfig) ([]byte, error) {
+	variable, ok := SecretEnvironment(secret)
+	if !ok {
+		return os.ReadFile(secret.File)
+	}
+	value, ok := project.Environment[variable]
+	if !ok {
+		return nil, errors.Errorf("secret %q: environment variable %s is not set", name, variable)
+	}
+	return []byte(value), nil
+}
+
+// moveSecretsEnvironment moves the environment attribute of the secrets of the
+// compose model to the SecretEnvironmentExtension and reports whether any
+// secret has one.
+func moveSecretsEnvironment(dict map[string]interface{}) bool {
+	secrets, _ := dict["secrets"].(map[string]interface{})
+	var moved bool
+	for _, s := range secrets {
+		secret, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if variable, ok := secret["environment"]; ok {
+			secret[SecretEnvironmentExtension] = variable
+			delete(secret, "environment")
+			moved = true
+		}
+	}
+	return moved
+}
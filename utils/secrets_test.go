@@ -0,0 +1,72 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/loader"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestEnvironmentSecrets(t *testing.T) {
+	dir := t.TempDir()
+	compose := filepath.Join(dir, "compose.yaml")
+	writeFile(t, compose, `
+services:
+  app:
+    image: app
+    secrets: [token, key]
+secrets:
+  token:
+    environment: TOKEN
+  key:
+    file: ./key.pem
+`)
+	writeFile(t, filepath.Join(dir, "key.pem"), "private key")
+
+	b, err := ResolveComposeFile(compose, nil, false)
+	assert.NilError(t, err)
+	env := map[string]string{"TOKEN": "s3cr3t"}
+	project, err := loader.Load(types.ConfigDetails{
+		WorkingDir:  dir,
+		ConfigFiles: []types.ConfigFile{{Filename: compose, Content: b}},
+		Environment: env,
+	}, func(options *loader.Options) {
+		options.ResolvePaths = true
+	})
+	assert.NilError(t, err)
+
+	variable, ok := SecretEnvironment(project.Secrets["token"])
+	assert.Assert(t, ok)
+	assert.Equal(t, variable, "TOKEN")
+	content, err := SecretContent(project, "token", project.Secrets["token"])
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "s3cr3t")
+
+	_, ok = SecretEnvironment(project.Secrets["key"])
+	assert.Assert(t, !ok)
+	content, err = SecretContent(project, "key", project.Secrets["key"])
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "private key")
+
+	delete(env, "TOKEN")
+	_, err = SecretContent(project, "token", project.Secrets["token"])
+	assert.Error(t, err, `secret "token": environment variable TOKEN is not set`)
+}
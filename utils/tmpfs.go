@@ -0,0 +1,73 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"strconv"
+
+	"github.com/compose-spec/compose-go/types"
+)
+
+// TmpfsModeExtension is the extension the mode option of tmpfs volumes is
+// moved to when resolving compose files, as the compose loader rejects it.
+// It holds the file mode of the mount, an octal number.
+const TmpfsModeExtension = "x-mode"
+
+// TmpfsMode returns the file mode the tmpfs options set, if any. Strings are
+// parsed as octal numbers, integers being written as octal literals in YAML.
+func TmpfsMode(tmpfs *types.ServiceVolumeTmpfs) (uint32, bool) {
+	if tmpfs == nil {
+		return 0, false
+	}
+	switch mode := tmpfs.Extensions[TmpfsModeExtension].(type) {
+	case int:
+		return uint32(mode), mode >= 0
+	case int64:
+		return uint32(mode), mode >= 0
+	case uint64:
+		return uint32(mode), true
+	case string:
+		n, err := strconv.ParseUint(mode, 8, 32)
+		return uint32(n), err == nil
+	}
+	return 0, false
+}
+
+// moveTmpfsMode moves the mode option of the tmpfs volumes of the services of
+// the compose model to the TmpfsModeExtension and reports whether any volume
+// sets one.
+func moveTmpfsMode(dict map[string]interface{}) bool {
+	services, _ := dict["services"].(map[string]interface{})
+	var moved bool
+	for _, s := range services {
+		service, _ := s.(map[string]interface{})
+		volumes, _ := service["volumes"].([]interface{})
+		for _, v := range volumes {
+			volume, _ := v.(map[string]interface{})
+			tmpfs, ok := volume["tmpfs"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if mode, ok := tmpfs["mode"]; ok {
+				tmpfs[TmpfsModeExtension] = mode
+				delete(tmpfs, "mode")
+				moved = true
+			}
+		}
+	}
+	return moved
+}
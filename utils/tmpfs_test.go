@@ -0,0 +1,72 @@
+/*
+   Copyright 2022 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/loader"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestTmpfsMode(t *testing.T) {
+	dir := t.TempDir()
+	compose := filepath.Join(dir, "compose.yaml")
+	writeFile(t, compose, `
+services:
+  app:
+    image: app
+    read_only: true
+    volumes:
+      - type: tmpfs
+        target: /run
+        tmpfs:
+          size: 64m
+          mode: 01777
+      - type: tmpfs
+        target: /tmp
+        tmpfs:
+          mode: "700"
+      - type: tmpfs
+        target: /cache
+`)
+
+	b, err := ResolveComposeFile(compose, nil, false)
+	assert.NilError(t, err)
+	project, err := loader.Load(types.ConfigDetails{
+		WorkingDir:  dir,
+		ConfigFiles: []types.ConfigFile{{Filename: compose, Content: b}},
+	})
+	assert.NilError(t, err)
+
+	app, err := project.GetService("app")
+	assert.NilError(t, err)
+	assert.Equal(t, len(app.Volumes), 3)
+	mode, ok := TmpfsMode(app.Volumes[0].Tmpfs)
+	assert.Assert(t, ok)
+	assert.Equal(t, mode, uint32(0o1777))
+	assert.Equal(t, app.Volumes[0].Tmpfs.Size, types.UnitBytes(64*1024*1024))
+
+	mode, ok = TmpfsMode(app.Volumes[1].Tmpfs)
+	assert.Assert(t, ok)
+	assert.Equal(t, mode, uint32(0o700))
+
+	_, ok = TmpfsMode(app.Volumes[2].Tmpfs)
+	assert.Assert(t, !ok)
+}
@@ -0,0 +1,50 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"strconv"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/compose/v2/pkg/utils"
+)
+
+// TopTitles are the columns of the `compose top` summaries built by
+// ContainersTop.
+var TopTitles = []string{"SERVICE", "STATE", "HEALTH", "EXIT CODE", "CMD"}
+
+// ContainersTop describes the containers of the given services, or of all
+// services if none is given, as `compose top` summaries for backends that
+// can't list the processes of a container. Each container is reported as a
+// single "process", its main command, along with its state.
+func ContainersTop(containers []api.ContainerSummary, services []string) []api.ContainerProcSummary {
+	summaries := []api.ContainerProcSummary{}
+	for _, c := range containers {
+		if len(services) > 0 && !utils.StringContains(services, c.Service) {
+			continue
+		}
+		summaries = append(summaries, api.ContainerProcSummary{
+			ID:     c.ID,
+			Name:   c.Name,
+			Titles: TopTitles,
+			Processes: [][]string{
+				{c.Service, c.State, c.Health, strconv.Itoa(c.ExitCode), c.Command},
+			},
+		})
+	}
+	return summaries
+}
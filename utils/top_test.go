@@ -0,0 +1,42 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/docker/compose/v2/pkg/api"
+	"gotest.tools/v3/assert"
+)
+
+func TestContainersTop(t *testing.T) {
+	containers := []api.ContainerSummary{
+		{ID: "1", Name: "demo-web-1", Service: "web", State: "running", Health: "healthy", Command: "nginx"},
+		{ID: "2", Name: "demo-db-1", Service: "db", State: "exited", ExitCode: 1},
+	}
+	top := ContainersTop(containers, []string{"web"})
+	assert.DeepEqual(t, top, []api.ContainerProcSummary{{
+		ID:        "1",
+		Name:      "demo-web-1",
+		Titles:    TopTitles,
+		Processes: [][]string{{"web", "running", "healthy", "0", "nginx"}},
+	}})
+
+	top = ContainersTop(containers, nil)
+	assert.Equal(t, len(top), 2)
+	assert.DeepEqual(t, top[1].Processes, [][]string{{"db", "exited", "", "1", ""}})
+}